@@ -6,7 +6,7 @@ const ReservationCard = ({ reservation, onCancel }) => {
   };
 
   const getStatusClass = (status) => {
-    if (status === 'confirmed') return 'status-confirmed';
+    if (status === 'confirmed' || status === 'checked_in' || status === 'completed') return 'status-confirmed';
     if (status === 'pending') return 'status-pending';
     return 'status-cancelled';
   };
@@ -14,6 +14,10 @@ const ReservationCard = ({ reservation, onCancel }) => {
   const getStatusText = (status) => {
     if (status === 'confirmed') return 'Confirmada';
     if (status === 'pending') return 'Pendiente';
+    if (status === 'checked_in') return 'En curso';
+    if (status === 'completed') return 'Finalizada';
+    if (status === 'no_show') return 'No se presentó';
+    if (status === 'expired') return 'Vencida';
     return 'Cancelada';
   };
 
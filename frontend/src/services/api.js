@@ -79,10 +79,12 @@ export const searchService = {
  *  HOTELS / ROOMS (hotels-api)
  * ======================= */
 export const hotelService = {
-  // GET http://localhost:8081/hotels
-  getAll: async () => {
-    const response = await fetchWithAuth(`${API_URLS.hotels}/hotels`);
-    return response.json();
+  // GET http://localhost:8081/hotels (paginado: { items, total, limit, offset })
+  getAll: async (params = {}) => {
+    const query = new URLSearchParams(params).toString();
+    const response = await fetchWithAuth(`${API_URLS.hotels}/hotels${query ? `?${query}` : ""}`);
+    const page = await response.json();
+    return page.items;
   },
 
   // GET http://localhost:8081/hotels/:id
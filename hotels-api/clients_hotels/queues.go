@@ -1,11 +1,268 @@
 ﻿package clients_hotels
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	defaultBufferSize        = 1000
+	defaultReconnectInterval = 5 * time.Second
+	defaultConfirmTimeout    = 5 * time.Second
+)
+
+// ErrBufferFull se devuelve cuando el broker no está disponible y el buffer local ya está lleno
+var ErrBufferFull = errors.New("events buffer is full")
+
 type RabbitConfig struct {
 	Host, Port, Username, Password, QueueName string
+
+	// Cantidad máxima de mensajes guardados en memoria mientras no hay broker (default 1000)
+	BufferSize int
+	// Cada cuánto se reintenta la conexión y el envío de pendientes (default 5s)
+	ReconnectInterval time.Duration
+	// Cuánto se espera el ack del broker por cada mensaje (default 5s)
+	ConfirmTimeout time.Duration
+}
+
+// Channel es lo mínimo que necesitamos del broker.
+// Publish tiene que bloquear hasta que el broker confirme el mensaje.
+type Channel interface {
+	Publish(body []byte) error
+	Close() error
+}
+
+// Dialer abre una conexión nueva al broker (en tests se reemplaza por un fake en memoria)
+type Dialer func() (Channel, error)
+
+// Rabbit publica los eventos en JSON. Publish solo los deja en un buffer acotado; una goroutine
+// los envía en orden, reconectando cuando el broker se cae, así un broker lento o caído nunca
+// frena al request que generó el evento.
+type Rabbit struct {
+	config RabbitConfig
+	dial   Dialer
+
+	mu      sync.Mutex
+	pending [][]byte
+
+	// channel lo usa solo la goroutine de run (conectar, enviar y esperar el ack van sin r.mu)
+	channel Channel
+
+	wake      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeErr  error
+	closeOnce sync.Once
+}
+
+func NewRabbit(config RabbitConfig) *Rabbit {
+	return NewRabbitWithDialer(config, dialAMQP(config))
+}
+
+func NewRabbitWithDialer(config RabbitConfig, dial Dialer) *Rabbit {
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultBufferSize
+	}
+	if config.ReconnectInterval <= 0 {
+		config.ReconnectInterval = defaultReconnectInterval
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = defaultConfirmTimeout
+	}
+
+	r := &Rabbit{
+		config:  config,
+		dial:    dial,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Publish codifica el evento en JSON y lo encola para enviarlo. Devuelve ErrBufferFull si ya hay
+// BufferSize mensajes esperando (el broker lleva un rato caído).
+func (r *Rabbit) Publish(event any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) >= r.config.BufferSize {
+		return ErrBufferFull
+	}
+	r.pending = append(r.pending, body)
+	r.notify()
+	return nil
+}
+
+// Pending devuelve cuántos mensajes esperan ser enviados (incluido el que se está enviando)
+func (r *Rabbit) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+// Close frena el envío en segundo plano y cierra la conexión. Si hay un mensaje esperando el
+// ack del broker, espera a que termine (a lo sumo ConfirmTimeout).
+func (r *Rabbit) Close() error {
+	r.closeOnce.Do(func() { close(r.done) })
+	<-r.stopped
+	return r.closeErr
+}
+
+func (r *Rabbit) notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run envía lo pendiente cada vez que Publish avisa. Si el broker no responde espera
+// ReconnectInterval antes de reintentar, para no martillar un broker caído.
+func (r *Rabbit) run() {
+	defer close(r.stopped)
+	defer func() {
+		if r.channel != nil {
+			r.closeErr = r.channel.Close()
+			r.channel = nil
+		}
+	}()
+
+	for {
+		wait := r.wake
+		if !r.flush() {
+			wait = nil
+		}
+		select {
+		case <-r.done:
+			return
+		case <-wait:
+		case <-time.After(r.config.ReconnectInterval):
+		}
+	}
+}
+
+// flush envía los pendientes en orden. r.mu se toma solo para leer y sacar mensajes del buffer;
+// un mensaje sale del buffer recién cuando el broker lo confirmó. Devuelve false si falló.
+func (r *Rabbit) flush() bool {
+	for {
+		select {
+		case <-r.done:
+			return true
+		default:
+		}
+
+		r.mu.Lock()
+		if len(r.pending) == 0 {
+			r.mu.Unlock()
+			return true
+		}
+		body := r.pending[0]
+		r.mu.Unlock()
+
+		if err := r.send(body); err != nil {
+			log.Printf("error publishing events (%d pending): %v", r.Pending(), err)
+			return false
+		}
+
+		r.mu.Lock()
+		r.pending[0] = nil
+		r.pending = r.pending[1:]
+		r.mu.Unlock()
+	}
+}
+
+// send publica un mensaje, conectando si hace falta; ante error descarta la conexión para forzar una nueva
+func (r *Rabbit) send(body []byte) error {
+	if r.channel == nil {
+		channel, err := r.dial()
+		if err != nil {
+			return err
+		}
+		r.channel = channel
+	}
+	if err := r.channel.Publish(body); err != nil {
+		_ = r.channel.Close()
+		r.channel = nil
+		return err
+	}
+	return nil
 }
 
-// Stub que cumple la interfaz y no falla si no hay Rabbit
-type Rabbit struct{}
+// amqpChannel implementa Channel sobre una conexión real con publisher confirms
+type amqpChannel struct {
+	connection *amqp.Connection
+	channel    *amqp.Channel
+	queue      string
+	confirms   chan amqp.Confirmation
+	timeout    time.Duration
+}
 
-func NewRabbit(_ RabbitConfig) *Rabbit { return &Rabbit{} }
-func (r *Rabbit) Publish(_ any) error  { return nil }
+func dialAMQP(config RabbitConfig) Dialer {
+	return func() (Channel, error) {
+		connection, err := amqp.Dial(fmt.Sprintf("amqp://%s:%s@%s:%s/", config.Username, config.Password, config.Host, config.Port))
+		if err != nil {
+			return nil, fmt.Errorf("error getting Rabbit connection: %w", err)
+		}
+		channel, err := connection.Channel()
+		if err != nil {
+			_ = connection.Close()
+			return nil, fmt.Errorf("error creating Rabbit channel: %w", err)
+		}
+		if _, err := channel.QueueDeclare(config.QueueName, true, false, false, false, nil); err != nil {
+			_ = connection.Close()
+			return nil, fmt.Errorf("error declaring Rabbit queue: %w", err)
+		}
+		if err := channel.Confirm(false); err != nil {
+			_ = connection.Close()
+			return nil, fmt.Errorf("error enabling publisher confirms: %w", err)
+		}
+		return &amqpChannel{
+			connection: connection,
+			channel:    channel,
+			queue:      config.QueueName,
+			confirms:   channel.NotifyPublish(make(chan amqp.Confirmation, 1)),
+			timeout:    config.ConfirmTimeout,
+		}, nil
+	}
+}
+
+func (c *amqpChannel) Publish(body []byte) error {
+	err := c.channel.Publish("", c.queue, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing message: %w", err)
+	}
+
+	select {
+	case confirm, ok := <-c.confirms:
+		if !ok {
+			return errors.New("channel closed before confirmation")
+		}
+		if !confirm.Ack {
+			return errors.New("message rejected by broker")
+		}
+		return nil
+	case <-time.After(c.timeout):
+		return errors.New("timeout waiting for broker confirmation")
+	}
+}
+
+func (c *amqpChannel) Close() error {
+	_ = c.channel.Close()
+	return c.connection.Close()
+}
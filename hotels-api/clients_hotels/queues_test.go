@@ -0,0 +1,183 @@
+package clients_hotels_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	queues "hotels/clients_hotels"
+)
+
+// fakeBroker simula el broker en memoria: guarda lo publicado y se puede "apagar"
+type fakeBroker struct {
+	mu       sync.Mutex
+	down     bool
+	received [][]byte
+	dials    int
+
+	// si no es nil, cada Publish espera a que se cierre (broker lento con los confirms)
+	confirm chan struct{}
+}
+
+func (b *fakeBroker) setDown(down bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.down = down
+}
+
+func (b *fakeBroker) messages() [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][]byte(nil), b.received...)
+}
+
+func (b *fakeBroker) dial() (queues.Channel, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dials++
+	if b.down {
+		return nil, errors.New("connection refused")
+	}
+	return &fakeChannel{broker: b}, nil
+}
+
+type fakeChannel struct {
+	broker *fakeBroker
+}
+
+func (c *fakeChannel) Publish(body []byte) error {
+	if c.broker.confirm != nil {
+		<-c.broker.confirm
+	}
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+	if c.broker.down {
+		return errors.New("connection lost")
+	}
+	c.broker.received = append(c.broker.received, body)
+	return nil
+}
+
+func (c *fakeChannel) Close() error { return nil }
+
+func newRabbit(broker *fakeBroker, bufferSize int) *queues.Rabbit {
+	return queues.NewRabbitWithDialer(queues.RabbitConfig{
+		QueueName:         "hotels-news",
+		BufferSize:        bufferSize,
+		ReconnectInterval: 10 * time.Millisecond,
+	}, broker.dial)
+}
+
+func TestRabbit(t *testing.T) {
+
+	t.Run("Publish - Encodes event as JSON", func(t *testing.T) {
+		broker := &fakeBroker{}
+		rabbit := newRabbit(broker, 10)
+		defer rabbit.Close()
+
+		err := rabbit.Publish(map[string]any{"op": "create", "id": "h1"})
+
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool { return rabbit.Pending() == 0 }, time.Second, 5*time.Millisecond)
+		msgs := broker.messages()
+		if assert.Len(t, msgs, 1) {
+			var decoded map[string]any
+			assert.NoError(t, json.Unmarshal(msgs[0], &decoded))
+			assert.Equal(t, "create", decoded["op"])
+			assert.Equal(t, "h1", decoded["id"])
+		}
+	})
+
+	t.Run("Publish - Buffers while broker is down and flushes in order", func(t *testing.T) {
+		broker := &fakeBroker{down: true}
+		rabbit := newRabbit(broker, 10)
+		defer rabbit.Close()
+
+		assert.NoError(t, rabbit.Publish("first"))
+		assert.NoError(t, rabbit.Publish("second"))
+		assert.Equal(t, 2, rabbit.Pending())
+		assert.Empty(t, broker.messages())
+
+		broker.setDown(false)
+
+		assert.Eventually(t, func() bool { return rabbit.Pending() == 0 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, [][]byte{[]byte(`"first"`), []byte(`"second"`)}, broker.messages())
+	})
+
+	t.Run("Publish - Rejects events when the buffer is full", func(t *testing.T) {
+		broker := &fakeBroker{down: true}
+		rabbit := newRabbit(broker, 1)
+		defer rabbit.Close()
+
+		assert.NoError(t, rabbit.Publish("first"))
+		err := rabbit.Publish("second")
+
+		assert.ErrorIs(t, err, queues.ErrBufferFull)
+		assert.Equal(t, 1, rabbit.Pending())
+	})
+
+	t.Run("Publish - Reconnects after losing the connection", func(t *testing.T) {
+		broker := &fakeBroker{}
+		rabbit := newRabbit(broker, 10)
+		defer rabbit.Close()
+
+		assert.NoError(t, rabbit.Publish("before"))
+
+		broker.setDown(true)
+		assert.NoError(t, rabbit.Publish("during"))
+		broker.setDown(false)
+
+		assert.Eventually(t, func() bool { return rabbit.Pending() == 0 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, [][]byte{[]byte(`"before"`), []byte(`"during"`)}, broker.messages())
+	})
+
+	t.Run("Publish - Does not wait for a slow broker", func(t *testing.T) {
+		broker := &fakeBroker{confirm: make(chan struct{})}
+		rabbit := newRabbit(broker, 10)
+		defer rabbit.Close()
+
+		start := time.Now()
+		assert.NoError(t, rabbit.Publish("first"))
+		assert.NoError(t, rabbit.Publish("second"))
+		assert.NoError(t, rabbit.Publish("third"))
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+		assert.Equal(t, 3, rabbit.Pending())
+
+		close(broker.confirm)
+
+		assert.Eventually(t, func() bool { return rabbit.Pending() == 0 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, [][]byte{[]byte(`"first"`), []byte(`"second"`), []byte(`"third"`)}, broker.messages())
+	})
+
+	t.Run("Publish - Does not wait for a slow connection", func(t *testing.T) {
+		broker := &fakeBroker{}
+		dialing := make(chan struct{})
+		rabbit := queues.NewRabbitWithDialer(queues.RabbitConfig{ReconnectInterval: 10 * time.Millisecond}, func() (queues.Channel, error) {
+			<-dialing
+			return broker.dial()
+		})
+		defer rabbit.Close()
+
+		start := time.Now()
+		assert.NoError(t, rabbit.Publish("first"))
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+		close(dialing)
+		assert.Eventually(t, func() bool { return rabbit.Pending() == 0 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Publish - Fails on events that cannot be encoded", func(t *testing.T) {
+		broker := &fakeBroker{}
+		rabbit := newRabbit(broker, 10)
+		defer rabbit.Close()
+
+		err := rabbit.Publish(make(chan int))
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, rabbit.Pending())
+	})
+}
@@ -0,0 +1,117 @@
+package clients_hotels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultReservationsTimeout = 5 * time.Second
+
+type ReservationsConfig struct {
+	Host    string
+	Port    string
+	Timeout time.Duration // default 5s
+}
+
+// Reservation: lo que nos interesa de una reserva de reservations-api
+type Reservation struct {
+	ID       string    `json:"id"`
+	HotelID  string    `json:"hotel_id"`
+	UserID   string    `json:"user_id"`
+	CheckIn  time.Time `json:"check_in"`
+	CheckOut time.Time `json:"check_out"`
+	Status   string    `json:"status"`
+	RoomType string    `json:"room_type"`
+}
+
+// Reservations consulta reservations-api por HTTP
+type Reservations struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewReservations(cfg ReservationsConfig) *Reservations {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultReservationsTimeout
+	}
+	return &Reservations{
+		baseURL: fmt.Sprintf("http://%s:%s", cfg.Host, cfg.Port),
+		client:  &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// HasFutureReservations indica si el hotel tiene reservas activas (pendientes, confirmadas o con el
+// huésped adentro) que todavía no terminaron
+func (r *Reservations) HasFutureReservations(ctx context.Context, hotelID string) (bool, error) {
+	return r.hasFuture(ctx, hotelID, func(Reservation) bool { return true })
+}
+
+// HasFutureRoomReservations es lo mismo pero solo para un tipo de habitación
+func (r *Reservations) HasFutureRoomReservations(ctx context.Context, hotelID string, roomID string) (bool, error) {
+	return r.hasFuture(ctx, hotelID, func(res Reservation) bool { return res.RoomType == roomID })
+}
+
+func (r *Reservations) hasFuture(ctx context.Context, hotelID string, match func(Reservation) bool) (bool, error) {
+	reservations, err := r.byHotel(ctx, hotelID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, res := range reservations {
+		if activeStatus(res.Status) && res.CheckOut.After(now) && match(res) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// activeStatus: la reserva sigue ocupando habitaciones (pendiente, confirmada o con el huésped adentro)
+func activeStatus(status string) bool {
+	return status == "pending" || status == "confirmed" || status == "checked_in"
+}
+
+// CompletedStay devuelve el id de una reserva completada del usuario en el hotel ("" si no tiene
+// ninguna). Sirve para habilitar la reseña: una reserva confirmada con el check-out vencido puede
+// ser un no-show, así que solo cuenta el estado completed.
+func (r *Reservations) CompletedStay(ctx context.Context, hotelID string, userID string) (string, error) {
+	reservations, err := r.byHotel(ctx, hotelID)
+	if err != nil {
+		return "", err
+	}
+	for _, res := range reservations {
+		if res.UserID != userID {
+			continue
+		}
+		if res.Status == "completed" {
+			return res.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *Reservations) byHotel(ctx context.Context, hotelID string) ([]Reservation, error) {
+	endpoint := r.baseURL + "/reservations?hotel_id=" + url.QueryEscape(hotelID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building reservations request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reservations for hotel %s: %w", hotelID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch reservations for hotel %s: status %d", hotelID, resp.StatusCode)
+	}
+
+	var reservations []Reservation
+	if err := json.NewDecoder(resp.Body).Decode(&reservations); err != nil {
+		return nil, fmt.Errorf("error decoding reservations for hotel %s: %w", hotelID, err)
+	}
+	return reservations, nil
+}
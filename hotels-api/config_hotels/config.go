@@ -0,0 +1,69 @@
+﻿package config_hotels
+
+import (
+	"os"
+	"strings"
+)
+
+// Backends posibles para HOTELS_REPOSITORY
+const (
+	RepositoryMongo  = "mongo"
+	RepositoryMemory = "memory"
+)
+
+// Config de hotels-api; todo sale de variables de entorno con defaults para docker-compose
+type Config struct {
+	Port       string
+	Repository string // "mongo" (default) o "memory"
+	SeedFile   string // hoteles de ejemplo que se cargan al arrancar ("" para no cargar nada)
+	ImagesDir  string // raíz del blob store local de imágenes
+	JWTKey     string // misma clave con la que users-api firma los tokens
+
+	Mongo Mongo
+
+	ReservationsHost string
+	ReservationsPort string
+}
+
+type Mongo struct {
+	Host       string
+	Port       string
+	Username   string
+	Password   string
+	Database   string
+	Collection string
+
+	ReviewsCollection string
+	AuditCollection   string
+}
+
+// Load lee la configuración del entorno
+func Load() Config {
+	return Config{
+		Port:       getEnv("HOTELS_PORT", "8081"),
+		Repository: strings.ToLower(getEnv("HOTELS_REPOSITORY", RepositoryMongo)),
+		SeedFile:   getEnv("HOTELS_SEED_FILE", "db/hotels.json"),
+		ImagesDir:  getEnv("HOTELS_IMAGES_DIR", "data/images"),
+		JWTKey:     getEnv("JWT_KEY", "ThisIsAnExampleJWTKey!"),
+		Mongo: Mongo{
+			Host:       getEnv("MONGO_HOST", "mongo"),
+			Port:       getEnv("MONGO_PORT", "27017"),
+			Username:   getEnv("MONGO_USERNAME", "root"),
+			Password:   getEnv("MONGO_PASSWORD", "root"),
+			Database:   getEnv("MONGO_DATABASE", "hotels"),
+			Collection: getEnv("MONGO_COLLECTION", "hotels"),
+
+			ReviewsCollection: getEnv("MONGO_REVIEWS_COLLECTION", "reviews"),
+			AuditCollection:   getEnv("MONGO_AUDIT_COLLECTION", "hotel_history"),
+		},
+		ReservationsHost: getEnv("RESERVATIONS_HOST", "reservations-api"),
+		ReservationsPort: getEnv("RESERVATIONS_PORT", "8086"),
+	}
+}
+
+func getEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	return fallback
+}
@@ -0,0 +1,171 @@
+package controllers_hotels_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+)
+
+const newHotel = `{"name":"Sheraton","city":"Córdoba","price_per_night":1000,"stars":4}`
+
+func TestRequireAuth(t *testing.T) {
+	create := func(authorization string) (int, string) {
+		router, _ := newRouter(t)
+		headers := map[string]string{"Content-Type": "application/json"}
+		if authorization != "" {
+			headers["Authorization"] = authorization
+		}
+		recorder := do(router, http.MethodPost, "/createHotel", newHotel, headers)
+		return recorder.Code, recorder.Body.String()
+	}
+
+	t.Run("RequireAuth - Valid Token", func(t *testing.T) {
+		router, _ := newRouter(t)
+
+		recorder := do(router, http.MethodPost, "/createHotel", newHotel, map[string]string{"Authorization": bearer(t, 7, false)})
+
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		var out domain_hotels.Hotel
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &out))
+		assert.Equal(t, "7", out.OwnerID) // el dueño sale del token
+	})
+
+	t.Run("RequireAuth - Missing Header", func(t *testing.T) {
+		valid := token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(7, false, time.Hour))
+		for _, authorization := range []string{"", "Bearer ", "Bearer    ", valid, "Basic " + valid} {
+			code, body := create(authorization)
+			assert.Equal(t, http.StatusUnauthorized, code, authorization)
+			assert.Equal(t, domain_hotels.ErrUnauthorized.Error(), body, authorization)
+		}
+	})
+
+	t.Run("RequireAuth - Expired Token", func(t *testing.T) {
+		code, body := create("Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(7, false, -time.Minute)))
+
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid token", body)
+	})
+
+	t.Run("RequireAuth - Wrong Key Or Signing Method", func(t *testing.T) {
+		tokens := map[string]string{
+			"wrong key": token(t, jwt.SigningMethodHS256, []byte("other-key"), claims(7, false, time.Hour)),
+			"HS512":     token(t, jwt.SigningMethodHS512, []byte(jwtKey), claims(7, false, time.Hour)),
+			"none":      token(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(7, false, time.Hour)),
+			"garbage":   "not.a.token",
+		}
+		for name, value := range tokens {
+			code, _ := create("Bearer " + value)
+			assert.Equal(t, http.StatusUnauthorized, code, name)
+		}
+	})
+
+	t.Run("RequireAuth - Invalid Claims", func(t *testing.T) {
+		noExpiration := claims(7, false, time.Hour)
+		delete(noExpiration, "expiration_date")
+		numericExpiration := claims(7, false, time.Hour)
+		numericExpiration["expiration_date"] = time.Now().Add(time.Hour).Unix()
+		noUser := claims(7, false, time.Hour)
+		delete(noUser, "user_id")
+		textUser := claims(7, false, time.Hour)
+		textUser["user_id"] = "7"
+
+		for name, c := range map[string]jwt.MapClaims{
+			"no expiration_date":      noExpiration,
+			"numeric expiration_date": numericExpiration,
+			"no user_id":              noUser,
+			"user_id as text":         textUser,
+			"user_id zero":            claims(0, false, time.Hour),
+		} {
+			code, _ := create("Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), c))
+			assert.Equal(t, http.StatusUnauthorized, code, name)
+		}
+	})
+}
+
+func TestAuthorization(t *testing.T) {
+	patch := func(t *testing.T, authorization string) (int, domain_hotels.Hotel) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+		headers := map[string]string{"Authorization": authorization, "Content-Type": "application/merge-patch+json"}
+
+		recorder := do(router, http.MethodPatch, "/hotels/"+h.ID, `{"stars":5}`, headers)
+
+		out, err := repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		return recorder.Code, out
+	}
+
+	t.Run("Patch - Owner Can Edit", func(t *testing.T) {
+		code, out := patch(t, bearer(t, 7, false))
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 5, out.Stars)
+	})
+
+	t.Run("Patch - Admin Can Edit Any Hotel", func(t *testing.T) {
+		code, out := patch(t, bearer(t, 1, true))
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 5, out.Stars)
+	})
+
+	t.Run("Patch - Other Users Cannot", func(t *testing.T) {
+		code, out := patch(t, bearer(t, 8, false))
+
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, 4, out.Stars)
+	})
+
+	t.Run("Patch - A Non Boolean Admin Claim Is Not Admin", func(t *testing.T) {
+		c := claims(8, false, time.Hour)
+		c["admin"] = "true"
+
+		code, _ := patch(t, "Bearer "+token(t, jwt.SigningMethodHS256, []byte(jwtKey), c))
+
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("Archive - Non Owner Is Forbidden", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+
+		recorder := do(router, http.MethodDelete, "/hotels/"+h.ID, "", map[string]string{"Authorization": bearer(t, 8, false)})
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("Delete - Only Admins", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+
+		owner := do(router, http.MethodDelete, "/admin/hotels/"+h.ID, "", map[string]string{"Authorization": bearer(t, 7, false)})
+		assert.Equal(t, http.StatusForbidden, owner.Code)
+
+		admin := do(router, http.MethodDelete, "/admin/hotels/"+h.ID, "", map[string]string{"Authorization": bearer(t, 1, true)})
+		assert.Equal(t, http.StatusNoContent, admin.Code)
+		_, err := repo.GetByID(context.Background(), h.ID)
+		assert.ErrorIs(t, err, domain_hotels.ErrNotFound)
+	})
+
+	t.Run("Create - Only Admins Create For Someone Else", func(t *testing.T) {
+		router, _ := newRouter(t)
+		body := `{"name":"Sheraton","city":"Córdoba","price_per_night":1000,"stars":4,"owner_id":"9"}`
+
+		owner := do(router, http.MethodPost, "/createHotel", body, map[string]string{"Authorization": bearer(t, 7, false)})
+		admin := do(router, http.MethodPost, "/createHotel", body, map[string]string{"Authorization": bearer(t, 1, true)})
+
+		var fromOwner, fromAdmin domain_hotels.Hotel
+		require.NoError(t, json.Unmarshal(owner.Body.Bytes(), &fromOwner))
+		require.NoError(t, json.Unmarshal(admin.Body.Bytes(), &fromAdmin))
+		assert.Equal(t, "7", fromOwner.OwnerID)
+		assert.Equal(t, "9", fromAdmin.OwnerID)
+	})
+}
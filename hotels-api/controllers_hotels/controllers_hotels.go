@@ -2,10 +2,16 @@
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"hotels/domain_hotels"
+	"hotels/services_hotels"
+	"hotels/storage_hotels"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,9 +19,37 @@ import (
 // Esta interfaz la satisface services_hotels.Service
 type Service interface {
 	GetByID(ctx context.Context, id string) (domain_hotels.Hotel, error)
-	List(ctx context.Context, q string) ([]domain_hotels.Hotel, error)
+	List(ctx context.Context, q domain_hotels.HotelQuery) (domain_hotels.HotelPage, error)
+	ListMine(ctx context.Context) ([]domain_hotels.Hotel, error)
+	Import(ctx context.Context, format string, r io.Reader, dryRun bool) (domain_hotels.ImportReport, error)
+	Export(ctx context.Context, format string, w io.Writer) error
 	Create(ctx context.Context, h domain_hotels.Hotel) (domain_hotels.Hotel, error)
-	Update(ctx context.Context, id string, h domain_hotels.Hotel) (domain_hotels.Hotel, error)
+	Update(ctx context.Context, id string, h domain_hotels.Hotel, expectedVersion int64) (domain_hotels.Hotel, error)
+	Patch(ctx context.Context, id string, patch []byte, expectedVersion int64) (domain_hotels.Hotel, error)
+	Archive(ctx context.Context, id string) (domain_hotels.Hotel, error)
+	Delete(ctx context.Context, id string) error
+	History(ctx context.Context, id string, limit int, offset int) (domain_hotels.AuditPage, error)
+
+	ListRooms(ctx context.Context, hotelID string) ([]domain_hotels.RoomType, error)
+	GetRoom(ctx context.Context, hotelID string, roomID string) (domain_hotels.RoomType, error)
+	CreateRoom(ctx context.Context, hotelID string, room domain_hotels.RoomType) (domain_hotels.RoomType, error)
+	UpdateRoom(ctx context.Context, hotelID string, roomID string, room domain_hotels.RoomType) (domain_hotels.RoomType, error)
+	DeleteRoom(ctx context.Context, hotelID string, roomID string) error
+
+	Quote(ctx context.Context, hotelID string, req services_hotels.QuoteRequest) (domain_hotels.Quote, error)
+	UpdatePricing(ctx context.Context, hotelID string, pricing domain_hotels.PricingRules) (domain_hotels.PricingRules, error)
+
+	ListImages(ctx context.Context, hotelID string) ([]domain_hotels.Image, error)
+	AddImage(ctx context.Context, hotelID string, data []byte) (domain_hotels.Image, error)
+	DeleteImage(ctx context.Context, hotelID string, imageID string) error
+	ReorderImages(ctx context.Context, hotelID string, ids []string) ([]domain_hotels.Image, error)
+	GetImageBlob(ctx context.Context, key string) (storage_hotels.Blob, error)
+
+	ListReviews(ctx context.Context, hotelID string, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error)
+	CreateReview(ctx context.Context, hotelID string, in domain_hotels.Review) (domain_hotels.Review, error)
+	DeleteReview(ctx context.Context, hotelID string, reviewID string) error
+	ListReviewsForModeration(ctx context.Context, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error)
+	ModerateReview(ctx context.Context, reviewID string, status string, note string) (domain_hotels.Review, error)
 }
 
 type Controller struct {
@@ -24,6 +58,22 @@ type Controller struct {
 
 func NewController(s Service) *Controller { return &Controller{service: s} }
 
+const correlationIDHeader = "X-Correlation-ID"
+
+// CorrelationID toma el header X-Correlation-ID (o genera uno) y lo deja en el contexto
+// del request, así los eventos publicados se pueden seguir entre servicios.
+func CorrelationID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := strings.TrimSpace(ctx.GetHeader(correlationIDHeader))
+		if id == "" {
+			id = domain_hotels.NewCorrelationID()
+		}
+		ctx.Header(correlationIDHeader, id)
+		ctx.Request = ctx.Request.WithContext(domain_hotels.WithCorrelationID(ctx.Request.Context(), id))
+		ctx.Next()
+	}
+}
+
 // GET /hotels/:id
 func (c *Controller) GetHotelByID(ctx *gin.Context) {
 	id := strings.TrimSpace(ctx.Param("id"))
@@ -32,20 +82,97 @@ func (c *Controller) GetHotelByID(ctx *gin.Context) {
 		ctx.String(http.StatusNotFound, "not found")
 		return
 	}
+	setETag(ctx, h)
 	ctx.JSON(http.StatusOK, h)
 }
 
-// GET /hotels?q=...
+// GET /hotels?q=&city=&min_stars=&max_price=&amenity=&sort=price|-price|stars|name&limit=&offset=
 func (c *Controller) GetHotels(ctx *gin.Context) {
-	q := ctx.Query("q")
-	list, err := c.service.List(ctx.Request.Context(), q)
+	q, err := parseHotelQuery(ctx)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := c.service.List(ctx.Request.Context(), q)
 	if err != nil {
+		if errors.Is(err, domain_hotels.ErrInvalidQuery) {
+			ctx.String(http.StatusBadRequest, err.Error())
+			return
+		}
 		ctx.String(http.StatusInternalServerError, err.Error())
 		return
 	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+func parseHotelQuery(ctx *gin.Context) (domain_hotels.HotelQuery, error) {
+	q := domain_hotels.HotelQuery{
+		Q:       ctx.Query("q"),
+		City:    ctx.Query("city"),
+		Amenity: ctx.Query("amenity"),
+	}
+	q.Sort, q.Desc = domain_hotels.ParseSort(ctx.Query("sort"))
+
+	var err error
+	if q.MinStars, err = intParam(ctx, "min_stars"); err != nil {
+		return q, err
+	}
+	if q.Limit, err = intParam(ctx, "limit"); err != nil {
+		return q, err
+	}
+	if q.Offset, err = intParam(ctx, "offset"); err != nil {
+		return q, err
+	}
+	if value := strings.TrimSpace(ctx.Query("max_price")); value != "" {
+		if q.MaxPrice, err = strconv.ParseFloat(value, 64); err != nil {
+			return q, fmt.Errorf("%w: max_price must be a number", domain_hotels.ErrInvalidQuery)
+		}
+	}
+	return q, nil
+}
+
+func intParam(ctx *gin.Context, name string) (int, error) {
+	value := strings.TrimSpace(ctx.Query(name))
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be an integer", domain_hotels.ErrInvalidQuery, name)
+	}
+	return n, nil
+}
+
+// GET /me/hotels
+func (c *Controller) GetMyHotels(ctx *gin.Context) {
+	list, err := c.service.ListMine(ctx.Request.Context())
+	if err != nil {
+		ctx.String(hotelErrorStatus(err), err.Error())
+		return
+	}
 	ctx.JSON(http.StatusOK, list)
 }
 
+// GET /hotels/:id/history?limit=&offset= (dueño o admin)
+func (c *Controller) History(ctx *gin.Context) {
+	limit, err := intParam(ctx, "limit")
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, err := intParam(ctx, "offset")
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := c.service.History(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), limit, offset)
+	if err != nil {
+		ctx.String(hotelErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
 // POST /createHotel
 func (c *Controller) Create(ctx *gin.Context) {
 	var in domain_hotels.Hotel
@@ -55,17 +182,20 @@ func (c *Controller) Create(ctx *gin.Context) {
 	}
 
 	// Validaciones mínimas
-	if strings.TrimSpace(in.Name) == "" ||
-		strings.TrimSpace(in.City) == "" ||
-		in.PricePerNight <= 0 ||
-		in.Stars < 1 || in.Stars > 5 {
+	if !in.Valid() {
 		ctx.String(http.StatusBadRequest, "invalid hotel payload")
 		return
 	}
+	for _, room := range in.Rooms {
+		if !room.Valid() {
+			ctx.String(http.StatusBadRequest, "invalid room payload")
+			return
+		}
+	}
 
 	out, err := c.service.Create(ctx.Request.Context(), in)
 	if err != nil {
-		ctx.String(http.StatusInternalServerError, err.Error())
+		ctx.String(hotelErrorStatus(err), err.Error())
 		return
 	}
 	ctx.JSON(http.StatusCreated, out)
@@ -98,11 +228,131 @@ func (c *Controller) Update(ctx *gin.Context) {
 		ctx.String(http.StatusBadRequest, "invalid hotel payload")
 		return
 	}
+	if !in.ValidLocation() {
+		ctx.String(http.StatusBadRequest, "invalid hotel payload")
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		ctx.String(http.StatusPreconditionFailed, domain_hotels.ErrVersionConflict.Error())
+		return
+	}
+	out, err := c.service.Update(ctx.Request.Context(), id, in, version)
+	if err != nil {
+		ctx.String(hotelErrorStatus(err), err.Error())
+		return
+	}
+	setETag(ctx, out)
+	ctx.JSON(http.StatusOK, out)
+}
+
+// PATCH /hotels/:id (JSON Merge Patch, RFC 7386)
+func (c *Controller) Patch(ctx *gin.Context) {
+	id := strings.TrimSpace(ctx.Param("id"))
 
-	out, err := c.service.Update(ctx.Request.Context(), id, in)
+	contentType := ctx.ContentType()
+	if contentType != "application/merge-patch+json" && contentType != "application/json" {
+		ctx.String(http.StatusUnsupportedMediaType, "use application/merge-patch+json")
+		return
+	}
+	patch, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxPatchSize))
 	if err != nil {
-		ctx.String(http.StatusInternalServerError, err.Error())
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		ctx.String(http.StatusPreconditionFailed, domain_hotels.ErrVersionConflict.Error())
+		return
+	}
+	out, err := c.service.Patch(ctx.Request.Context(), id, patch, version)
+	if err != nil {
+		ctx.String(hotelErrorStatus(err), err.Error())
+		return
+	}
+	setETag(ctx, out)
+	ctx.JSON(http.StatusOK, out)
+}
+
+const maxPatchSize = 64 << 10
+
+// El ETag de un hotel es su versión
+func setETag(ctx *gin.Context, h domain_hotels.Hotel) {
+	ctx.Header("ETag", strconv.Quote(strconv.FormatInt(h.Version, 10)))
+}
+
+// ifMatchVersion lee If-Match: sin header (o "*") no hay versión esperada (0). Un ETag que no
+// es una versión nuestra, o uno débil, no puede coincidir y devuelve false.
+func ifMatchVersion(ctx *gin.Context) (int64, bool) {
+	value := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// DELETE /hotels/:id (borrado lógico)
+func (c *Controller) Archive(ctx *gin.Context) {
+	id := strings.TrimSpace(ctx.Param("id"))
+	out, err := c.service.Archive(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.String(deleteErrorStatus(err), err.Error())
 		return
 	}
 	ctx.JSON(http.StatusOK, out)
 }
+
+// DELETE /admin/hotels/:id (borrado físico)
+func (c *Controller) Delete(ctx *gin.Context) {
+	id := strings.TrimSpace(ctx.Param("id"))
+	if err := c.service.Delete(ctx.Request.Context(), id); err != nil {
+		ctx.String(deleteErrorStatus(err), err.Error())
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func hotelErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain_hotels.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_hotels.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_hotels.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain_hotels.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain_hotels.ErrInvalidHotel), errors.Is(err, domain_hotels.ErrInvalidPatch),
+		errors.Is(err, domain_hotels.ErrInvalidQuery):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func deleteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain_hotels.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_hotels.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_hotels.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain_hotels.ErrHasFutureReservations):
+		return http.StatusConflict
+	case errors.Is(err, domain_hotels.ErrReservationsUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,175 @@
+package controllers_hotels_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	controllers "hotels/controllers_hotels"
+	"hotels/domain_hotels"
+	repositories "hotels/repositories_hotels"
+	services "hotels/services_hotels"
+	storage "hotels/storage_hotels"
+)
+
+const jwtKey = "test-key"
+
+type fakeEvents struct{}
+
+func (fakeEvents) Publish(event any) error { return nil }
+
+type fakeReservations struct{}
+
+func (fakeReservations) HasFutureReservations(ctx context.Context, hotelID string) (bool, error) {
+	return false, nil
+}
+
+func (fakeReservations) HasFutureRoomReservations(ctx context.Context, hotelID string, roomID string) (bool, error) {
+	return false, nil
+}
+
+func (fakeReservations) CompletedStay(ctx context.Context, hotelID string, userID string) (string, error) {
+	return "", nil
+}
+
+// newRouter arma las rutas de hoteles como main.go, con el service real y los repos en memoria
+func newRouter(t *testing.T) (*gin.Engine, *repositories.Mock) {
+	t.Helper()
+	blobs, err := storage.NewLocalFS(t.TempDir())
+	require.NoError(t, err)
+	repo := repositories.NewMock()
+	service := services.NewService(repo, fakeEvents{}, fakeReservations{}, blobs, repositories.NewReviewsMock(), repositories.NewAuditMock())
+	controller := controllers.NewController(service)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	auth := controllers.RequireAuth(jwtKey)
+	router.GET("/hotels/:id", controller.GetHotelByID)
+	router.POST("/createHotel", auth, controller.Create)
+	router.PUT("/edit/:id", auth, controller.Update)
+	router.PATCH("/hotels/:id", auth, controller.Patch)
+	router.DELETE("/hotels/:id", auth, controller.Archive)
+	router.DELETE("/admin/hotels/:id", auth, controller.Delete)
+	return router, repo
+}
+
+// token firma los claims como users-api
+func token(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func claims(userID float64, admin bool, expiresIn time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"username":        "ana",
+		"user_id":         userID,
+		"admin":           admin,
+		"expiration_date": time.Now().Add(expiresIn).Format(time.RFC3339),
+	}
+}
+
+// bearer: token válido del usuario
+func bearer(t *testing.T, userID float64, admin bool) string {
+	return "Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(userID, admin, time.Hour))
+}
+
+func do(router *gin.Engine, method string, target string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func seedHotel(t *testing.T, repo *repositories.Mock, owner string) domain_hotels.Hotel {
+	t.Helper()
+	h, err := repo.Create(context.Background(), domain_hotels.Hotel{Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4, OwnerID: owner})
+	require.NoError(t, err)
+	return h
+}
+
+func TestIfMatch(t *testing.T) {
+	patch := func(t *testing.T, router *gin.Engine, id string, ifMatch string) *httptest.ResponseRecorder {
+		headers := map[string]string{"Authorization": bearer(t, 7, false), "Content-Type": "application/merge-patch+json"}
+		if ifMatch != "" {
+			headers["If-Match"] = ifMatch
+		}
+		return do(router, http.MethodPatch, "/hotels/"+id, `{"stars":5}`, headers)
+	}
+
+	t.Run("Patch - Matching ETag", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+
+		recorder := patch(t, router, h.ID, `"1"`)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("Patch - Mismatched ETag Returns 412", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+
+		for _, ifMatch := range []string{`"2"`, `W/"1"`, `1`, `"abc"`, `"0"`} {
+			recorder := patch(t, router, h.ID, ifMatch)
+			assert.Equal(t, http.StatusPreconditionFailed, recorder.Code, ifMatch)
+		}
+		unchanged, err := repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, unchanged.Stars)
+	})
+
+	t.Run("Patch - Missing If-Match Skips The Check", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+		_, err := repo.Update(context.Background(), h.ID, h, 0) // otro cliente ya lo editó
+		require.NoError(t, err)
+
+		recorder := patch(t, router, h.ID, "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+
+		recorder = patch(t, router, h.ID, "*")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("Update - Mismatched ETag Returns 412", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+		headers := map[string]string{"Authorization": bearer(t, 7, false), "Content-Type": "application/json", "If-Match": `"5"`}
+
+		recorder := do(router, http.MethodPut, "/edit/"+h.ID, `{"name":"Sheraton Centro"}`, headers)
+
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.Equal(t, domain_hotels.ErrVersionConflict.Error(), recorder.Body.String())
+	})
+
+	t.Run("GetHotelByID - ETag Is The Version", func(t *testing.T) {
+		router, repo := newRouter(t)
+		h := seedHotel(t, repo, "7")
+
+		recorder := do(router, http.MethodGet, "/hotels/"+h.ID, "", nil)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+	})
+}
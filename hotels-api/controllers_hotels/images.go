@@ -0,0 +1,140 @@
+package controllers_hotels
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"hotels/domain_hotels"
+	"hotels/storage_hotels"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Las claves de las imágenes no se reutilizan, así que se pueden cachear "para siempre"
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+// GET /hotels/:id/images
+func (c *Controller) ListImages(ctx *gin.Context) {
+	images, err := c.service.ListImages(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")))
+	if err != nil {
+		ctx.String(imageErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, images)
+}
+
+// POST /hotels/:id/images (multipart, campo "image")
+func (c *Controller) UploadImage(ctx *gin.Context) {
+	// Un poco de margen para el resto del multipart; lo que pase de ahí corta la lectura
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, domain_hotels.MaxImageSize+64<<10)
+
+	file, err := ctx.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.String(http.StatusRequestEntityTooLarge, domain_hotels.ErrImageTooLarge.Error())
+			return
+		}
+		ctx.String(http.StatusBadRequest, "missing image file")
+		return
+	}
+	if file.Size > domain_hotels.MaxImageSize {
+		ctx.String(http.StatusRequestEntityTooLarge, domain_hotels.ErrImageTooLarge.Error())
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, domain_hotels.MaxImageSize+1))
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+
+	img, err := c.service.AddImage(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), data)
+	if err != nil {
+		ctx.String(imageErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusCreated, img)
+}
+
+// DELETE /hotels/:id/images/:imageId
+func (c *Controller) DeleteImage(ctx *gin.Context) {
+	if err := c.service.DeleteImage(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), ctx.Param("imageId")); err != nil {
+		ctx.String(imageErrorStatus(err), err.Error())
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// PUT /hotels/:id/images/order {"ids": ["img_b", "img_a"]}
+func (c *Controller) ReorderImages(ctx *gin.Context) {
+	var in struct {
+		IDs []string `json:"ids"`
+	}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+
+	images, err := c.service.ReorderImages(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), in.IDs)
+	if err != nil {
+		ctx.String(imageErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, images)
+}
+
+// GET /images/*key sirve el archivo con Cache-Control y ETag (responde 304 si no cambió)
+func (c *Controller) ServeImage(ctx *gin.Context) {
+	blob, err := c.service.GetImageBlob(ctx.Request.Context(), strings.TrimPrefix(ctx.Param("key"), "/"))
+	if err != nil {
+		if errors.Is(err, storage_hotels.ErrBlobNotFound) {
+			ctx.String(http.StatusNotFound, "not found")
+			return
+		}
+		ctx.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sum := sha256.Sum256(blob.Data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", imageCacheControl)
+	if match := ctx.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.Data(http.StatusOK, blob.ContentType, blob.Data)
+}
+
+func imageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain_hotels.ErrNotFound), errors.Is(err, domain_hotels.ErrImageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_hotels.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain_hotels.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_hotels.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain_hotels.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, domain_hotels.ErrUnsupportedImage):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, domain_hotels.ErrTooManyImages):
+		return http.StatusConflict
+	case errors.Is(err, domain_hotels.ErrInvalidOrder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,93 @@
+package controllers_hotels
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hotels/domain_hotels"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportSize = 10 << 20
+
+// POST /hotels/import?format=csv|ndjson&dry_run=true
+// El formato sale de ?format o del Content-Type (text/csv, application/x-ndjson).
+func (c *Controller) Import(ctx *gin.Context) {
+	format := importFormat(ctx)
+	if format == "" {
+		ctx.String(http.StatusUnsupportedMediaType, "use format=csv or format=ndjson")
+		return
+	}
+	dryRun := false
+	if value := ctx.Query("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			ctx.String(http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	report, err := c.service.Import(ctx.Request.Context(), format, body, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			ctx.String(http.StatusRequestEntityTooLarge, "file too large")
+		case errors.Is(err, domain_hotels.ErrInvalidImport):
+			ctx.String(http.StatusBadRequest, err.Error())
+		default:
+			ctx.String(hotelErrorStatus(err), err.Error())
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// GET /hotels/export?format=csv|ndjson (default csv)
+func (c *Controller) Export(ctx *gin.Context) {
+	format := strings.ToLower(ctx.DefaultQuery("format", domain_hotels.FormatCSV))
+	contentType := map[string]string{
+		domain_hotels.FormatCSV:    "text/csv; charset=utf-8",
+		domain_hotels.FormatNDJSON: "application/x-ndjson",
+	}[format]
+	if contentType == "" {
+		ctx.String(http.StatusBadRequest, "use format=csv or format=ndjson")
+		return
+	}
+	if _, ok := domain_hotels.ActorFrom(ctx.Request.Context()); !ok {
+		ctx.String(http.StatusUnauthorized, domain_hotels.ErrUnauthorized.Error())
+		return
+	}
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", `attachment; filename="hotels.`+format+`"`)
+	ctx.Status(http.StatusOK)
+	// Ya se mandó el status: si algo falla a mitad de camino solo queda cortar y loguear
+	if err := c.service.Export(ctx.Request.Context(), format, ctx.Writer); err != nil {
+		log.Printf("error exporting hotels: %v", err)
+	}
+}
+
+func importFormat(ctx *gin.Context) string {
+	if format := strings.ToLower(strings.TrimSpace(ctx.Query("format"))); format != "" {
+		if format == domain_hotels.FormatCSV || format == domain_hotels.FormatNDJSON {
+			return format
+		}
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return domain_hotels.FormatCSV
+	case "application/x-ndjson", "application/ndjson":
+		return domain_hotels.FormatNDJSON
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,80 @@
+﻿package controllers_hotels
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hotels/domain_hotels"
+	"hotels/services_hotels"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /hotels/:id/quote?check_in=2025-11-20&check_out=2025-11-23&guests=2&room_type=
+// Con cheapest=true y sin room_type cotiza el tipo de habitación más barato (lo usa search-api)
+func (c *Controller) Quote(ctx *gin.Context) {
+	checkIn, err := time.Parse(time.DateOnly, ctx.Query("check_in"))
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "invalid check_in (YYYY-MM-DD)")
+		return
+	}
+	checkOut, err := time.Parse(time.DateOnly, ctx.Query("check_out"))
+	if err != nil {
+		ctx.String(http.StatusBadRequest, "invalid check_out (YYYY-MM-DD)")
+		return
+	}
+	guests := 1
+	if value := ctx.Query("guests"); value != "" {
+		if guests, err = strconv.Atoi(value); err != nil {
+			ctx.String(http.StatusBadRequest, "invalid guests")
+			return
+		}
+	}
+
+	quote, err := c.service.Quote(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), services_hotels.QuoteRequest{
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Guests:   guests,
+		RoomType: ctx.Query("room_type"),
+		Cheapest: ctx.Query("cheapest") == "true",
+	})
+	if err != nil {
+		ctx.String(pricingErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, quote)
+}
+
+// PUT /hotels/:id/pricing (reemplaza todas las reglas)
+func (c *Controller) UpdatePricing(ctx *gin.Context) {
+	var in domain_hotels.PricingRules
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+
+	out, err := c.service.UpdatePricing(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), in)
+	if err != nil {
+		ctx.String(pricingErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+func pricingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain_hotels.ErrNotFound), errors.Is(err, domain_hotels.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_hotels.ErrInvalidQuote), errors.Is(err, domain_hotels.ErrInvalidPricing):
+		return http.StatusBadRequest
+	case errors.Is(err, domain_hotels.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_hotels.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,118 @@
+package controllers_hotels
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"hotels/domain_hotels"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /hotels/:id/reviews?limit=&offset=
+func (c *Controller) ListReviews(ctx *gin.Context) {
+	q, err := parseReviewQuery(ctx)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := c.service.ListReviews(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), q)
+	if err != nil {
+		ctx.String(reviewErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// POST /hotels/:id/reviews {"rating": 1..5, "title": "...", "comment": "..."}
+func (c *Controller) CreateReview(ctx *gin.Context) {
+	var in domain_hotels.Review
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+	out, err := c.service.CreateReview(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), in)
+	if err != nil {
+		ctx.String(reviewErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusCreated, out)
+}
+
+// DELETE /hotels/:id/reviews/:reviewId
+func (c *Controller) DeleteReview(ctx *gin.Context) {
+	hotelID := strings.TrimSpace(ctx.Param("id"))
+	reviewID := strings.TrimSpace(ctx.Param("reviewId"))
+	if err := c.service.DeleteReview(ctx.Request.Context(), hotelID, reviewID); err != nil {
+		ctx.String(reviewErrorStatus(err), err.Error())
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GET /admin/reviews?status=pending|approved|rejected&hotel_id=&limit=&offset=
+func (c *Controller) ListReviewsForModeration(ctx *gin.Context) {
+	q, err := parseReviewQuery(ctx)
+	if err != nil {
+		ctx.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	q.HotelID = strings.TrimSpace(ctx.Query("hotel_id"))
+	q.Status = strings.TrimSpace(ctx.Query("status"))
+	page, err := c.service.ListReviewsForModeration(ctx.Request.Context(), q)
+	if err != nil {
+		ctx.String(reviewErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// PUT /admin/reviews/:reviewId {"status": "approved"|"rejected", "note": "..."}
+func (c *Controller) ModerateReview(ctx *gin.Context) {
+	var in struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+	out, err := c.service.ModerateReview(ctx.Request.Context(), strings.TrimSpace(ctx.Param("reviewId")), in.Status, in.Note)
+	if err != nil {
+		ctx.String(reviewErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+func parseReviewQuery(ctx *gin.Context) (domain_hotels.ReviewQuery, error) {
+	var q domain_hotels.ReviewQuery
+	var err error
+	if q.Limit, err = intParam(ctx, "limit"); err != nil {
+		return q, err
+	}
+	if q.Offset, err = intParam(ctx, "offset"); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain_hotels.ErrNotFound), errors.Is(err, domain_hotels.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_hotels.ErrInvalidReview), errors.Is(err, domain_hotels.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, domain_hotels.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_hotels.ErrForbidden), errors.Is(err, domain_hotels.ErrNoCompletedStay):
+		return http.StatusForbidden
+	case errors.Is(err, domain_hotels.ErrAlreadyReviewed):
+		return http.StatusConflict
+	case errors.Is(err, domain_hotels.ErrReservationsUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,99 @@
+﻿package controllers_hotels
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"hotels/domain_hotels"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /hotels/:id/rooms
+func (c *Controller) ListRooms(ctx *gin.Context) {
+	rooms, err := c.service.ListRooms(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")))
+	if err != nil {
+		ctx.String(roomErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, rooms)
+}
+
+// GET /hotels/:id/rooms/:roomId
+func (c *Controller) GetRoom(ctx *gin.Context) {
+	room, err := c.service.GetRoom(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), ctx.Param("roomId"))
+	if err != nil {
+		ctx.String(roomErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, room)
+}
+
+// POST /hotels/:id/rooms
+func (c *Controller) CreateRoom(ctx *gin.Context) {
+	var in domain_hotels.RoomType
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+	if !in.Valid() {
+		ctx.String(http.StatusBadRequest, "invalid room payload")
+		return
+	}
+
+	out, err := c.service.CreateRoom(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), in)
+	if err != nil {
+		ctx.String(roomErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusCreated, out)
+}
+
+// PUT /hotels/:id/rooms/:roomId (reemplaza el tipo de habitación completo)
+func (c *Controller) UpdateRoom(ctx *gin.Context) {
+	var in domain_hotels.RoomType
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.String(http.StatusBadRequest, "bad request")
+		return
+	}
+	if !in.Valid() {
+		ctx.String(http.StatusBadRequest, "invalid room payload")
+		return
+	}
+
+	out, err := c.service.UpdateRoom(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), ctx.Param("roomId"), in)
+	if err != nil {
+		ctx.String(roomErrorStatus(err), err.Error())
+		return
+	}
+	ctx.JSON(http.StatusOK, out)
+}
+
+// DELETE /hotels/:id/rooms/:roomId
+func (c *Controller) DeleteRoom(ctx *gin.Context) {
+	if err := c.service.DeleteRoom(ctx.Request.Context(), strings.TrimSpace(ctx.Param("id")), ctx.Param("roomId")); err != nil {
+		ctx.String(roomErrorStatus(err), err.Error())
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain_hotels.ErrNotFound), errors.Is(err, domain_hotels.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain_hotels.ErrRoomNameTaken), errors.Is(err, domain_hotels.ErrRoomHasBookings):
+		return http.StatusConflict
+	case errors.Is(err, domain_hotels.ErrReservationsUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain_hotels.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain_hotels.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain_hotels.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
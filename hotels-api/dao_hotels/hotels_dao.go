@@ -1,16 +1,31 @@
 ﻿package dao_hotels
 
-import "hotels/domain_hotels"
+import (
+	"time"
+
+	"hotels/domain_hotels"
+)
 
 // Representación del documento en MongoDB
 type Hotel struct {
-	ID            string   `bson:"_id,omitempty"`
-	Name          string   `bson:"name"`
-	City          string   `bson:"city"`
-	PricePerNight float64  `bson:"price_per_night"`
-	Stars         int      `bson:"stars"`
-	Amenities     []string `bson:"amenities"`
-	OwnerID       string   `bson:"owner_id"`
+	ID            string                      `bson:"_id,omitempty"`
+	Name          string                      `bson:"name"`
+	City          string                      `bson:"city"`
+	PricePerNight float64                     `bson:"price_per_night"`
+	Stars         int                         `bson:"stars"`
+	Amenities     []string                    `bson:"amenities"`
+	OwnerID       string                      `bson:"owner_id"`
+	Address       string                      `bson:"address,omitempty"`
+	Latitude      *float64                    `bson:"latitude,omitempty"`
+	Longitude     *float64                    `bson:"longitude,omitempty"`
+	Rooms         []domain_hotels.RoomType    `bson:"rooms,omitempty"`
+	Pricing       *domain_hotels.PricingRules `bson:"pricing,omitempty"`
+	Images        []domain_hotels.Image       `bson:"images,omitempty"`
+	RatingAvg     float64                     `bson:"rating_avg"`
+	RatingCount   int                         `bson:"rating_count"`
+	Archived      bool                        `bson:"archived,omitempty"`
+	ArchivedAt    *time.Time                  `bson:"archived_at,omitempty"`
+	Version       int64                       `bson:"version"`
 }
 
 type Hotels []Hotel
@@ -25,6 +40,17 @@ func FromDomain(d domain_hotels.Hotel) Hotel {
 		Stars:         d.Stars,
 		Amenities:     d.Amenities,
 		OwnerID:       d.OwnerID,
+		Address:       d.Address,
+		Latitude:      d.Latitude,
+		Longitude:     d.Longitude,
+		Rooms:         d.Rooms,
+		Pricing:       d.Pricing,
+		Images:        d.Images,
+		RatingAvg:     d.RatingAvg,
+		RatingCount:   d.RatingCount,
+		Archived:      d.Archived,
+		ArchivedAt:    d.ArchivedAt,
+		Version:       d.Version,
 	}
 }
 
@@ -37,5 +63,16 @@ func (h Hotel) ToDomain() domain_hotels.Hotel {
 		Stars:         h.Stars,
 		Amenities:     h.Amenities,
 		OwnerID:       h.OwnerID,
+		Address:       h.Address,
+		Latitude:      h.Latitude,
+		Longitude:     h.Longitude,
+		Rooms:         h.Rooms,
+		Pricing:       h.Pricing,
+		Images:        h.Images,
+		RatingAvg:     h.RatingAvg,
+		RatingCount:   h.RatingCount,
+		Archived:      h.Archived,
+		ArchivedAt:    h.ArchivedAt,
+		Version:       h.Version,
 	}
 }
@@ -0,0 +1,41 @@
+package domain_hotels
+
+import "time"
+
+// Acciones que quedan en el historial de un hotel
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditArchive = "archive"
+	AuditDelete  = "delete"
+)
+
+// FieldChange: un campo del hotel (nombre en el JSON de la API) con su valor antes y después
+type FieldChange struct {
+	Field string `json:"field" bson:"field"`
+	From  any    `json:"from" bson:"from"`
+	To    any    `json:"to" bson:"to"`
+}
+
+// AuditEntry es inmutable: el repo solo agrega y lista
+type AuditEntry struct {
+	ID            string        `json:"id" bson:"_id,omitempty"`
+	HotelID       string        `json:"hotel_id" bson:"hotel_id"`
+	Action        string        `json:"action" bson:"action"`
+	ActorID       string        `json:"actor_id" bson:"actor_id"`
+	ActorAdmin    bool          `json:"actor_admin" bson:"actor_admin"`
+	At            time.Time     `json:"at" bson:"at"`
+	Changes       []FieldChange `json:"changes" bson:"changes"`
+	CorrelationID string        `json:"correlation_id,omitempty" bson:"correlation_id,omitempty"`
+}
+
+type AuditPage struct {
+	Items  []AuditEntry `json:"items"`
+	Total  int64        `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+func NewAuditID() string {
+	return randomID("aud_")
+}
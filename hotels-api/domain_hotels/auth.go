@@ -0,0 +1,30 @@
+package domain_hotels
+
+import "context"
+
+// Actor es el usuario autenticado del request (sale del JWT que emite users-api)
+type Actor struct {
+	UserID string
+	Admin  bool
+}
+
+// CanManage: solo el dueño del hotel o un admin pueden modificarlo
+func (a Actor) CanManage(h Hotel) bool {
+	return a.Admin || (a.UserID != "" && a.UserID == h.OwnerID)
+}
+
+type actorKey struct{}
+
+// WithActor guarda el usuario autenticado en el contexto del request
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom devuelve el usuario del contexto (false si el request no viene autenticado)
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -1,11 +1,58 @@
 ﻿package domain_hotels
 
+import (
+	"strings"
+	"time"
+)
+
 type Hotel struct {
-	ID            string   `json:"id" bson:"_id,omitempty"`
-	Name          string   `json:"name" bson:"name"`
-	City          string   `json:"city" bson:"city"`
-	PricePerNight float64  `json:"price_per_night" bson:"price_per_night"`
-	Stars         int      `json:"stars" bson:"stars"`
-	Amenities     []string `json:"amenities" bson:"amenities"`
-	OwnerID       string   `json:"owner_id" bson:"owner_id"`
+	ID            string        `json:"id" bson:"_id,omitempty"`
+	Name          string        `json:"name" bson:"name"`
+	City          string        `json:"city" bson:"city"`
+	PricePerNight float64       `json:"price_per_night" bson:"price_per_night"`
+	Stars         int           `json:"stars" bson:"stars"`
+	Amenities     []string      `json:"amenities" bson:"amenities"`
+	OwnerID       string        `json:"owner_id" bson:"owner_id"`
+	Address       string        `json:"address,omitempty" bson:"address,omitempty"`
+	Latitude      *float64      `json:"latitude,omitempty" bson:"latitude,omitempty"`
+	Longitude     *float64      `json:"longitude,omitempty" bson:"longitude,omitempty"`
+	Rooms         []RoomType    `json:"rooms,omitempty" bson:"rooms,omitempty"`
+	Pricing       *PricingRules `json:"pricing,omitempty" bson:"pricing,omitempty"`
+	Images        []Image       `json:"images,omitempty" bson:"images,omitempty"`
+
+	// Promedio y cantidad de reseñas aprobadas (los recalcula el service al moderar)
+	RatingAvg   float64 `json:"rating_avg" bson:"rating_avg"`
+	RatingCount int     `json:"rating_count" bson:"rating_count"`
+
+	// Version sube con cada escritura; es el ETag del hotel (If-Match en PUT/PATCH)
+	Version int64 `json:"version" bson:"version"`
+
+	// Borrado lógico: el hotel deja de listarse pero se puede seguir consultando por id
+	Archived   bool       `json:"archived,omitempty" bson:"archived,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+}
+
+// Valid: lo mínimo que tiene que tener un hotel completo
+func (h Hotel) Valid() bool {
+	return strings.TrimSpace(h.Name) != "" &&
+		strings.TrimSpace(h.City) != "" &&
+		h.PricePerNight > 0 &&
+		h.Stars >= 1 && h.Stars <= 5 &&
+		h.ValidLocation()
+}
+
+// HasLocation indica si el hotel tiene coordenadas (se cargan las dos o ninguna)
+func (h Hotel) HasLocation() bool {
+	return h.Latitude != nil && h.Longitude != nil
+}
+
+// ValidLocation: latitud/longitud vienen juntas y dentro de rango
+func (h Hotel) ValidLocation() bool {
+	if h.Latitude == nil && h.Longitude == nil {
+		return true
+	}
+	if !h.HasLocation() {
+		return false
+	}
+	return *h.Latitude >= -90 && *h.Latitude <= 90 && *h.Longitude >= -180 && *h.Longitude <= 180
 }
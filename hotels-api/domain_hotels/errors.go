@@ -0,0 +1,15 @@
+package domain_hotels
+
+import "errors"
+
+// Errores que el controller traduce a códigos HTTP
+var (
+	ErrNotFound                = errors.New("not found")
+	ErrHasFutureReservations   = errors.New("hotel has future pending, confirmed or checked-in reservations")
+	ErrReservationsUnavailable = errors.New("could not check reservations")
+	ErrUnauthorized            = errors.New("authentication required")
+	ErrForbidden               = errors.New("not allowed to manage this hotel")
+	ErrVersionConflict         = errors.New("hotel was modified by another request")
+	ErrInvalidHotel            = errors.New("invalid hotel payload")
+	ErrInvalidPatch            = errors.New("invalid merge patch")
+)
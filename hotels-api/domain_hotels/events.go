@@ -0,0 +1,68 @@
+package domain_hotels
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// EventVersion es la versión del contrato de eventos que comparten hotels-api y search-api
+// (search-api tiene la misma definición en domain_search). Cualquier cambio incompatible
+// en HotelEvent tiene que subir este número en los dos servicios. El ejemplo del contrato está
+// en contracts/hotel-events.v1.json y lo prueban los dos lados.
+const EventVersion = 1
+
+// Tipos de evento que se publican en la cola "hotels-news"
+const (
+	EventHotelCreated = "hotel.created"
+	EventHotelUpdated = "hotel.updated"
+	EventHotelDeleted = "hotel.deleted"
+)
+
+type HotelEvent struct {
+	Type          string    `json:"type"`
+	EntityID      string    `json:"entity_id"`
+	Version       int       `json:"version"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	CorrelationID string    `json:"correlation_id"`
+}
+
+// NewHotelEvent arma el evento tomando el correlation id del request (o generando uno nuevo)
+func NewHotelEvent(ctx context.Context, eventType string, hotelID string) HotelEvent {
+	correlationID := CorrelationIDFrom(ctx)
+	if correlationID == "" {
+		correlationID = NewCorrelationID()
+	}
+	return HotelEvent{
+		Type:          eventType,
+		EntityID:      hotelID,
+		Version:       EventVersion,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+	}
+}
+
+type correlationIDKey struct{}
+
+// WithCorrelationID guarda el correlation id en el contexto del request
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFrom devuelve el correlation id del contexto ("" si no hay)
+func CorrelationIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
+
+func NewCorrelationID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
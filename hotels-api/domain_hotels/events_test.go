@@ -0,0 +1,68 @@
+package domain_hotels_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+)
+
+// El mismo archivo lo lee el test de search-api: si uno de los dos cambia HotelEvent sin el
+// otro, alguno de los tests falla
+const eventsContract = "../../contracts/hotel-events.v1.json"
+
+func TestHotelEventContract(t *testing.T) {
+	data, err := os.ReadFile(eventsContract)
+	require.NoError(t, err)
+	var fixtures []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fixtures))
+	require.Len(t, fixtures, 3)
+
+	t.Run("HotelEvent - Round Trip", func(t *testing.T) {
+		types := make([]string, 0, len(fixtures))
+		for _, fixture := range fixtures {
+			decoder := json.NewDecoder(bytes.NewReader(fixture))
+			decoder.DisallowUnknownFields()
+			var event domain_hotels.HotelEvent
+			require.NoError(t, decoder.Decode(&event))
+
+			assert.Equal(t, domain_hotels.EventVersion, event.Version)
+			assert.NotEmpty(t, event.EntityID)
+			assert.NotEmpty(t, event.CorrelationID)
+			assert.False(t, event.OccurredAt.IsZero())
+			types = append(types, event.Type)
+
+			encoded, err := json.Marshal(event)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(fixture), string(encoded))
+		}
+		assert.Equal(t, []string{domain_hotels.EventHotelCreated, domain_hotels.EventHotelUpdated, domain_hotels.EventHotelDeleted}, types)
+	})
+
+	t.Run("NewHotelEvent - Has The Fields Of The Contract", func(t *testing.T) {
+		ctx := domain_hotels.WithCorrelationID(context.Background(), "abc")
+		encoded, err := json.Marshal(domain_hotels.NewHotelEvent(ctx, domain_hotels.EventHotelUpdated, "h1"))
+		require.NoError(t, err)
+
+		assert.Equal(t, keys(t, fixtures[1]), keys(t, encoded))
+	})
+}
+
+func keys(t *testing.T, data []byte) []string {
+	t.Helper()
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,37 @@
+package domain_hotels
+
+import (
+	"errors"
+	"time"
+)
+
+// Límites de la galería
+const (
+	MaxImageSize      = 5 << 20 // 5MB
+	MaxImagesPerHotel = 20
+)
+
+var (
+	ErrImageNotFound    = errors.New("image not found")
+	ErrImageTooLarge    = errors.New("image is larger than 5MB")
+	ErrUnsupportedImage = errors.New("unsupported image type (jpeg, png or gif)")
+	ErrTooManyImages    = errors.New("hotel already has the maximum number of images")
+	ErrInvalidOrder     = errors.New("order must list every image id exactly once")
+)
+
+// Image es una foto de la galería del hotel; el orden del slice es el orden de la galería
+type Image struct {
+	ID           string    `json:"id" bson:"id"`
+	URL          string    `json:"url" bson:"url"`
+	ThumbnailURL string    `json:"thumbnail_url" bson:"thumbnail_url"`
+	ContentType  string    `json:"content_type" bson:"content_type"`
+	Size         int       `json:"size" bson:"size"`
+	Width        int       `json:"width" bson:"width"`
+	Height       int       `json:"height" bson:"height"`
+	UploadedAt   time.Time `json:"uploaded_at" bson:"uploaded_at"`
+}
+
+// NewImageID genera el id de una imagen
+func NewImageID() string {
+	return randomID("img_")
+}
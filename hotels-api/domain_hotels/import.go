@@ -0,0 +1,60 @@
+package domain_hotels
+
+import (
+	"errors"
+	"strings"
+)
+
+// Formatos de POST /hotels/import y GET /hotels/export
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+const MaxImportRows = 5000
+
+// maxImportIDLength: un id propio del catálogo tiene que entrar en URLs y claves de imágenes
+const maxImportIDLength = 64
+
+// ValidImportID: los ids que puede traer un archivo para crear un hotel nuevo (letras, dígitos,
+// '-' y '_'), así no terminan en una ruta o en la clave de una imagen
+func ValidImportID(id string) bool {
+	if id == "" || len(id) > maxImportIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportKey es la clave natural de una fila sin id: nombre, ciudad y dirección, sin distinguir
+// mayúsculas ni espacios de más. Con ella reimportar el mismo catálogo actualiza en vez de duplicar.
+func ImportKey(h Hotel) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), " "))
+	}
+	return normalize(h.Name) + "|" + normalize(h.City) + "|" + normalize(h.Address)
+}
+
+// ErrInvalidImport: el archivo entero no se puede procesar (formato desconocido, encabezado
+// inválido, demasiadas filas). Los errores de una fila van en el reporte.
+var ErrInvalidImport = errors.New("invalid import file")
+
+type ImportRowError struct {
+	Row   int    `json:"row"` // línea del archivo (en CSV la 1 es el encabezado)
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport: qué pasó (o pasaría, con dry_run) con cada fila
+type ImportReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Rows    int              `json:"rows"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,119 @@
+package domain_hotels
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultCurrency = "ARS"
+	MaxQuoteNights  = 365
+	seasonDayLayout = "01-02" // MM-DD
+)
+
+var (
+	ErrInvalidPricing = errors.New("invalid pricing rules")
+	ErrInvalidQuote   = errors.New("invalid quote request")
+)
+
+// PricingRules ajustan el precio base (PricePerNight del hotel o BasePrice de la habitación)
+type PricingRules struct {
+	Currency            string         `json:"currency" bson:"currency"`
+	Seasons             []Season       `json:"seasons" bson:"seasons"`
+	WeekendSurchargePct float64        `json:"weekend_surcharge_pct" bson:"weekend_surcharge_pct"` // noches de viernes y sábado
+	MinStay             int            `json:"min_stay" bson:"min_stay"`
+	StayDiscounts       []StayDiscount `json:"stay_discounts" bson:"stay_discounts"`
+}
+
+// Season es un rango MM-DD (inclusive, puede cruzar el año: 12-15 a 03-01) con un ajuste en %
+type Season struct {
+	Name          string  `json:"name" bson:"name"`
+	From          string  `json:"from" bson:"from"`
+	To            string  `json:"to" bson:"to"`
+	AdjustmentPct float64 `json:"adjustment_pct" bson:"adjustment_pct"` // 30 = +30%, -20 = -20%
+	MinStay       int     `json:"min_stay,omitempty" bson:"min_stay,omitempty"`
+}
+
+// StayDiscount: descuento sobre el total a partir de cierta cantidad de noches
+type StayDiscount struct {
+	MinNights   int     `json:"min_nights" bson:"min_nights"`
+	DiscountPct float64 `json:"discount_pct" bson:"discount_pct"`
+}
+
+// Quote es el precio de una estadía noche por noche
+type Quote struct {
+	HotelID     string       `json:"hotel_id"`
+	RoomType    string       `json:"room_type,omitempty"`
+	CheckIn     string       `json:"check_in"`
+	CheckOut    string       `json:"check_out"`
+	Nights      int          `json:"nights"`
+	Guests      int          `json:"guests"`
+	Currency    string       `json:"currency"`
+	Nightly     []NightPrice `json:"nightly"`
+	Subtotal    float64      `json:"subtotal"`
+	DiscountPct float64      `json:"discount_pct,omitempty"`
+	Discount    float64      `json:"discount,omitempty"`
+	Total       float64      `json:"total"`
+}
+
+type NightPrice struct {
+	Date       string  `json:"date"`
+	Base       float64 `json:"base"`
+	Season     string  `json:"season,omitempty"`
+	SeasonPct  float64 `json:"season_pct,omitempty"`
+	WeekendPct float64 `json:"weekend_pct,omitempty"`
+	Price      float64 `json:"price"`
+}
+
+// Contains indica si la fecha cae dentro de la temporada
+func (s Season) Contains(day time.Time) bool {
+	from, err := time.Parse(seasonDayLayout, s.From)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse(seasonDayLayout, s.To)
+	if err != nil {
+		return false
+	}
+	d := monthDay(day)
+	f, t := monthDay(from), monthDay(to)
+	if f <= t {
+		return d >= f && d <= t
+	}
+	return d >= f || d <= t // cruza fin de año
+}
+
+func monthDay(t time.Time) int {
+	return int(t.Month())*100 + t.Day()
+}
+
+// Valid: validaciones de las reglas
+func (p PricingRules) Valid() error {
+	if p.WeekendSurchargePct < 0 || p.WeekendSurchargePct > 500 {
+		return fmt.Errorf("%w: weekend_surcharge_pct must be between 0 and 500", ErrInvalidPricing)
+	}
+	if p.MinStay < 0 || p.MinStay > MaxQuoteNights {
+		return fmt.Errorf("%w: min_stay out of range", ErrInvalidPricing)
+	}
+	for _, s := range p.Seasons {
+		if _, err := time.Parse(seasonDayLayout, s.From); err != nil {
+			return fmt.Errorf("%w: season from must be MM-DD", ErrInvalidPricing)
+		}
+		if _, err := time.Parse(seasonDayLayout, s.To); err != nil {
+			return fmt.Errorf("%w: season to must be MM-DD", ErrInvalidPricing)
+		}
+		if s.AdjustmentPct <= -100 || s.AdjustmentPct > 500 {
+			return fmt.Errorf("%w: season adjustment_pct must be greater than -100 and at most 500", ErrInvalidPricing)
+		}
+		if s.MinStay < 0 || s.MinStay > MaxQuoteNights {
+			return fmt.Errorf("%w: season min_stay out of range", ErrInvalidPricing)
+		}
+	}
+	for _, d := range p.StayDiscounts {
+		if d.MinNights < 2 || d.DiscountPct <= 0 || d.DiscountPct >= 100 {
+			return fmt.Errorf("%w: stay discounts need min_nights >= 2 and discount_pct between 0 and 100", ErrInvalidPricing)
+		}
+	}
+	return nil
+}
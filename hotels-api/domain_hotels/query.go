@@ -0,0 +1,104 @@
+package domain_hotels
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Órdenes de GET /hotels; con "-" adelante es descendente (ej. sort=-price)
+const (
+	SortName  = "name"
+	SortPrice = "price"
+	SortStars = "stars"
+)
+
+var ErrInvalidQuery = errors.New("invalid hotels query")
+
+// HotelQuery: filtros, orden y página del listado de hoteles. Los archivados nunca se listan.
+type HotelQuery struct {
+	Q        string  // texto libre sobre nombre o ciudad
+	City     string  // ciudad exacta (sin distinguir mayúsculas)
+	MinStars int     // 0 = sin filtro
+	MaxPrice float64 // 0 = sin filtro
+	Amenity  string  // el hotel tiene que tener esta amenity
+	Sort     string  // name, price o stars (vacío = por id, estable para paginar)
+	Desc     bool
+	Limit    int
+	Offset   int
+}
+
+// HotelPage es la respuesta de GET /hotels
+type HotelPage struct {
+	Items  []Hotel `json:"items"`
+	Total  int64   `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// ParseSort separa "-price" en ("price", true)
+func ParseSort(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		return rest, true
+	}
+	return value, false
+}
+
+// Normalize completa los defaults y valida los rangos
+func (q HotelQuery) Normalize() (HotelQuery, error) {
+	q.Q = strings.TrimSpace(q.Q)
+	q.City = strings.TrimSpace(q.City)
+	q.Amenity = strings.TrimSpace(q.Amenity)
+	switch q.Sort {
+	case "", SortName, SortPrice, SortStars:
+	default:
+		return q, ErrInvalidQuery
+	}
+	if q.MinStars < 0 || q.MinStars > 5 || q.MaxPrice < 0 || q.Offset < 0 || q.Limit < 0 || q.Limit > MaxPageSize {
+		return q, ErrInvalidQuery
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultPageSize
+	}
+	return q, nil
+}
+
+// Matches aplica los filtros en memoria (el repo de Mongo arma el mismo filtro en bson)
+func (q HotelQuery) Matches(h Hotel) bool {
+	if h.Archived {
+		return false
+	}
+	if q.Q != "" {
+		text := strings.ToLower(q.Q)
+		if !strings.Contains(strings.ToLower(h.Name), text) && !strings.Contains(strings.ToLower(h.City), text) {
+			return false
+		}
+	}
+	if q.City != "" && !strings.EqualFold(h.City, q.City) {
+		return false
+	}
+	if q.MinStars > 0 && h.Stars < q.MinStars {
+		return false
+	}
+	if q.MaxPrice > 0 && h.PricePerNight > q.MaxPrice {
+		return false
+	}
+	if q.Amenity != "" && !hasAmenity(h.Amenities, q.Amenity) {
+		return false
+	}
+	return true
+}
+
+func hasAmenity(amenities []string, amenity string) bool {
+	for _, a := range amenities {
+		if strings.EqualFold(strings.TrimSpace(a), amenity) {
+			return true
+		}
+	}
+	return false
+}
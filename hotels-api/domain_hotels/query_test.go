@@ -0,0 +1,88 @@
+package domain_hotels_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+)
+
+func TestHotelQuery(t *testing.T) {
+	t.Run("Normalize - Defaults And Trimming", func(t *testing.T) {
+		q, err := domain_hotels.HotelQuery{Q: "  sher ", City: " Córdoba ", Amenity: " wifi "}.Normalize()
+
+		require.NoError(t, err)
+		assert.Equal(t, domain_hotels.HotelQuery{Q: "sher", City: "Córdoba", Amenity: "wifi", Limit: domain_hotels.DefaultPageSize}, q)
+	})
+
+	t.Run("Normalize - Ranges", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			query domain_hotels.HotelQuery
+			valid bool
+		}{
+			{"max page size", domain_hotels.HotelQuery{Limit: domain_hotels.MaxPageSize}, true},
+			{"page too large", domain_hotels.HotelQuery{Limit: domain_hotels.MaxPageSize + 1}, false},
+			{"negative limit", domain_hotels.HotelQuery{Limit: -1}, false},
+			{"negative offset", domain_hotels.HotelQuery{Offset: -1}, false},
+			{"stars 5", domain_hotels.HotelQuery{MinStars: 5}, true},
+			{"stars 6", domain_hotels.HotelQuery{MinStars: 6}, false},
+			{"negative price", domain_hotels.HotelQuery{MaxPrice: -1}, false},
+			{"sort by price", domain_hotels.HotelQuery{Sort: domain_hotels.SortPrice, Desc: true}, true},
+			{"unknown sort", domain_hotels.HotelQuery{Sort: "rating"}, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.query.Normalize()
+
+				if tt.valid {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, domain_hotels.ErrInvalidQuery)
+				}
+			})
+		}
+	})
+
+	t.Run("ParseSort - Leading Dash Is Descending", func(t *testing.T) {
+		field, desc := domain_hotels.ParseSort(" -Price ")
+		assert.Equal(t, domain_hotels.SortPrice, field)
+		assert.True(t, desc)
+
+		field, desc = domain_hotels.ParseSort("name")
+		assert.Equal(t, domain_hotels.SortName, field)
+		assert.False(t, desc)
+	})
+
+	t.Run("Matches - Filters", func(t *testing.T) {
+		h := domain_hotels.Hotel{Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4, Amenities: []string{" WiFi ", "pool"}}
+		tests := []struct {
+			name  string
+			query domain_hotels.HotelQuery
+			want  bool
+		}{
+			{"no filters", domain_hotels.HotelQuery{}, true},
+			{"text in the name", domain_hotels.HotelQuery{Q: "SHERA"}, true},
+			{"text in the city", domain_hotels.HotelQuery{Q: "córd"}, true},
+			{"text not found", domain_hotels.HotelQuery{Q: "hilton"}, false},
+			{"exact city ignoring case", domain_hotels.HotelQuery{City: "CÓRDOBA"}, true},
+			{"city is not a prefix match", domain_hotels.HotelQuery{City: "Córd"}, false},
+			{"min stars met", domain_hotels.HotelQuery{MinStars: 4}, true},
+			{"min stars not met", domain_hotels.HotelQuery{MinStars: 5}, false},
+			{"max price met", domain_hotels.HotelQuery{MaxPrice: 1000}, true},
+			{"max price not met", domain_hotels.HotelQuery{MaxPrice: 999}, false},
+			{"amenity ignoring case and spaces", domain_hotels.HotelQuery{Amenity: "wifi"}, true},
+			{"missing amenity", domain_hotels.HotelQuery{Amenity: "spa"}, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, tt.query.Matches(h))
+			})
+		}
+
+		h.Archived = true
+		assert.False(t, domain_hotels.HotelQuery{}.Matches(h))
+	})
+}
@@ -0,0 +1,91 @@
+package domain_hotels
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Estados de moderación de una reseña: solo las aprobadas se muestran y cuentan en el promedio
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+const (
+	MaxReviewTitle   = 100
+	MaxReviewComment = 2000
+)
+
+var (
+	ErrReviewNotFound  = errors.New("review not found")
+	ErrInvalidReview   = errors.New("invalid review payload")
+	ErrNoCompletedStay = errors.New("only guests with a completed stay can review this hotel")
+	ErrAlreadyReviewed = errors.New("user already reviewed this hotel")
+)
+
+type Review struct {
+	ID             string     `json:"id" bson:"_id,omitempty"`
+	HotelID        string     `json:"hotel_id" bson:"hotel_id"`
+	UserID         string     `json:"user_id" bson:"user_id"`
+	ReservationID  string     `json:"reservation_id" bson:"reservation_id"`
+	Rating         int        `json:"rating" bson:"rating"`
+	Title          string     `json:"title,omitempty" bson:"title,omitempty"`
+	Comment        string     `json:"comment,omitempty" bson:"comment,omitempty"`
+	Status         string     `json:"status" bson:"status"`
+	ModerationNote string     `json:"moderation_note,omitempty" bson:"moderation_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" bson:"created_at"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+}
+
+// Valid: puntaje de 1 a 5 y textos dentro de los largos máximos
+func (r Review) Valid() bool {
+	return r.Rating >= 1 && r.Rating <= 5 &&
+		len([]rune(strings.TrimSpace(r.Title))) <= MaxReviewTitle &&
+		len([]rune(strings.TrimSpace(r.Comment))) <= MaxReviewComment
+}
+
+// ValidReviewStatus: los estados a los que se puede llevar una reseña al moderarla
+func ValidReviewStatus(status string) bool {
+	return status == ReviewApproved || status == ReviewRejected || status == ReviewPending
+}
+
+// ReviewQuery: filtros y página de un listado de reseñas ("" = cualquier valor)
+type ReviewQuery struct {
+	HotelID string
+	Status  string
+	Limit   int
+	Offset  int
+}
+
+type ReviewPage struct {
+	Items  []Review `json:"items"`
+	Total  int64    `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+}
+
+// RatingStats: promedio y cantidad de reseñas aprobadas de un hotel
+type RatingStats struct {
+	Average float64
+	Count   int
+}
+
+func NewReviewID() string {
+	return randomID("rev_")
+}
+
+// Normalize completa el tamaño de página y valida los rangos
+func (q ReviewQuery) Normalize() (ReviewQuery, error) {
+	if q.Status != "" && !ValidReviewStatus(q.Status) {
+		return q, ErrInvalidQuery
+	}
+	if q.Offset < 0 || q.Limit < 0 || q.Limit > MaxPageSize {
+		return q, ErrInvalidQuery
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultPageSize
+	}
+	return q, nil
+}
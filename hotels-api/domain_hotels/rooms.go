@@ -0,0 +1,61 @@
+package domain_hotels
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Límites de un tipo de habitación (reservations-api no acepta más de 10 huéspedes)
+const (
+	MaxRoomCapacity = 10
+	MaxRoomUnits    = 1000
+)
+
+var (
+	ErrRoomNotFound    = errors.New("room type not found")
+	ErrRoomNameTaken   = errors.New("room type name already exists in this hotel")
+	ErrRoomHasBookings = errors.New("room type has future pending, confirmed or checked-in reservations")
+)
+
+// RoomType es un tipo de habitación del hotel; Units es cuántas habitaciones iguales hay
+type RoomType struct {
+	ID        string   `json:"id" bson:"id"`
+	Name      string   `json:"name" bson:"name"`
+	Capacity  int      `json:"capacity" bson:"capacity"`
+	Units     int      `json:"units" bson:"units"`
+	BasePrice float64  `json:"base_price" bson:"base_price"`
+	Amenities []string `json:"amenities" bson:"amenities"`
+}
+
+// Valid: validaciones mínimas del payload
+func (r RoomType) Valid() bool {
+	return strings.TrimSpace(r.Name) != "" &&
+		r.Capacity >= 1 && r.Capacity <= MaxRoomCapacity &&
+		r.Units >= 1 && r.Units <= MaxRoomUnits &&
+		r.BasePrice > 0
+}
+
+// FindRoom busca un tipo de habitación por id
+func (h Hotel) FindRoom(roomID string) (RoomType, int, bool) {
+	for i, room := range h.Rooms {
+		if room.ID == roomID {
+			return room, i, true
+		}
+	}
+	return RoomType{}, -1, false
+}
+
+// NewRoomID genera el id de un tipo de habitación (único dentro del hotel)
+func NewRoomID() string {
+	return randomID("rt_")
+}
+
+func randomID(prefix string) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return prefix + hex.EncodeToString(b)
+}
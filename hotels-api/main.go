@@ -1,6 +1,7 @@
 ﻿package main
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -8,17 +9,25 @@ import (
 	"github.com/gin-gonic/gin"
 
 	queues "hotels/clients_hotels"
+	config "hotels/config_hotels"
 	controllers "hotels/controllers_hotels"
 	repositories "hotels/repositories_hotels"
 	services "hotels/services_hotels"
+	storage "hotels/storage_hotels"
 )
 
 func main() {
-	// Usamos repo en memoria para probar
-	mainRepository := repositories.NewMock()
-	_ = mainRepository.SeedFromJSON("db/hotels.json")
+	cfg := config.Load()
 
-	// Cola “dummy” (no falla si no hay Rabbit)
+	backend := newBackend(cfg)
+	defer backend.close()
+	if cfg.SeedFile != "" {
+		if err := backend.hotels.SeedFromJSON(cfg.SeedFile); err != nil {
+			log.Printf("could not seed hotels from %s: %v", cfg.SeedFile, err)
+		}
+	}
+
+	// Cola de eventos (si no hay Rabbit, los eventos quedan en buffer hasta que se reconecta)
 	eventsQueue := queues.NewRabbit(queues.RabbitConfig{
 		Host:      "rabbitmq",
 		Port:      "5672",
@@ -26,8 +35,21 @@ func main() {
 		Password:  "root",
 		QueueName: "hotels-news",
 	})
+	defer eventsQueue.Close()
+
+	// reservations-api: antes de borrar un hotel se revisa que no tenga reservas futuras
+	reservationsAPI := queues.NewReservations(queues.ReservationsConfig{
+		Host: cfg.ReservationsHost,
+		Port: cfg.ReservationsPort,
+	})
+
+	// Imágenes de la galería en disco
+	blobs, err := storage.NewLocalFS(cfg.ImagesDir)
+	if err != nil {
+		log.Fatalf("error initializing image storage: %v", err)
+	}
 
-	service := services.NewService(mainRepository, eventsQueue)
+	service := services.NewService(backend.hotels, eventsQueue, reservationsAPI, blobs, backend.reviews, backend.history)
 	controller := controllers.NewController(service)
 
 	router := gin.Default()
@@ -35,19 +57,110 @@ func main() {
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "If-Match", "X-Correlation-ID"},
+		ExposeHeaders:    []string{"Content-Length", "ETag", "X-Correlation-ID"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	router.Use(controllers.CorrelationID())
+
+	// Las lecturas son públicas; para modificar un hotel hace falta el token de users-api
+	auth := controllers.RequireAuth(cfg.JWTKey)
 
 	router.GET("/hotels/:id", controller.GetHotelByID)
 	router.GET("/hotels", controller.GetHotels)
-	router.POST("/createHotel", controller.Create)
-	router.PUT("/edit/:id", controller.Update)
+	router.GET("/me/hotels", auth, controller.GetMyHotels)
+	router.POST("/hotels/import", auth, controller.Import)
+	router.GET("/hotels/export", auth, controller.Export)
+	router.POST("/createHotel", auth, controller.Create)
+	router.PUT("/edit/:id", auth, controller.Update)
+	router.PATCH("/hotels/:id", auth, controller.Patch)
+	router.DELETE("/hotels/:id", auth, controller.Archive)
+	router.DELETE("/admin/hotels/:id", auth, controller.Delete)
+
+	router.GET("/hotels/:id/rooms", controller.ListRooms)
+	router.POST("/hotels/:id/rooms", auth, controller.CreateRoom)
+	router.GET("/hotels/:id/rooms/:roomId", controller.GetRoom)
+	router.PUT("/hotels/:id/rooms/:roomId", auth, controller.UpdateRoom)
+	router.DELETE("/hotels/:id/rooms/:roomId", auth, controller.DeleteRoom)
+
+	router.GET("/hotels/:id/quote", controller.Quote)
+	router.PUT("/hotels/:id/pricing", auth, controller.UpdatePricing)
+
+	router.GET("/hotels/:id/images", controller.ListImages)
+	router.POST("/hotels/:id/images", auth, controller.UploadImage)
+	router.PUT("/hotels/:id/images/order", auth, controller.ReorderImages)
+	router.DELETE("/hotels/:id/images/:imageId", auth, controller.DeleteImage)
+	router.GET("/images/*key", controller.ServeImage)
 
-	if err := router.Run(":8081"); err != nil {
+	router.GET("/hotels/:id/history", auth, controller.History)
+
+	router.GET("/hotels/:id/reviews", controller.ListReviews)
+	router.POST("/hotels/:id/reviews", auth, controller.CreateReview)
+	router.DELETE("/hotels/:id/reviews/:reviewId", auth, controller.DeleteReview)
+	router.GET("/admin/reviews", auth, controller.ListReviewsForModeration)
+	router.PUT("/admin/reviews/:reviewId", auth, controller.ModerateReview)
+
+	if err := router.Run(":" + cfg.Port); err != nil {
 		log.Fatalf("error running application: %v", err)
 	}
 }
+
+// Lo que main necesita del repo además de services.Repository
+type repository interface {
+	services.Repository
+	SeedFromJSON(path string) error
+}
+
+// backend: los repos del backend elegido en HOTELS_REPOSITORY; todos comparten la misma base
+type backend struct {
+	hotels  repository
+	reviews services.ReviewRepository
+	history services.AuditRepository
+	close   func()
+}
+
+// newBackend arma el backend elegido en HOTELS_REPOSITORY (mongo o memory), para los hoteles,
+// las reseñas y el historial de cambios
+func newBackend(cfg config.Config) backend {
+	switch cfg.Repository {
+	case config.RepositoryMemory:
+		log.Println("using in-memory hotels repository (data is lost on restart)")
+		return backend{
+			hotels:  repositories.NewMock(),
+			reviews: repositories.NewReviewsMock(),
+			history: repositories.NewAuditMock(),
+			close:   func() {},
+		}
+	case config.RepositoryMongo:
+		repo, err := repositories.NewMongo(repositories.MongoConfig{
+			Host:       cfg.Mongo.Host,
+			Port:       cfg.Mongo.Port,
+			Username:   cfg.Mongo.Username,
+			Password:   cfg.Mongo.Password,
+			Database:   cfg.Mongo.Database,
+			Collection: cfg.Mongo.Collection,
+		})
+		if err != nil {
+			log.Fatalf("error initializing mongo repository: %v", err)
+		}
+		reviews, err := repositories.NewMongoReviews(repo, cfg.Mongo.ReviewsCollection)
+		if err != nil {
+			log.Fatalf("error initializing mongo reviews repository: %v", err)
+		}
+		history, err := repositories.NewMongoAudit(repo, cfg.Mongo.AuditCollection)
+		if err != nil {
+			log.Fatalf("error initializing mongo history repository: %v", err)
+		}
+		return backend{
+			hotels:  repo,
+			reviews: reviews,
+			history: history,
+			close:   func() { _ = repo.Close(context.Background()) },
+		}
+	default:
+		log.Fatalf("unknown HOTELS_REPOSITORY %q (use %q or %q)", cfg.Repository, config.RepositoryMongo, config.RepositoryMemory)
+		return backend{}
+	}
+}
@@ -0,0 +1,48 @@
+package repositories_hotels
+
+import (
+	"context"
+	"sync"
+
+	"hotels/domain_hotels"
+)
+
+// AuditMock guarda el historial en memoria; solo se agregan entradas, nunca se modifican
+type AuditMock struct {
+	mu      sync.RWMutex
+	entries []domain_hotels.AuditEntry
+}
+
+func NewAuditMock() *AuditMock { return &AuditMock{} }
+
+func (m *AuditMock) Append(ctx context.Context, entry domain_hotels.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry.ID == "" {
+		entry.ID = domain_hotels.NewAuditID()
+	}
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+// ListByHotel: las más nuevas primero (las entradas se agregan en orden, se recorren al revés)
+func (m *AuditMock) ListByHotel(ctx context.Context, hotelID string, limit int, offset int) (domain_hotels.AuditPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matched := make([]domain_hotels.AuditEntry, 0)
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.entries[i].HotelID == hotelID {
+			matched = append(matched, m.entries[i])
+		}
+	}
+
+	start := min(offset, len(matched))
+	end := min(start+limit, len(matched))
+	return domain_hotels.AuditPage{
+		Items:  matched[start:end],
+		Total:  int64(len(matched)),
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package repositories_hotels
+
+import (
+	"context"
+	"fmt"
+
+	"hotels/domain_hotels"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAudit guarda el historial de los hoteles en su propia colección. Solo hace inserts:
+// una entrada no se edita ni se borra (tampoco cuando se borra el hotel).
+type MongoAudit struct {
+	col *mongo.Collection
+}
+
+func NewMongoAudit(m *Mongo, collection string) (*MongoAudit, error) {
+	a := &MongoAudit{col: m.client.Database(m.database).Collection(collection)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	_, err := a.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "hotel_id", Value: 1}, {Key: "at", Value: -1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating history indexes: %w", err)
+	}
+	return a, nil
+}
+
+func (a *MongoAudit) Append(ctx context.Context, entry domain_hotels.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = domain_hotels.NewAuditID()
+	}
+	if _, err := a.col.InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("error appending history entry: %w", err)
+	}
+	return nil
+}
+
+// ListByHotel: las más nuevas primero
+func (a *MongoAudit) ListByHotel(ctx context.Context, hotelID string, limit int, offset int) (domain_hotels.AuditPage, error) {
+	filter := bson.M{"hotel_id": hotelID}
+	total, err := a.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain_hotels.AuditPage{}, fmt.Errorf("error counting history entries: %w", err)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cur, err := a.col.Find(ctx, filter, opts)
+	if err != nil {
+		return domain_hotels.AuditPage{}, fmt.Errorf("error getting history: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	items := make([]domain_hotels.AuditEntry, 0)
+	if err := cur.All(ctx, &items); err != nil {
+		return domain_hotels.AuditPage{}, fmt.Errorf("error decoding history: %w", err)
+	}
+	return domain_hotels.AuditPage{Items: items, Total: total, Limit: limit, Offset: offset}, nil
+}
@@ -1,14 +1,19 @@
 ﻿package repositories_hotels
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"log"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
 	"hotels/domain_hotels"
 )
 
@@ -23,21 +28,36 @@ func (m *Mock) Create(ctx context.Context, h domain_hotels.Hotel) (domain_hotels
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if h.ID == "" {
-		h.ID = fmt.Sprintf("%d", time.Now().UnixNano())
+		h.ID = newID()
 	}
+	h.Version = 1
 	m.db[h.ID] = h
 	return h, nil
 }
 
-func (m *Mock) Update(ctx context.Context, id string, h domain_hotels.Hotel) (domain_hotels.Hotel, error) {
+// Update reemplaza los datos editables del hotel si la versión sigue siendo expectedVersion
+// (0 = sin chequeo). Habitaciones, tarifas, galería y archivado tienen sus propios métodos.
+func (m *Mock) Update(ctx context.Context, id string, h domain_hotels.Hotel, expectedVersion int64) (domain_hotels.Hotel, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if _, ok := m.db[id]; !ok {
-		return domain_hotels.Hotel{}, fmt.Errorf("not found")
+	existing, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
 	}
-	h.ID = id
-	m.db[id] = h
-	return h, nil
+	if expectedVersion != 0 && existing.Version != expectedVersion {
+		return domain_hotels.Hotel{}, domain_hotels.ErrVersionConflict
+	}
+	existing.Name = h.Name
+	existing.City = h.City
+	existing.PricePerNight = h.PricePerNight
+	existing.Stars = h.Stars
+	existing.Amenities = h.Amenities
+	existing.OwnerID = h.OwnerID
+	existing.Address = h.Address
+	existing.Latitude, existing.Longitude = h.Latitude, h.Longitude
+	existing.Version++
+	m.db[id] = existing
+	return existing, nil
 }
 
 func (m *Mock) GetByID(ctx context.Context, id string) (domain_hotels.Hotel, error) {
@@ -45,54 +65,227 @@ func (m *Mock) GetByID(ctx context.Context, id string) (domain_hotels.Hotel, err
 	defer m.mu.RUnlock()
 	h, ok := m.db[id]
 	if !ok {
-		return domain_hotels.Hotel{}, fmt.Errorf("not found")
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	return h, nil
+}
+
+func (m *Mock) Archive(ctx context.Context, id string, at time.Time) (domain_hotels.Hotel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	h.Archived = true
+	h.ArchivedAt = &at
+	h.Version++
+	m.db[id] = h
+	return h, nil
+}
+
+func (m *Mock) UpdateRooms(ctx context.Context, id string, rooms []domain_hotels.RoomType, expectedVersion int64) (domain_hotels.Hotel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	if expectedVersion != 0 && h.Version != expectedVersion {
+		return domain_hotels.Hotel{}, domain_hotels.ErrVersionConflict
+	}
+	h.Rooms = rooms
+	h.Version++
+	m.db[id] = h
+	return h, nil
+}
+
+func (m *Mock) UpdatePricing(ctx context.Context, id string, pricing domain_hotels.PricingRules) (domain_hotels.Hotel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	h.Pricing = &pricing
+	h.Version++
+	m.db[id] = h
+	return h, nil
+}
+
+func (m *Mock) UpdateImages(ctx context.Context, id string, images []domain_hotels.Image, expectedVersion int64) (domain_hotels.Hotel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	if expectedVersion != 0 && h.Version != expectedVersion {
+		return domain_hotels.Hotel{}, domain_hotels.ErrVersionConflict
+	}
+	h.Images = images
+	h.Version++
+	m.db[id] = h
+	return h, nil
+}
+
+// UpdateRating guarda el promedio de reseñas; es un dato derivado, no cambia la versión
+func (m *Mock) UpdateRating(ctx context.Context, id string, stats domain_hotels.RatingStats) (domain_hotels.Hotel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
 	}
+	h.RatingAvg, h.RatingCount = stats.Average, stats.Count
+	m.db[id] = h
 	return h, nil
 }
 
-func (m *Mock) List(ctx context.Context, q string) ([]domain_hotels.Hotel, error) {
+func (m *Mock) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.db[id]; !ok {
+		return domain_hotels.ErrNotFound
+	}
+	delete(m.db, id)
+	return nil
+}
+
+// List filtra, ordena y pagina en memoria con los mismos criterios que el repo de Mongo
+func (m *Mock) List(ctx context.Context, q domain_hotels.HotelQuery) (domain_hotels.HotelPage, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Si no hay query, devolvemos todo
-	if strings.TrimSpace(q) == "" {
-		out := make([]domain_hotels.Hotel, 0, len(m.db))
-		for _, v := range m.db {
-			out = append(out, v)
+	matched := make([]domain_hotels.Hotel, 0, len(m.db))
+	for _, v := range m.db {
+		if q.Matches(v) {
+			matched = append(matched, v)
 		}
-		return out, nil
 	}
+	// Un Collator no se puede compartir entre goroutines: uno por llamada
+	names := collate.New(language.Spanish, collate.IgnoreCase, collate.IgnoreDiacritics)
+	sort.Slice(matched, func(i, j int) bool {
+		return lessHotel(matched[i], matched[j], q.Sort, q.Desc, names)
+	})
+
+	start := min(q.Offset, len(matched))
+	end := min(start+q.Limit, len(matched))
+	return domain_hotels.HotelPage{
+		Items:  matched[start:end],
+		Total:  int64(len(matched)),
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}, nil
+}
+
+// lessHotel ordena por el campo pedido y desempata por id para que las páginas sean estables. Los
+// nombres se comparan como la collation es de strength 1 de Mongo: sin mayúsculas ni acentos, con
+// la ñ después de la n.
+func lessHotel(a, b domain_hotels.Hotel, field string, desc bool, names *collate.Collator) bool {
+	cmp := 0
+	switch field {
+	case domain_hotels.SortName:
+		cmp = names.CompareString(a.Name, b.Name)
+	case domain_hotels.SortPrice:
+		cmp = compareFloat(a.PricePerNight, b.PricePerNight)
+	case domain_hotels.SortStars:
+		cmp = a.Stars - b.Stars
+	}
+	if desc {
+		cmp = -cmp
+	}
+	if cmp != 0 {
+		return cmp < 0
+	}
+	return a.ID < b.ID
+}
 
-	// Filtro simple por nombre o ciudad (case-insensitive)
-	q = strings.ToLower(q)
-	out := make([]domain_hotels.Hotel, 0, len(m.db))
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ListByOwner: los hoteles de un dueño, archivados incluidos
+func (m *Mock) ListByOwner(ctx context.Context, ownerID string) ([]domain_hotels.Hotel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]domain_hotels.Hotel, 0)
 	for _, v := range m.db {
-		if strings.Contains(strings.ToLower(v.Name), q) || strings.Contains(strings.ToLower(v.City), q) {
+		if v.OwnerID == ownerID {
 			out = append(out, v)
 		}
 	}
 	return out, nil
 }
 
-// SeedFromJSON carga hoteles desde un archivo JSON (ej: "db/hotels.json")
-func (m *Mock) SeedFromJSON(path string) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
+// Iterate recorre los hoteles no archivados por id ("" = todos los dueños). fn corre sin el
+// lock tomado, así puede escribir la respuesta tranquilo.
+func (m *Mock) Iterate(ctx context.Context, ownerID string, fn func(domain_hotels.Hotel) error) error {
+	m.mu.RLock()
+	hotels := make([]domain_hotels.Hotel, 0, len(m.db))
+	for _, v := range m.db {
+		if !v.Archived && (ownerID == "" || v.OwnerID == ownerID) {
+			hotels = append(hotels, v)
+		}
 	}
-	defer f.Close()
+	m.mu.RUnlock()
 
+	sort.Slice(hotels, func(i, j int) bool { return hotels[i].ID < hotels[j].ID })
+	for _, h := range hotels {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSeedFile lee el JSON de hoteles de ejemplo (el archivo puede venir con BOM). Los hoteles
+// sin id quedan afuera y se avisa en el log: con un id generado, Mongo los volvería a insertar
+// en cada arranque.
+func readSeedFile(path string) ([]domain_hotels.Hotel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 	var items []domain_hotels.Hotel
-	if err := json.NewDecoder(f).Decode(&items); err != nil {
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &items); err != nil {
+		return nil, err
+	}
+	seed := make([]domain_hotels.Hotel, 0, len(items))
+	for i, h := range items {
+		if strings.TrimSpace(h.ID) == "" {
+			log.Printf("skipping hotel #%d (%q) in %s: missing id", i+1, h.Name, path)
+			continue
+		}
+		if h.Version == 0 {
+			h.Version = 1
+		}
+		seed = append(seed, h)
+	}
+	return seed, nil
+}
+
+// SeedFromJSON carga hoteles desde un archivo JSON (ej: "db/hotels.json")
+func (m *Mock) SeedFromJSON(path string) error {
+	items, err := readSeedFile(path)
+	if err != nil {
 		return err
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	for _, h := range items {
-		if h.ID == "" {
-			continue
-		}
 		m.db[h.ID] = h
 	}
 	return nil
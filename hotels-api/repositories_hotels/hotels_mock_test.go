@@ -0,0 +1,158 @@
+package repositories_hotels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+	repositories "hotels/repositories_hotels"
+)
+
+func TestMockUpdate(t *testing.T) {
+	ctx := context.Background()
+	hotel := domain_hotels.Hotel{Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4}
+
+	t.Run("Update - Expected Version Must Match", func(t *testing.T) {
+		repo := repositories.NewMock()
+		h, err := repo.Create(ctx, hotel)
+		require.NoError(t, err)
+
+		_, err = repo.Update(ctx, h.ID, hotel, 2)
+		assert.ErrorIs(t, err, domain_hotels.ErrVersionConflict)
+
+		out, err := repo.Update(ctx, h.ID, hotel, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), out.Version)
+	})
+
+	t.Run("Update - Version 0 Means No Check", func(t *testing.T) {
+		repo := repositories.NewMock()
+		h, err := repo.Create(ctx, hotel)
+		require.NoError(t, err)
+
+		for want := int64(2); want <= 3; want++ {
+			out, err := repo.Update(ctx, h.ID, hotel, 0)
+			require.NoError(t, err)
+			assert.Equal(t, want, out.Version)
+		}
+	})
+
+	t.Run("UpdateRooms - Expected Version Must Match", func(t *testing.T) {
+		repo := repositories.NewMock()
+		h, err := repo.Create(ctx, hotel)
+		require.NoError(t, err)
+		_, err = repo.Update(ctx, h.ID, hotel, 0) // otro cambio entre la lectura y la escritura
+		require.NoError(t, err)
+		rooms := []domain_hotels.RoomType{{ID: "rt_1", Name: "Suite", Capacity: 2, Units: 1, BasePrice: 1000}}
+
+		_, err = repo.UpdateRooms(ctx, h.ID, rooms, h.Version)
+		assert.ErrorIs(t, err, domain_hotels.ErrVersionConflict)
+
+		out, err := repo.UpdateRooms(ctx, h.ID, rooms, h.Version+1)
+		require.NoError(t, err)
+		assert.Equal(t, rooms, out.Rooms)
+		assert.Equal(t, int64(3), out.Version)
+	})
+
+	t.Run("UpdateImages - Expected Version Must Match", func(t *testing.T) {
+		repo := repositories.NewMock()
+		h, err := repo.Create(ctx, hotel)
+		require.NoError(t, err)
+		_, err = repo.Update(ctx, h.ID, hotel, 0)
+		require.NoError(t, err)
+		images := []domain_hotels.Image{{ID: "img_1", URL: "/images/hotels/" + h.ID + "/img_1.png"}}
+
+		_, err = repo.UpdateImages(ctx, h.ID, images, h.Version)
+		assert.ErrorIs(t, err, domain_hotels.ErrVersionConflict)
+
+		out, err := repo.UpdateImages(ctx, h.ID, images, h.Version+1)
+		require.NoError(t, err)
+		assert.Equal(t, images, out.Images)
+	})
+
+	t.Run("Update - Missing Hotel", func(t *testing.T) {
+		_, err := repositories.NewMock().Update(ctx, "missing", hotel, 0)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrNotFound)
+	})
+}
+
+func TestMockList(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewMock()
+	for _, h := range []domain_hotels.Hotel{
+		{ID: "h1", Name: "Ñandú", City: "Salta", PricePerNight: 700, Stars: 3},
+		{ID: "h2", Name: "Álamo", City: "Córdoba", PricePerNight: 1500, Stars: 5, Amenities: []string{"pool"}},
+		{ID: "h3", Name: "nogal", City: "Córdoba", PricePerNight: 900, Stars: 4},
+		{ID: "h4", Name: "Abedul", City: "Mendoza", PricePerNight: 900, Stars: 4, Amenities: []string{"pool"}},
+		{ID: "h5", Name: "Olmo", City: "Córdoba", PricePerNight: 800, Stars: 2},
+		{ID: "h6", Name: "Archivado", City: "Córdoba", PricePerNight: 100, Stars: 1, Archived: true},
+	} {
+		_, err := repo.Create(ctx, h)
+		require.NoError(t, err)
+	}
+	ids := func(page domain_hotels.HotelPage) []string {
+		out := make([]string, 0, len(page.Items))
+		for _, h := range page.Items {
+			out = append(out, h.ID)
+		}
+		return out
+	}
+
+	t.Run("List - Sort", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sort string
+			desc bool
+			want []string
+		}{
+			// Sin acentos ni mayúsculas, la ñ después de la n (como la collation es de Mongo)
+			{"by name", domain_hotels.SortName, false, []string{"h4", "h2", "h3", "h1", "h5"}},
+			{"by name desc", domain_hotels.SortName, true, []string{"h5", "h1", "h3", "h2", "h4"}},
+			// Los empates se ordenan por id también en descendente
+			{"by price", domain_hotels.SortPrice, false, []string{"h1", "h5", "h3", "h4", "h2"}},
+			{"by stars desc", domain_hotels.SortStars, true, []string{"h2", "h3", "h4", "h1", "h5"}},
+			{"default by id", "", false, []string{"h1", "h2", "h3", "h4", "h5"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := repo.List(ctx, domain_hotels.HotelQuery{Sort: tt.sort, Desc: tt.desc, Limit: 10})
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, ids(page))
+				assert.Equal(t, int64(5), page.Total)
+			})
+		}
+	})
+
+	t.Run("List - Filters", func(t *testing.T) {
+		page, err := repo.List(ctx, domain_hotels.HotelQuery{City: "córdoba", MinStars: 3, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"h2", "h3"}, ids(page))
+
+		page, err = repo.List(ctx, domain_hotels.HotelQuery{Amenity: "pool", MaxPrice: 1000, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"h4"}, ids(page))
+	})
+
+	t.Run("List - Pagination", func(t *testing.T) {
+		q := domain_hotels.HotelQuery{Sort: domain_hotels.SortName, Limit: 2}
+		var all []string
+		for q.Offset = 0; q.Offset < 6; q.Offset += q.Limit {
+			page, err := repo.List(ctx, q)
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), page.Total)
+			assert.Equal(t, q.Offset, page.Offset)
+			all = append(all, ids(page)...)
+		}
+		assert.Equal(t, []string{"h4", "h2", "h3", "h1", "h5"}, all)
+
+		page, err := repo.List(ctx, domain_hotels.HotelQuery{Offset: 50, Limit: 2})
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+		assert.Equal(t, int64(5), page.Total)
+	})
+}
@@ -1,16 +1,17 @@
-﻿//go:build ignore
-
-package repositories_hotels
+﻿package repositories_hotels
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"regexp"
+	"time"
 
 	"hotels/dao_hotels"
 	"hotels/domain_hotels"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -31,25 +32,59 @@ type Mongo struct {
 	collection string
 }
 
-const connectionURI = "mongodb://%s:%s"
+const (
+	connectionURI  = "mongodb://%s:%s"
+	connectTimeout = 10 * time.Second
+)
 
-// NewMongo: crea el cliente y lo deja listo
-func NewMongo(cfg MongoConfig) *Mongo {
+// NewMongo: crea el cliente, verifica la conexión con un ping y crea los índices
+func NewMongo(cfg MongoConfig) (*Mongo, error) {
 	cred := options.Credential{
 		Username: cfg.Username,
 		Password: cfg.Password,
 	}
-	cli, err := mongo.Connect(context.Background(),
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	cli, err := mongo.Connect(ctx,
 		options.Client().ApplyURI(fmt.Sprintf(connectionURI, cfg.Host, cfg.Port)).SetAuth(cred),
 	)
 	if err != nil {
-		log.Panicf("error connecting to mongo DB: %v", err)
+		return nil, fmt.Errorf("error connecting to mongo DB: %w", err)
+	}
+	if err := cli.Ping(ctx, nil); err != nil {
+		_ = cli.Disconnect(context.Background())
+		return nil, fmt.Errorf("error pinging mongo DB: %w", err)
 	}
-	return &Mongo{
+
+	m := &Mongo{
 		client:     cli,
 		database:   cfg.Database,
 		collection: cfg.Collection,
 	}
+	if err := m.ensureIndexes(ctx); err != nil {
+		_ = cli.Disconnect(context.Background())
+		return nil, err
+	}
+	return m, nil
+}
+
+// Close: cierra la conexión
+func (m *Mongo) Close(ctx context.Context) error {
+	return m.client.Disconnect(ctx)
+}
+
+// ensureIndexes: índices para los filtros más usados (es idempotente, se corre en cada arranque)
+func (m *Mongo) ensureIndexes(ctx context.Context) error {
+	_, err := m.col().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "city", Value: 1}}},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}}},
+		{Keys: bson.D{{Key: "stars", Value: 1}, {Key: "price_per_night", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes: %w", err)
+	}
+	return nil
 }
 
 // helper: colección
@@ -62,9 +97,9 @@ func (m *Mongo) Create(ctx context.Context, h domain_hotels.Hotel) (domain_hotel
 	// Permitimos _id string (Hex) para que el dominio sea string
 	dao := dao_hotels.FromDomain(h)
 	if dao.ID == "" {
-		// Generamos un ID simple string (podés cambiar a hex o UUID si querés)
-		dao.ID = fmt.Sprintf("h_%d", newID())
+		dao.ID = newID()
 	}
+	dao.Version = 1
 	_, err := m.col().InsertOne(ctx, dao)
 	if err != nil {
 		return domain_hotels.Hotel{}, fmt.Errorf("error creating document: %w", err)
@@ -72,74 +107,239 @@ func (m *Mongo) Create(ctx context.Context, h domain_hotels.Hotel) (domain_hotel
 	return dao.ToDomain(), nil
 }
 
-// Update: actualiza por id (solo setea los campos que vienen)
-func (m *Mongo) Update(ctx context.Context, id string, h domain_hotels.Hotel) (domain_hotels.Hotel, error) {
-	update := bson.M{}
-	if h.Name != "" {
-		update["name"] = h.Name
+// Update reemplaza los datos editables del hotel si la versión sigue siendo expectedVersion
+// (0 = sin chequeo). Habitaciones, tarifas, galería y archivado tienen sus propios métodos.
+func (m *Mongo) Update(ctx context.Context, id string, h domain_hotels.Hotel, expectedVersion int64) (domain_hotels.Hotel, error) {
+	set := bson.M{
+		"name":            h.Name,
+		"city":            h.City,
+		"price_per_night": h.PricePerNight,
+		"stars":           h.Stars,
+		"amenities":       h.Amenities,
+		"owner_id":        h.OwnerID,
+	}
+	unset := bson.M{}
+	if h.Address != "" {
+		set["address"] = h.Address
+	} else {
+		unset["address"] = ""
+	}
+	if h.HasLocation() {
+		set["latitude"] = *h.Latitude
+		set["longitude"] = *h.Longitude
+	} else {
+		unset["latitude"] = ""
+		unset["longitude"] = ""
 	}
-	if h.City != "" {
-		update["city"] = h.City
+
+	update := bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
 	}
-	if h.PricePerNight != 0 {
-		update["price_per_night"] = h.PricePerNight
+	res, err := m.col().UpdateOne(ctx, versionFilter(id, expectedVersion), update)
+	if err != nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("error updating document: %w", err)
 	}
-	if h.Stars != 0 {
-		update["stars"] = h.Stars
+	if res.MatchedCount == 0 {
+		return domain_hotels.Hotel{}, m.missOrConflict(ctx, id)
 	}
-	if h.Amenities != nil {
-		update["amenities"] = h.Amenities
+	return m.GetByID(ctx, id)
+}
+
+// versionFilter: los documentos anteriores al campo version cuentan como versión 0
+func versionFilter(id string, expectedVersion int64) bson.M {
+	filter := bson.M{"_id": id}
+	if expectedVersion != 0 {
+		filter["version"] = expectedVersion
 	}
-	if h.OwnerID != "" {
-		update["owner_id"] = h.OwnerID
+	return filter
+}
+
+// missOrConflict distingue, cuando el update no matcheó, si el hotel no existe o cambió de versión
+func (m *Mongo) missOrConflict(ctx context.Context, id string) error {
+	n, err := m.col().CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("error checking document: %w", err)
 	}
-	if len(update) == 0 {
-		// nada para actualizar; devolvemos el actual
-		return m.GetByID(ctx, id)
+	if n == 0 {
+		return domain_hotels.ErrNotFound
 	}
+	return domain_hotels.ErrVersionConflict
+}
 
-	res, err := m.col().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+// Archive: borrado lógico, el hotel queda pero no se lista
+func (m *Mongo) Archive(ctx context.Context, id string, at time.Time) (domain_hotels.Hotel, error) {
+	res, err := m.col().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"archived": true, "archived_at": at}, "$inc": bson.M{"version": 1}})
 	if err != nil {
-		return domain_hotels.Hotel{}, fmt.Errorf("error updating document: %w", err)
+		return domain_hotels.Hotel{}, fmt.Errorf("error archiving document: %w", err)
 	}
 	if res.MatchedCount == 0 {
-		return domain_hotels.Hotel{}, fmt.Errorf("hotel %s not found", id)
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
 	}
 	return m.GetByID(ctx, id)
 }
 
+// UpdateRooms: reemplaza la lista de tipos de habitación si la versión sigue siendo
+// expectedVersion (la lista se arma sobre la leída, así no se pisa un cambio concurrente)
+func (m *Mongo) UpdateRooms(ctx context.Context, id string, rooms []domain_hotels.RoomType, expectedVersion int64) (domain_hotels.Hotel, error) {
+	res, err := m.col().UpdateOne(ctx, versionFilter(id, expectedVersion), bson.M{"$set": bson.M{"rooms": rooms}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("error updating rooms: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain_hotels.Hotel{}, m.missOrConflict(ctx, id)
+	}
+	return m.GetByID(ctx, id)
+}
+
+// UpdatePricing: reemplaza las reglas de precios
+func (m *Mongo) UpdatePricing(ctx context.Context, id string, pricing domain_hotels.PricingRules) (domain_hotels.Hotel, error) {
+	res, err := m.col().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"pricing": pricing}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("error updating pricing: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	return m.GetByID(ctx, id)
+}
+
+// UpdateImages: reemplaza la galería (el orden del slice es el de la galería) si la versión
+// sigue siendo expectedVersion, igual que UpdateRooms
+func (m *Mongo) UpdateImages(ctx context.Context, id string, images []domain_hotels.Image, expectedVersion int64) (domain_hotels.Hotel, error) {
+	res, err := m.col().UpdateOne(ctx, versionFilter(id, expectedVersion), bson.M{"$set": bson.M{"images": images}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("error updating images: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain_hotels.Hotel{}, m.missOrConflict(ctx, id)
+	}
+	return m.GetByID(ctx, id)
+}
+
+// UpdateRating guarda el promedio de reseñas; es un dato derivado, no cambia la versión
+func (m *Mongo) UpdateRating(ctx context.Context, id string, stats domain_hotels.RatingStats) (domain_hotels.Hotel, error) {
+	res, err := m.col().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"rating_avg": stats.Average, "rating_count": stats.Count}})
+	if err != nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("error updating rating: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	return m.GetByID(ctx, id)
+}
+
+// Delete: borrado físico
+func (m *Mongo) Delete(ctx context.Context, id string) error {
+	res, err := m.col().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("error deleting document: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain_hotels.ErrNotFound
+	}
+	return nil
+}
+
 // GetByID: busca por _id string
 func (m *Mongo) GetByID(ctx context.Context, id string) (domain_hotels.Hotel, error) {
 	var dao dao_hotels.Hotel
 	err := m.col().FindOne(ctx, bson.M{"_id": id}).Decode(&dao)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return domain_hotels.Hotel{}, fmt.Errorf("not found")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
 		}
 		return domain_hotels.Hotel{}, fmt.Errorf("error finding document: %w", err)
 	}
 	return dao.ToDomain(), nil
 }
 
-// List: lista todos (o filtra por nombre/ciudad con q)
-func (m *Mongo) List(ctx context.Context, q string) ([]domain_hotels.Hotel, error) {
-	filter := bson.M{}
-	if q != "" {
-		filter = bson.M{
-			"$or": []bson.M{
-				{"name": bson.M{"$regex": q, "$options": "i"}},
-				{"city": bson.M{"$regex": q, "$options": "i"}},
-			},
+// Campos de Mongo para cada orden de HotelQuery
+var sortFields = map[string]string{
+	domain_hotels.SortName:  "name",
+	domain_hotels.SortPrice: "price_per_night",
+	domain_hotels.SortStars: "stars",
+}
+
+// List: filtra, ordena y pagina en la base; Total es la cantidad sin paginar
+func (m *Mongo) List(ctx context.Context, q domain_hotels.HotelQuery) (domain_hotels.HotelPage, error) {
+	filter := listFilter(q)
+
+	total, err := m.col().CountDocuments(ctx, filter)
+	if err != nil {
+		return domain_hotels.HotelPage{}, fmt.Errorf("error counting documents: %w", err)
+	}
+
+	// Siempre se desempata por _id para que las páginas no se pisen
+	direction := 1
+	if q.Desc {
+		direction = -1
+	}
+	sortBy := bson.D{}
+	if field, ok := sortFields[q.Sort]; ok {
+		sortBy = append(sortBy, bson.E{Key: field, Value: direction})
+	}
+	sortBy = append(sortBy, bson.E{Key: "_id", Value: 1})
+
+	opts := options.Find().SetSort(sortBy).SetSkip(int64(q.Offset)).SetLimit(int64(q.Limit))
+	if q.Sort == domain_hotels.SortName {
+		// orden alfabético sin distinguir mayúsculas ni acentos, igual que el mock
+		opts.SetCollation(&options.Collation{Locale: "es", Strength: 1})
+	}
+	cur, err := m.col().Find(ctx, filter, opts)
+	if err != nil {
+		return domain_hotels.HotelPage{}, fmt.Errorf("error getting documents: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	list := make([]domain_hotels.Hotel, 0)
+	for cur.Next(ctx) {
+		var dao dao_hotels.Hotel
+		if err := cur.Decode(&dao); err != nil {
+			return domain_hotels.HotelPage{}, fmt.Errorf("error decoding document: %w", err)
+		}
+		list = append(list, dao.ToDomain())
+	}
+	if err := cur.Err(); err != nil {
+		return domain_hotels.HotelPage{}, fmt.Errorf("cursor error: %w", err)
+	}
+	return domain_hotels.HotelPage{Items: list, Total: total, Limit: q.Limit, Offset: q.Offset}, nil
+}
+
+// listFilter traduce HotelQuery a bson (el texto se escapa: no es una regex del usuario)
+func listFilter(q domain_hotels.HotelQuery) bson.M {
+	filter := bson.M{"archived": bson.M{"$ne": true}}
+	if q.Q != "" {
+		text := primitive.Regex{Pattern: regexp.QuoteMeta(q.Q), Options: "i"}
+		filter["$or"] = []bson.M{
+			{"name": text},
+			{"city": text},
 		}
 	}
+	if q.City != "" {
+		filter["city"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(q.City) + "$", Options: "i"}
+	}
+	if q.MinStars > 0 {
+		filter["stars"] = bson.M{"$gte": q.MinStars}
+	}
+	if q.MaxPrice > 0 {
+		filter["price_per_night"] = bson.M{"$lte": q.MaxPrice}
+	}
+	if q.Amenity != "" {
+		filter["amenities"] = primitive.Regex{Pattern: "^\\s*" + regexp.QuoteMeta(q.Amenity) + "\\s*$", Options: "i"}
+	}
+	return filter
+}
 
-	cur, err := m.col().Find(ctx, filter)
+// ListByOwner: los hoteles de un dueño, archivados incluidos (usa el índice de owner_id)
+func (m *Mongo) ListByOwner(ctx context.Context, ownerID string) ([]domain_hotels.Hotel, error) {
+	cur, err := m.col().Find(ctx, bson.M{"owner_id": ownerID})
 	if err != nil {
 		return nil, fmt.Errorf("error getting documents: %w", err)
 	}
 	defer cur.Close(ctx)
 
-	var list []domain_hotels.Hotel
+	list := make([]domain_hotels.Hotel, 0)
 	for cur.Next(ctx) {
 		var dao dao_hotels.Hotel
 		if err := cur.Decode(&dao); err != nil {
@@ -153,10 +353,60 @@ func (m *Mongo) List(ctx context.Context, q string) ([]domain_hotels.Hotel, erro
 	return list, nil
 }
 
-// newID: contador simple en memoria (para demo). Cambialo por uuid o similar si querés.
-var _idCounter int64
+// Iterate recorre los hoteles no archivados por _id con un cursor ("" = todos los dueños), sin
+// cargar la colección entera en memoria
+func (m *Mongo) Iterate(ctx context.Context, ownerID string, fn func(domain_hotels.Hotel) error) error {
+	filter := bson.M{"archived": bson.M{"$ne": true}}
+	if ownerID != "" {
+		filter["owner_id"] = ownerID
+	}
+	cur, err := m.col().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("error getting documents: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var dao dao_hotels.Hotel
+		if err := cur.Decode(&dao); err != nil {
+			return fmt.Errorf("error decoding document: %w", err)
+		}
+		if err := fn(dao.ToDomain()); err != nil {
+			return err
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return fmt.Errorf("cursor error: %w", err)
+	}
+	return nil
+}
+
+// SeedFromJSON carga hoteles de ejemplo; los que ya existen (mismo id) no se tocan
+func (m *Mongo) SeedFromJSON(path string) error {
+	items, err := readSeedFile(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	for _, h := range items {
+		// El _id sale del filtro al insertar
+		dao := dao_hotels.FromDomain(h)
+		dao.ID = ""
+		_, err := m.col().UpdateOne(ctx,
+			bson.M{"_id": h.ID},
+			bson.M{"$setOnInsert": dao},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("error seeding hotel %s: %w", h.ID, err)
+		}
+	}
+	return nil
+}
 
-func newID() int64 {
-	_idCounter++
-	return _idCounter
+// newID: ObjectID en hex; el _id queda como string para que el dominio no dependa de Mongo
+func newID() string {
+	return primitive.NewObjectID().Hex()
 }
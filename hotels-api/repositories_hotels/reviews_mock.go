@@ -0,0 +1,120 @@
+package repositories_hotels
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"hotels/domain_hotels"
+)
+
+// ReviewsMock guarda las reseñas en memoria (se usa con HOTELS_REPOSITORY=memory)
+type ReviewsMock struct {
+	mu sync.RWMutex
+	db map[string]domain_hotels.Review
+}
+
+func NewReviewsMock() *ReviewsMock {
+	return &ReviewsMock{db: map[string]domain_hotels.Review{}}
+}
+
+// Create: una reseña por usuario y hotel
+func (m *ReviewsMock) Create(ctx context.Context, r domain_hotels.Review) (domain_hotels.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.db {
+		if existing.HotelID == r.HotelID && existing.UserID == r.UserID {
+			return domain_hotels.Review{}, domain_hotels.ErrAlreadyReviewed
+		}
+	}
+	if r.ID == "" {
+		r.ID = domain_hotels.NewReviewID()
+	}
+	m.db[r.ID] = r
+	return r, nil
+}
+
+func (m *ReviewsMock) GetByID(ctx context.Context, id string) (domain_hotels.Review, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Review{}, domain_hotels.ErrReviewNotFound
+	}
+	return r, nil
+}
+
+// List: las más nuevas primero
+func (m *ReviewsMock) List(ctx context.Context, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matched := make([]domain_hotels.Review, 0)
+	for _, r := range m.db {
+		if (q.HotelID == "" || r.HotelID == q.HotelID) && (q.Status == "" || r.Status == q.Status) {
+			matched = append(matched, r)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	start := min(q.Offset, len(matched))
+	end := min(start+q.Limit, len(matched))
+	return domain_hotels.ReviewPage{
+		Items:  matched[start:end],
+		Total:  int64(len(matched)),
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}, nil
+}
+
+func (m *ReviewsMock) Moderate(ctx context.Context, id string, status string, note string, at time.Time) (domain_hotels.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.db[id]
+	if !ok {
+		return domain_hotels.Review{}, domain_hotels.ErrReviewNotFound
+	}
+	r.Status = status
+	r.ModerationNote = note
+	r.ModeratedAt = &at
+	m.db[id] = r
+	return r, nil
+}
+
+func (m *ReviewsMock) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.db[id]; !ok {
+		return domain_hotels.ErrReviewNotFound
+	}
+	delete(m.db, id)
+	return nil
+}
+
+// Stats: promedio (a un decimal) y cantidad de reseñas aprobadas del hotel
+func (m *ReviewsMock) Stats(ctx context.Context, hotelID string) (domain_hotels.RatingStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sum, count := 0, 0
+	for _, r := range m.db {
+		if r.HotelID == hotelID && r.Status == domain_hotels.ReviewApproved {
+			sum += r.Rating
+			count++
+		}
+	}
+	if count == 0 {
+		return domain_hotels.RatingStats{}, nil
+	}
+	return domain_hotels.RatingStats{Average: roundRating(float64(sum) / float64(count)), Count: count}, nil
+}
+
+func roundRating(avg float64) float64 {
+	return math.Round(avg*10) / 10
+}
@@ -0,0 +1,139 @@
+package repositories_hotels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hotels/domain_hotels"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoReviews guarda las reseñas en su propia colección, usando la conexión del repo de hoteles
+type MongoReviews struct {
+	col *mongo.Collection
+}
+
+func NewMongoReviews(m *Mongo, collection string) (*MongoReviews, error) {
+	r := &MongoReviews{col: m.client.Database(m.database).Collection(collection)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	_, err := r.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hotel_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		// una reseña por usuario y hotel
+		{Keys: bson.D{{Key: "hotel_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating review indexes: %w", err)
+	}
+	return r, nil
+}
+
+func (r *MongoReviews) Create(ctx context.Context, review domain_hotels.Review) (domain_hotels.Review, error) {
+	if review.ID == "" {
+		review.ID = domain_hotels.NewReviewID()
+	}
+	if _, err := r.col.InsertOne(ctx, review); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain_hotels.Review{}, domain_hotels.ErrAlreadyReviewed
+		}
+		return domain_hotels.Review{}, fmt.Errorf("error creating review: %w", err)
+	}
+	return review, nil
+}
+
+func (r *MongoReviews) GetByID(ctx context.Context, id string) (domain_hotels.Review, error) {
+	var review domain_hotels.Review
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&review)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain_hotels.Review{}, domain_hotels.ErrReviewNotFound
+		}
+		return domain_hotels.Review{}, fmt.Errorf("error getting review: %w", err)
+	}
+	return review, nil
+}
+
+// List: las más nuevas primero
+func (r *MongoReviews) List(ctx context.Context, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error) {
+	filter := bson.M{}
+	if q.HotelID != "" {
+		filter["hotel_id"] = q.HotelID
+	}
+	if q.Status != "" {
+		filter["status"] = q.Status
+	}
+
+	total, err := r.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return domain_hotels.ReviewPage{}, fmt.Errorf("error counting reviews: %w", err)
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(q.Offset)).
+		SetLimit(int64(q.Limit))
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return domain_hotels.ReviewPage{}, fmt.Errorf("error getting reviews: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	items := make([]domain_hotels.Review, 0)
+	if err := cur.All(ctx, &items); err != nil {
+		return domain_hotels.ReviewPage{}, fmt.Errorf("error decoding reviews: %w", err)
+	}
+	return domain_hotels.ReviewPage{Items: items, Total: total, Limit: q.Limit, Offset: q.Offset}, nil
+}
+
+func (r *MongoReviews) Moderate(ctx context.Context, id string, status string, note string, at time.Time) (domain_hotels.Review, error) {
+	update := bson.M{"$set": bson.M{"status": status, "moderation_note": note, "moderated_at": at}}
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return domain_hotels.Review{}, fmt.Errorf("error moderating review: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain_hotels.Review{}, domain_hotels.ErrReviewNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *MongoReviews) Delete(ctx context.Context, id string) error {
+	res, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("error deleting review: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain_hotels.ErrReviewNotFound
+	}
+	return nil
+}
+
+// Stats: promedio (a un decimal) y cantidad de reseñas aprobadas, calculado en la base
+func (r *MongoReviews) Stats(ctx context.Context, hotelID string) (domain_hotels.RatingStats, error) {
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"hotel_id": hotelID, "status": domain_hotels.ReviewApproved}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "avg": bson.M{"$avg": "$rating"}, "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return domain_hotels.RatingStats{}, fmt.Errorf("error aggregating reviews: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var rows []struct {
+		Avg   float64 `bson:"avg"`
+		Count int     `bson:"count"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return domain_hotels.RatingStats{}, fmt.Errorf("error decoding review stats: %w", err)
+	}
+	if len(rows) == 0 {
+		return domain_hotels.RatingStats{}, nil
+	}
+	return domain_hotels.RatingStats{Average: roundRating(rows[0].Avg), Count: rows[0].Count}, nil
+}
@@ -0,0 +1,106 @@
+package services_hotels
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"hotels/domain_hotels"
+)
+
+// El historial de cambios de cada hotel (mismo backend que los hoteles)
+type AuditRepository interface {
+	Append(ctx context.Context, entry domain_hotels.AuditEntry) error
+	ListByHotel(ctx context.Context, hotelID string, limit int, offset int) (domain_hotels.AuditPage, error)
+}
+
+// Campos que no se auditan: los maneja el sistema, no un usuario
+var unauditedFields = map[string]bool{
+	"version":      true,
+	"rating_avg":   true,
+	"rating_count": true,
+}
+
+// History: los cambios del hotel, los más nuevos primero (dueño o admin)
+func (s *Service) History(ctx context.Context, hotelID string, limit int, offset int) (domain_hotels.AuditPage, error) {
+	if _, err := s.ownedHotel(ctx, hotelID); err != nil {
+		return domain_hotels.AuditPage{}, err
+	}
+	if offset < 0 || limit < 0 || limit > domain_hotels.MaxPageSize {
+		return domain_hotels.AuditPage{}, domain_hotels.ErrInvalidQuery
+	}
+	if limit == 0 {
+		limit = domain_hotels.DefaultPageSize
+	}
+	return s.audit.ListByHotel(ctx, hotelID, limit, offset)
+}
+
+// record guarda quién cambió qué. El cambio ya se hizo, así que si el historial falla solo se
+// loguea (no se le devuelve error al cliente por algo que sí se guardó).
+func (s *Service) record(ctx context.Context, action string, before domain_hotels.Hotel, after domain_hotels.Hotel) {
+	changes := diffHotels(before, after)
+	if action == domain_hotels.AuditUpdate && len(changes) == 0 {
+		return
+	}
+	hotelID := after.ID
+	if hotelID == "" {
+		hotelID = before.ID
+	}
+
+	actor, _ := domain_hotels.ActorFrom(ctx)
+	entry := domain_hotels.AuditEntry{
+		ID:            domain_hotels.NewAuditID(),
+		HotelID:       hotelID,
+		Action:        action,
+		ActorID:       actor.UserID,
+		ActorAdmin:    actor.Admin,
+		At:            time.Now().UTC(),
+		Changes:       changes,
+		CorrelationID: domain_hotels.CorrelationIDFrom(ctx),
+	}
+	if err := s.audit.Append(ctx, entry); err != nil {
+		log.Printf("error recording %s of hotel %s in history: %v", action, hotelID, err)
+	}
+}
+
+// diffHotels compara la representación JSON de los dos hoteles campo por campo
+func diffHotels(before domain_hotels.Hotel, after domain_hotels.Hotel) []domain_hotels.FieldChange {
+	from, to := hotelFields(before), hotelFields(after)
+
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := make([]domain_hotels.FieldChange, 0)
+	for _, name := range names {
+		if unauditedFields[name] || reflect.DeepEqual(from[name], to[name]) {
+			continue
+		}
+		changes = append(changes, domain_hotels.FieldChange{Field: name, From: from[name], To: to[name]})
+	}
+	return changes
+}
+
+func hotelFields(h domain_hotels.Hotel) map[string]any {
+	fields := map[string]any{}
+	if h.ID == "" {
+		return fields // antes de crearse no hay nada
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	delete(fields, "id")
+	return fields
+}
@@ -0,0 +1,144 @@
+package services_hotels_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+	services "hotels/services_hotels"
+)
+
+func TestDiffHotels(t *testing.T) {
+	latitude, longitude := -31.42, -64.18
+	hotel := domain_hotels.Hotel{
+		ID: "h1", Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4,
+		Amenities: []string{"wifi"}, OwnerID: "u1", Version: 3,
+	}
+	with := func(change func(h *domain_hotels.Hotel)) domain_hotels.Hotel {
+		h := hotel
+		h.Amenities = append([]string{}, hotel.Amenities...)
+		change(&h)
+		return h
+	}
+
+	tests := []struct {
+		name   string
+		before domain_hotels.Hotel
+		after  domain_hotels.Hotel
+		want   []domain_hotels.FieldChange
+	}{
+		{
+			name:   "no changes",
+			before: hotel,
+			after:  with(func(h *domain_hotels.Hotel) {}),
+			want:   []domain_hotels.FieldChange{},
+		},
+		{
+			name:   "version and rating are not audited",
+			before: hotel,
+			after:  with(func(h *domain_hotels.Hotel) { h.Version, h.RatingAvg, h.RatingCount = 4, 4.5, 2 }),
+			want:   []domain_hotels.FieldChange{},
+		},
+		{
+			name:   "changed fields in name order",
+			before: hotel,
+			after: with(func(h *domain_hotels.Hotel) {
+				h.Stars, h.Name, h.Amenities = 5, "Sheraton Centro", []string{"wifi", "spa"}
+			}),
+			want: []domain_hotels.FieldChange{
+				{Field: "amenities", From: []any{"wifi"}, To: []any{"wifi", "spa"}},
+				{Field: "name", From: "Sheraton", To: "Sheraton Centro"},
+				{Field: "stars", From: 4.0, To: 5.0},
+			},
+		},
+		{
+			name:   "added and removed optional fields",
+			before: with(func(h *domain_hotels.Hotel) { h.Address = "Av. Colón 100" }),
+			after:  with(func(h *domain_hotels.Hotel) { h.Latitude, h.Longitude = &latitude, &longitude }),
+			want: []domain_hotels.FieldChange{
+				{Field: "address", From: "Av. Colón 100", To: nil},
+				{Field: "latitude", From: nil, To: -31.42},
+				{Field: "longitude", From: nil, To: -64.18},
+			},
+		},
+		{
+			name:   "nested values are compared whole",
+			before: with(func(h *domain_hotels.Hotel) { h.Pricing = &domain_hotels.PricingRules{MinStay: 2} }),
+			after:  with(func(h *domain_hotels.Hotel) { h.Pricing = &domain_hotels.PricingRules{MinStay: 3} }),
+			want: []domain_hotels.FieldChange{
+				{Field: "pricing", From: pricingJSON(2), To: pricingJSON(3)},
+			},
+		},
+		{
+			name:   "create lists every field",
+			before: domain_hotels.Hotel{},
+			after:  hotel,
+			want: []domain_hotels.FieldChange{
+				{Field: "amenities", From: nil, To: []any{"wifi"}},
+				{Field: "city", From: nil, To: "Córdoba"},
+				{Field: "name", From: nil, To: "Sheraton"},
+				{Field: "owner_id", From: nil, To: "u1"},
+				{Field: "price_per_night", From: nil, To: 1000.0},
+				{Field: "stars", From: nil, To: 4.0},
+			},
+		},
+		{
+			name:   "delete lists every field",
+			before: with(func(h *domain_hotels.Hotel) { h.Amenities = nil }),
+			after:  domain_hotels.Hotel{},
+			want: []domain_hotels.FieldChange{
+				{Field: "city", From: "Córdoba", To: nil},
+				{Field: "name", From: "Sheraton", To: nil},
+				{Field: "owner_id", From: "u1", To: nil},
+				{Field: "price_per_night", From: 1000.0, To: nil},
+				{Field: "stars", From: 4.0, To: nil},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, services.DiffHotels(tt.before, tt.after))
+		})
+	}
+}
+
+// pricingJSON: cómo queda en el historial un PricingRules con solo la estadía mínima
+func pricingJSON(minStay float64) map[string]any {
+	return map[string]any{"currency": "", "min_stay": minStay, "seasons": nil, "stay_discounts": nil, "weekend_surcharge_pct": 0.0}
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("History - Records Who Changed What", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		ctx := domain_hotels.WithCorrelationID(as("u1", false), "req-1")
+
+		_, err := ts.Patch(ctx, h.ID, []byte(`{"stars":5}`), 0)
+		require.NoError(t, err)
+		_, err = ts.Patch(ctx, h.ID, []byte(`{"stars":5}`), 0) // sin cambios no deja entrada
+		require.NoError(t, err)
+
+		page, err := ts.History(as("u1", false), h.ID, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		entry := page.Items[0]
+		assert.Equal(t, domain_hotels.AuditUpdate, entry.Action)
+		assert.Equal(t, "u1", entry.ActorID)
+		assert.False(t, entry.ActorAdmin)
+		assert.Equal(t, "req-1", entry.CorrelationID)
+		assert.Equal(t, []domain_hotels.FieldChange{{Field: "stars", From: 4.0, To: 5.0}}, entry.Changes)
+	})
+
+	t.Run("History - Only The Owner Or An Admin", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		_, err := ts.History(as("u2", false), h.ID, 0, 0)
+		assert.ErrorIs(t, err, domain_hotels.ErrForbidden)
+
+		_, err = ts.History(as("9", true), h.ID, 0, 0)
+		assert.NoError(t, err)
+	})
+}
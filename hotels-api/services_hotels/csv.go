@@ -0,0 +1,113 @@
+package services_hotels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hotels/domain_hotels"
+)
+
+// Columnas del CSV de hoteles (import y export). Las amenities van separadas con "|".
+var csvColumns = []string{"id", "name", "city", "price_per_night", "stars", "amenities", "owner_id", "address", "latitude", "longitude"}
+
+var requiredCSVColumns = []string{"name", "city", "price_per_night", "stars"}
+
+const amenitySeparator = "|"
+
+// csvIndex valida el encabezado y devuelve la posición de cada columna
+func csvIndex(header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	known := make(map[string]bool, len(csvColumns))
+	for _, column := range csvColumns {
+		known[column] = true
+	}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !known[column] {
+			return nil, fmt.Errorf("%w: unknown column %q", domain_hotels.ErrInvalidImport, column)
+		}
+		if _, dup := index[column]; dup {
+			return nil, fmt.Errorf("%w: duplicated column %q", domain_hotels.ErrInvalidImport, column)
+		}
+		index[column] = i
+	}
+	for _, column := range requiredCSVColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", domain_hotels.ErrInvalidImport, column)
+		}
+	}
+	return index, nil
+}
+
+func recordToHotel(index map[string]int, record []string) (domain_hotels.Hotel, error) {
+	field := func(name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	h := domain_hotels.Hotel{
+		ID:      field("id"),
+		Name:    field("name"),
+		City:    field("city"),
+		OwnerID: field("owner_id"),
+		Address: field("address"),
+	}
+	var err error
+	if h.PricePerNight, err = strconv.ParseFloat(field("price_per_night"), 64); err != nil {
+		return h, fmt.Errorf("price_per_night must be a number")
+	}
+	if h.Stars, err = strconv.Atoi(field("stars")); err != nil {
+		return h, fmt.Errorf("stars must be an integer")
+	}
+	if amenities := field("amenities"); amenities != "" {
+		for _, amenity := range strings.Split(amenities, amenitySeparator) {
+			if amenity = strings.TrimSpace(amenity); amenity != "" {
+				h.Amenities = append(h.Amenities, amenity)
+			}
+		}
+	}
+	if h.Latitude, err = optionalFloat(field("latitude")); err != nil {
+		return h, fmt.Errorf("latitude must be a number")
+	}
+	if h.Longitude, err = optionalFloat(field("longitude")); err != nil {
+		return h, fmt.Errorf("longitude must be a number")
+	}
+	return h, nil
+}
+
+func hotelToRecord(h domain_hotels.Hotel) []string {
+	return []string{
+		h.ID,
+		h.Name,
+		h.City,
+		strconv.FormatFloat(h.PricePerNight, 'f', -1, 64),
+		strconv.Itoa(h.Stars),
+		strings.Join(h.Amenities, amenitySeparator),
+		h.OwnerID,
+		h.Address,
+		formatOptionalFloat(h.Latitude),
+		formatOptionalFloat(h.Longitude),
+	}
+}
+
+func optionalFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
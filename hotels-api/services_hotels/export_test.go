@@ -0,0 +1,7 @@
+package services_hotels
+
+// Funciones internas que prueban los tests de services_hotels_test
+var (
+	MergePatch = mergePatch
+	DiffHotels = diffHotels
+)
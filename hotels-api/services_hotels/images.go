@@ -0,0 +1,202 @@
+package services_hotels
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // decoders para image.Decode
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"strings"
+	"time"
+
+	"hotels/domain_hotels"
+	"hotels/storage_hotels"
+)
+
+const (
+	thumbnailWidth = 320
+	maxImagePixels = 40_000_000 // un PNG chico puede declarar dimensiones enormes
+	imagesURLPath  = "/images/"
+)
+
+// Formatos aceptados y la extensión con la que se guardan
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func (s *Service) ListImages(ctx context.Context, hotelID string) ([]domain_hotels.Image, error) {
+	h, err := s.repo.GetByID(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	if h.Images == nil {
+		return []domain_hotels.Image{}, nil
+	}
+	return h.Images, nil
+}
+
+// AddImage valida la imagen (tipo real por contenido, no por extensión), la guarda junto con
+// un thumbnail y la agrega al final de la galería
+func (s *Service) AddImage(ctx context.Context, hotelID string, data []byte) (domain_hotels.Image, error) {
+	if len(data) > domain_hotels.MaxImageSize {
+		return domain_hotels.Image{}, domain_hotels.ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return domain_hotels.Image{}, domain_hotels.ErrUnsupportedImage
+	}
+
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.Image{}, err
+	}
+	if len(h.Images) >= domain_hotels.MaxImagesPerHotel {
+		return domain_hotels.Image{}, domain_hotels.ErrTooManyImages
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > maxImagePixels {
+		return domain_hotels.Image{}, domain_hotels.ErrUnsupportedImage
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return domain_hotels.Image{}, domain_hotels.ErrUnsupportedImage
+	}
+	thumb, err := thumbnail(src)
+	if err != nil {
+		return domain_hotels.Image{}, fmt.Errorf("error generating thumbnail: %w", err)
+	}
+
+	id := domain_hotels.NewImageID()
+	key := imageKey(hotelID, id+ext)
+	thumbKey := imageKey(hotelID, id+"_thumb.jpg")
+	if err := s.blobs.Put(ctx, key, data); err != nil {
+		return domain_hotels.Image{}, err
+	}
+	if err := s.blobs.Put(ctx, thumbKey, thumb); err != nil {
+		_ = s.blobs.Delete(ctx, key)
+		return domain_hotels.Image{}, err
+	}
+
+	img := domain_hotels.Image{
+		ID:           id,
+		URL:          imagesURLPath + key,
+		ThumbnailURL: imagesURLPath + thumbKey,
+		ContentType:  contentType,
+		Size:         len(data),
+		Width:        src.Bounds().Dx(),
+		Height:       src.Bounds().Dy(),
+		UploadedAt:   time.Now().UTC(),
+	}
+	// La galería sale de h: si otro cambio ganó mientras se procesaba la imagen, ErrVersionConflict
+	// y los archivos recién subidos se borran
+	images := append(append([]domain_hotels.Image{}, h.Images...), img)
+	out, err := s.repo.UpdateImages(ctx, hotelID, images, h.Version)
+	if err != nil {
+		_ = s.blobs.Delete(ctx, key)
+		_ = s.blobs.Delete(ctx, thumbKey)
+		return domain_hotels.Image{}, err
+	}
+	s.record(ctx, domain_hotels.AuditUpdate, h, out)
+	return img, nil
+}
+
+func (s *Service) DeleteImage(ctx context.Context, hotelID string, imageID string) error {
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return err
+	}
+	images := make([]domain_hotels.Image, 0, len(h.Images))
+	var removed *domain_hotels.Image
+	for i, img := range h.Images {
+		if img.ID == imageID {
+			removed = &h.Images[i]
+			continue
+		}
+		images = append(images, img)
+	}
+	if removed == nil {
+		return domain_hotels.ErrImageNotFound
+	}
+
+	out, err := s.repo.UpdateImages(ctx, hotelID, images, h.Version)
+	if err != nil {
+		return err
+	}
+	s.record(ctx, domain_hotels.AuditUpdate, h, out)
+	// Si falla el borrado del archivo queda huérfano, pero la galería ya no lo muestra
+	_ = s.blobs.Delete(ctx, strings.TrimPrefix(removed.URL, imagesURLPath))
+	_ = s.blobs.Delete(ctx, strings.TrimPrefix(removed.ThumbnailURL, imagesURLPath))
+	return nil
+}
+
+// ReorderImages recibe todos los ids de la galería en el orden nuevo
+func (s *Service) ReorderImages(ctx context.Context, hotelID string, ids []string) ([]domain_hotels.Image, error) {
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(h.Images) {
+		return nil, domain_hotels.ErrInvalidOrder
+	}
+	byID := make(map[string]domain_hotels.Image, len(h.Images))
+	for _, img := range h.Images {
+		byID[img.ID] = img
+	}
+	images := make([]domain_hotels.Image, 0, len(ids))
+	for _, id := range ids {
+		img, ok := byID[id]
+		if !ok {
+			return nil, domain_hotels.ErrInvalidOrder
+		}
+		delete(byID, id) // un id repetido falla en la vuelta siguiente
+		images = append(images, img)
+	}
+
+	out, err := s.repo.UpdateImages(ctx, hotelID, images, h.Version)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, domain_hotels.AuditUpdate, h, out)
+	return out.Images, nil
+}
+
+// GetImageBlob devuelve el archivo de una imagen (o thumbnail) por su clave
+func (s *Service) GetImageBlob(ctx context.Context, key string) (storage_hotels.Blob, error) {
+	return s.blobs.Get(ctx, key)
+}
+
+func imageKey(hotelID string, name string) string {
+	return "hotels/" + hotelID + "/" + name
+}
+
+// thumbnail escala a thumbnailWidth de ancho (vecino más cercano, sin dependencias) y lo guarda en JPEG
+func thumbnail(src image.Image) ([]byte, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > thumbnailWidth {
+		height = max(1, height*thumbnailWidth/width)
+		width = thumbnailWidth
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,199 @@
+package services_hotels_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+	repositories "hotels/repositories_hotels"
+	services "hotels/services_hotels"
+)
+
+// pngImage genera un PNG del tamaño pedido
+func pngImage(t *testing.T, width int, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func blobKey(url string) string {
+	return strings.TrimPrefix(url, "/images/")
+}
+
+// racingRepo cambia las habitaciones del hotel justo antes de guardar la galería, como otra
+// request que escribe entre la lectura y la escritura
+type racingRepo struct {
+	*repositories.Mock
+}
+
+func (r racingRepo) UpdateImages(ctx context.Context, id string, images []domain_hotels.Image, expectedVersion int64) (domain_hotels.Hotel, error) {
+	rooms := []domain_hotels.RoomType{{ID: "rt_1", Name: "Suite", Capacity: 2, Units: 1, BasePrice: 1000}}
+	if _, err := r.Mock.UpdateRooms(ctx, id, rooms, 0); err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	return r.Mock.UpdateImages(ctx, id, images, expectedVersion)
+}
+
+func TestImages(t *testing.T) {
+	t.Run("AddImage - A Concurrent Change Is Not Overwritten", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		service := services.NewService(racingRepo{ts.repo}, ts.events, fakeReservations{}, ts.blobs, ts.reviews, ts.audit)
+
+		_, err := service.AddImage(as("u1", false), h.ID, pngImage(t, 100, 50))
+
+		assert.ErrorIs(t, err, domain_hotels.ErrVersionConflict)
+		out, err := ts.repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Len(t, out.Rooms, 1)
+		assert.Empty(t, out.Images)
+	})
+
+	t.Run("AddImage - Stores The Image And A Thumbnail", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		data := pngImage(t, 640, 480)
+
+		img, err := ts.AddImage(as("u1", false), h.ID, data)
+
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", img.ContentType)
+		assert.Equal(t, len(data), img.Size)
+		assert.Equal(t, 640, img.Width)
+		assert.Equal(t, 480, img.Height)
+		assert.Equal(t, "/images/hotels/"+h.ID+"/"+img.ID+".png", img.URL)
+
+		original, err := ts.GetImageBlob(context.Background(), blobKey(img.URL))
+		require.NoError(t, err)
+		assert.Equal(t, data, original.Data)
+
+		thumb, err := ts.GetImageBlob(context.Background(), blobKey(img.ThumbnailURL))
+		require.NoError(t, err)
+		assert.Equal(t, "image/jpeg", thumb.ContentType)
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 320, cfg.Width)
+		assert.Equal(t, 240, cfg.Height)
+
+		images, err := ts.ListImages(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain_hotels.Image{img}, images)
+	})
+
+	t.Run("AddImage - Small Images Keep Their Size", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		img, err := ts.AddImage(as("u1", false), h.ID, pngImage(t, 100, 50))
+
+		require.NoError(t, err)
+		thumb, err := ts.GetImageBlob(context.Background(), blobKey(img.ThumbnailURL))
+		require.NoError(t, err)
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 100, cfg.Width)
+		assert.Equal(t, 50, cfg.Height)
+	})
+
+	t.Run("AddImage - Rejected Files", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		truncated := pngImage(t, 10, 10)[:40]
+
+		tests := map[string]struct {
+			data []byte
+			want error
+		}{
+			"text":      {[]byte("<html>no soy una imagen</html>"), domain_hotels.ErrUnsupportedImage},
+			"truncated": {truncated, domain_hotels.ErrUnsupportedImage},
+			"too large": {make([]byte, domain_hotels.MaxImageSize+1), domain_hotels.ErrImageTooLarge},
+		}
+		for name, tt := range tests {
+			_, err := ts.AddImage(as("u1", false), h.ID, tt.data)
+			assert.ErrorIs(t, err, tt.want, name)
+		}
+		images, err := ts.ListImages(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Empty(t, images)
+	})
+
+	t.Run("AddImage - Only The Owner", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		_, err := ts.AddImage(as("u2", false), h.ID, pngImage(t, 10, 10))
+
+		assert.ErrorIs(t, err, domain_hotels.ErrForbidden)
+	})
+
+	t.Run("AddImage - Gallery Limit", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		data := pngImage(t, 10, 10)
+		for i := 0; i < domain_hotels.MaxImagesPerHotel; i++ {
+			_, err := ts.AddImage(as("u1", false), h.ID, data)
+			require.NoError(t, err)
+		}
+
+		_, err := ts.AddImage(as("u1", false), h.ID, data)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrTooManyImages)
+	})
+
+	t.Run("DeleteImage - Removes The Files", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		first, err := ts.AddImage(as("u1", false), h.ID, pngImage(t, 10, 10))
+		require.NoError(t, err)
+		second, err := ts.AddImage(as("u1", false), h.ID, pngImage(t, 20, 20))
+		require.NoError(t, err)
+
+		require.NoError(t, ts.DeleteImage(as("u1", false), h.ID, first.ID))
+
+		images, err := ts.ListImages(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain_hotels.Image{second}, images)
+		for _, url := range []string{first.URL, first.ThumbnailURL} {
+			_, err := ts.GetImageBlob(context.Background(), blobKey(url))
+			assert.Error(t, err, url)
+		}
+		_, err = ts.GetImageBlob(context.Background(), blobKey(second.URL))
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, ts.DeleteImage(as("u1", false), h.ID, first.ID), domain_hotels.ErrImageNotFound)
+	})
+
+	t.Run("ReorderImages - Every Id Exactly Once", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		ids := make([]string, 0, 3)
+		for i := 0; i < 3; i++ {
+			img, err := ts.AddImage(as("u1", false), h.ID, pngImage(t, 10, 10))
+			require.NoError(t, err)
+			ids = append(ids, img.ID)
+		}
+
+		for _, order := range [][]string{{ids[0], ids[1]}, {ids[0], ids[0], ids[1]}, {ids[0], ids[1], "img_otra"}} {
+			_, err := ts.ReorderImages(as("u1", false), h.ID, order)
+			assert.ErrorIs(t, err, domain_hotels.ErrInvalidOrder)
+		}
+
+		images, err := ts.ReorderImages(as("u1", false), h.ID, []string{ids[2], ids[0], ids[1]})
+		require.NoError(t, err)
+		require.Len(t, images, 3)
+		assert.Equal(t, []string{ids[2], ids[0], ids[1]}, []string{images[0].ID, images[1].ID, images[2].ID})
+	})
+}
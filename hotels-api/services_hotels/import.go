@@ -0,0 +1,313 @@
+package services_hotels
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"hotels/domain_hotels"
+)
+
+const maxNDJSONLine = 1 << 20
+
+// importRow es una fila leída del archivo; Err es un error de formato solo de esa fila
+type importRow struct {
+	Line  int
+	Hotel domain_hotels.Hotel
+	Err   error
+}
+
+// Import carga hoteles desde CSV o NDJSON. Cada fila es un upsert: si trae id se actualiza ese
+// hotel, o se crea con ese id si no existe; sin id se busca entre los hoteles del usuario (todos,
+// si es admin) uno con el mismo nombre, ciudad y dirección, así reimportar un catálogo no duplica
+// hoteles. Los archivados no se tocan. Con dryRun se valida todo y se informa qué pasaría, sin
+// escribir nada.
+func (s *Service) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (domain_hotels.ImportReport, error) {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.ImportReport{}, domain_hotels.ErrUnauthorized
+	}
+	rows, err := readImportRows(format, r)
+	if err != nil {
+		return domain_hotels.ImportReport{}, err
+	}
+	byKey, err := s.importKeys(ctx, actor, rows)
+	if err != nil {
+		return domain_hotels.ImportReport{}, err
+	}
+
+	report := domain_hotels.ImportReport{DryRun: dryRun, Rows: len(rows), Errors: []domain_hotels.ImportRowError{}}
+	seenIDs := make(map[string]int, len(rows))
+	seenKeys := make(map[string]int, len(rows))
+	for _, row := range rows {
+		id := strings.TrimSpace(row.Hotel.ID)
+		err := row.Err
+		if err == nil && id != "" {
+			if line, dup := seenIDs[id]; dup {
+				err = fmt.Errorf("id already used in line %d", line)
+			}
+			seenIDs[id] = row.Line
+		}
+		if err == nil && id == "" {
+			key := domain_hotels.ImportKey(row.Hotel)
+			if line, dup := seenKeys[key]; dup {
+				err = fmt.Errorf("same name, city and address as line %d", line)
+			}
+			seenKeys[key] = row.Line
+		}
+
+		created := false
+		if err == nil {
+			created, err = s.importHotel(ctx, actor, row.Hotel, byKey, dryRun)
+		}
+		switch {
+		case err != nil:
+			report.Failed++
+			report.Errors = append(report.Errors, domain_hotels.ImportRowError{Row: row.Line, ID: id, Error: err.Error()})
+		case created:
+			report.Created++
+		default:
+			report.Updated++
+		}
+	}
+	return report, nil
+}
+
+// importKeys indexa por clave natural los hoteles que el actor puede editar, solo si alguna fila
+// viene sin id. Una clave que repiten varios hoteles queda con todos: la fila tiene que traer el id.
+func (s *Service) importKeys(ctx context.Context, actor domain_hotels.Actor, rows []importRow) (map[string][]domain_hotels.Hotel, error) {
+	needed := false
+	for _, row := range rows {
+		if row.Err == nil && strings.TrimSpace(row.Hotel.ID) == "" {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil, nil
+	}
+
+	ownerID := actor.UserID
+	if actor.Admin {
+		ownerID = ""
+	}
+	byKey := make(map[string][]domain_hotels.Hotel)
+	err := s.repo.Iterate(ctx, ownerID, func(h domain_hotels.Hotel) error {
+		key := domain_hotels.ImportKey(h)
+		byKey[key] = append(byKey[key], h)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return byKey, nil
+}
+
+// importHotel hace el upsert de una fila; devuelve true si el hotel es nuevo
+func (s *Service) importHotel(ctx context.Context, actor domain_hotels.Actor, in domain_hotels.Hotel, byKey map[string][]domain_hotels.Hotel, dryRun bool) (bool, error) {
+	// Solo los datos editables: habitaciones, tarifas, galería y reseñas tienen sus endpoints
+	h := domain_hotels.Hotel{
+		ID:            strings.TrimSpace(in.ID),
+		Name:          strings.TrimSpace(in.Name),
+		City:          strings.TrimSpace(in.City),
+		PricePerNight: in.PricePerNight,
+		Stars:         in.Stars,
+		Amenities:     in.Amenities,
+		OwnerID:       strings.TrimSpace(in.OwnerID),
+		Address:       strings.TrimSpace(in.Address),
+		Latitude:      in.Latitude,
+		Longitude:     in.Longitude,
+	}
+	if !h.Valid() {
+		return false, domain_hotels.ErrInvalidHotel
+	}
+	if h.ID != "" && !domain_hotels.ValidImportID(h.ID) {
+		return false, fmt.Errorf("%w: id can only have letters, digits, '-' and '_'", domain_hotels.ErrInvalidHotel)
+	}
+
+	existing, found, err := s.importMatch(ctx, h, byKey)
+	if err != nil {
+		return false, err
+	}
+	if found {
+		if existing.Archived {
+			return false, fmt.Errorf("%w: hotel is archived", domain_hotels.ErrNotFound)
+		}
+		if !actor.CanManage(existing) {
+			return false, domain_hotels.ErrForbidden
+		}
+		h.ID = existing.ID
+		if !actor.Admin || h.OwnerID == "" {
+			h.OwnerID = existing.OwnerID
+		}
+		if dryRun {
+			return false, nil
+		}
+		_, err = s.save(ctx, existing, h)
+		return false, err
+	}
+
+	// Un id desconocido es el del catálogo del partner: el hotel se crea con ese id
+	if !actor.Admin || h.OwnerID == "" {
+		h.OwnerID = actor.UserID
+	}
+	if dryRun {
+		return true, nil
+	}
+	out, err := s.repo.Create(ctx, h)
+	if err != nil {
+		return false, err
+	}
+	s.record(ctx, domain_hotels.AuditCreate, domain_hotels.Hotel{}, out)
+	s.publish(ctx, domain_hotels.EventHotelCreated, out.ID)
+	return true, nil
+}
+
+// importMatch busca el hotel que actualiza la fila: por id si lo trae, si no por clave natural
+func (s *Service) importMatch(ctx context.Context, h domain_hotels.Hotel, byKey map[string][]domain_hotels.Hotel) (domain_hotels.Hotel, bool, error) {
+	if h.ID == "" {
+		matches := byKey[domain_hotels.ImportKey(h)]
+		switch len(matches) {
+		case 0:
+			return domain_hotels.Hotel{}, false, nil
+		case 1:
+			return matches[0], true, nil
+		default:
+			return domain_hotels.Hotel{}, false, fmt.Errorf("%w: %d hotels have this name, city and address; set the id", domain_hotels.ErrInvalidHotel, len(matches))
+		}
+	}
+
+	existing, err := s.repo.GetByID(ctx, h.ID)
+	if errors.Is(err, domain_hotels.ErrNotFound) {
+		return domain_hotels.Hotel{}, false, nil
+	}
+	if err != nil {
+		return domain_hotels.Hotel{}, false, err
+	}
+	return existing, true, nil
+}
+
+// readImportRows lee el archivo completo antes de escribir nada: un error de formato general
+// (o demasiadas filas) rechaza el import entero
+func readImportRows(format string, r io.Reader) ([]importRow, error) {
+	switch format {
+	case domain_hotels.FormatCSV:
+		return readCSVRows(r)
+	case domain_hotels.FormatNDJSON:
+		return readNDJSONRows(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", domain_hotels.ErrInvalidImport, format)
+	}
+}
+
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read CSV header: %v", domain_hotels.ErrInvalidImport, err)
+	}
+	index, err := csvIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, importRow{Line: parseErr.StartLine, Err: parseErr.Err})
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain_hotels.ErrInvalidImport, err)
+		} else {
+			line, _ := reader.FieldPos(0)
+			if len(record) != len(header) {
+				rows = append(rows, importRow{Line: line, Err: fmt.Errorf("expected %d columns, got %d", len(header), len(record))})
+			} else {
+				h, err := recordToHotel(index, record)
+				rows = append(rows, importRow{Line: line, Hotel: h, Err: err})
+			}
+		}
+		if len(rows) > domain_hotels.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", domain_hotels.ErrInvalidImport, domain_hotels.MaxImportRows)
+		}
+	}
+	return rows, nil
+}
+
+func readNDJSONRows(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxNDJSONLine)
+
+	rows := make([]importRow, 0)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if line == 1 {
+			text = bytes.TrimPrefix(text, []byte("\xef\xbb\xbf"))
+		}
+		if len(text) == 0 {
+			continue
+		}
+
+		var h domain_hotels.Hotel
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&h)
+		rows = append(rows, importRow{Line: line, Hotel: h, Err: err})
+		if len(rows) > domain_hotels.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", domain_hotels.ErrInvalidImport, domain_hotels.MaxImportRows)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain_hotels.ErrInvalidImport, err)
+	}
+	return rows, nil
+}
+
+// Export escribe los hoteles (sin los archivados) en CSV o NDJSON a medida que los lee del repo.
+// Un admin exporta todo el catálogo; un dueño, sus hoteles.
+func (s *Service) Export(ctx context.Context, format string, w io.Writer) error {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.ErrUnauthorized
+	}
+	ownerID := actor.UserID
+	if actor.Admin {
+		ownerID = ""
+	}
+
+	switch format {
+	case domain_hotels.FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvColumns); err != nil {
+			return err
+		}
+		err := s.repo.Iterate(ctx, ownerID, func(h domain_hotels.Hotel) error {
+			return writer.Write(hotelToRecord(h))
+		})
+		writer.Flush()
+		if err != nil {
+			return err
+		}
+		return writer.Error()
+	case domain_hotels.FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		return s.repo.Iterate(ctx, ownerID, func(h domain_hotels.Hotel) error {
+			return encoder.Encode(h)
+		})
+	default:
+		return fmt.Errorf("%w: unknown format %q", domain_hotels.ErrInvalidImport, format)
+	}
+}
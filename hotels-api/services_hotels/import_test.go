@@ -0,0 +1,272 @@
+package services_hotels_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+)
+
+func TestImport(t *testing.T) {
+	t.Run("Import - CSV Header Mapping", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			header string
+			row    string
+		}{
+			{"all columns", "id,name,city,price_per_night,stars,amenities,owner_id,address,latitude,longitude", `,Sheraton,Córdoba,1000,4,wifi|pool,,Av. Colón 100,-31.42,-64.18`},
+			{"any order and case", "STARS, City ,price_per_night,Name,amenities,latitude,longitude,address", `4,Córdoba,1000,Sheraton,wifi|pool,-31.42,-64.18,Av. Colón 100`},
+			{"header with BOM", "\ufeffname,city,price_per_night,stars,amenities,address,latitude,longitude", `Sheraton,Córdoba,1000,4,wifi| pool |,Av. Colón 100,-31.42,-64.18`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ts := newTestService(t, fakeReservations{})
+
+				report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(tt.header+"\n"+tt.row+"\n"), false)
+
+				require.NoError(t, err)
+				assert.Equal(t, 1, report.Created)
+				page, err := ts.repo.List(context.Background(), domain_hotels.HotelQuery{Limit: 10})
+				require.NoError(t, err)
+				require.Len(t, page.Items, 1)
+				h := page.Items[0]
+				assert.NotEmpty(t, h.ID)
+				assert.Equal(t, "Sheraton", h.Name)
+				assert.Equal(t, "Córdoba", h.City)
+				assert.Equal(t, 1000.0, h.PricePerNight)
+				assert.Equal(t, 4, h.Stars)
+				assert.Equal(t, []string{"wifi", "pool"}, h.Amenities)
+				assert.Equal(t, "u1", h.OwnerID)
+				assert.Equal(t, "Av. Colón 100", h.Address)
+				require.True(t, h.HasLocation())
+				assert.Equal(t, -31.42, *h.Latitude)
+			})
+		}
+	})
+
+	t.Run("Import - Invalid CSV Header Rejects The File", func(t *testing.T) {
+		headers := map[string]string{
+			"unknown column":    "name,city,price_per_night,stars,rating",
+			"duplicated column": "name,city,price_per_night,stars,city",
+			"missing column":    "name,city,stars",
+			"empty file":        "",
+		}
+		for name, header := range headers {
+			ts := newTestService(t, fakeReservations{})
+
+			_, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(header), false)
+
+			assert.ErrorIs(t, err, domain_hotels.ErrInvalidImport, name)
+		}
+	})
+
+	t.Run("Import - Bad Rows Are Reported And The Rest Imported", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		other := ts.seed(t, "u2")
+		file := strings.Join([]string{
+			"id,name,city,price_per_night,stars",
+			",Sheraton,Córdoba,1000,4",  // 2: ok
+			",Sin precio,Córdoba,abc,4", // 3
+			",Sin estrellas,Córdoba,1000,9",
+			",Pocas columnas",
+			"../h1,Id inválido,Córdoba,1000,4",
+			other.ID + ",Ajeno,Córdoba,1000,4",
+			`,"Comillas sin cerrar,Córdoba,1000,4`,
+		}, "\n")
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, report.Rows) // el encabezado no cuenta
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 6, report.Failed)
+		errs := map[int]string{}
+		for _, rowErr := range report.Errors {
+			errs[rowErr.Row] = rowErr.Error
+		}
+		assert.Equal(t, "price_per_night must be a number", errs[3])
+		assert.Equal(t, domain_hotels.ErrInvalidHotel.Error(), errs[4])
+		assert.Equal(t, "expected 5 columns, got 2", errs[5])
+		assert.Equal(t, domain_hotels.ErrInvalidHotel.Error()+": id can only have letters, digits, '-' and '_'", errs[6])
+		assert.Equal(t, domain_hotels.ErrForbidden.Error(), errs[7])
+		assert.Contains(t, errs[8], "quote")
+
+		unchanged, err := ts.repo.GetByID(context.Background(), other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Sheraton", unchanged.Name)
+	})
+
+	t.Run("Import - Updates A Hotel By ID", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		own := ts.seed(t, "u1")
+		file := "id,name,city,price_per_night,stars\n" + own.ID + ",Sheraton Centro,Córdoba,1500,5\n"
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+		updated, err := ts.repo.GetByID(context.Background(), own.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Sheraton Centro", updated.Name)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Equal(t, []string{domain_hotels.EventHotelUpdated}, ts.events.types)
+	})
+
+	t.Run("Import - Unknown ID Creates The Hotel With That ID", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		file := "id,name,city,price_per_night,stars\npartner-42,Sheraton,Córdoba,1000,4\n"
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		created, err := ts.repo.GetByID(context.Background(), "partner-42")
+		require.NoError(t, err)
+		assert.Equal(t, "u1", created.OwnerID)
+
+		report, err = ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 1, report.Updated)
+	})
+
+	t.Run("Import - Re-Importing Without IDs Does Not Duplicate", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		ts.seed(t, "u2") // mismo hotel de otro dueño: no es de u1, no se toca
+		file := strings.Join([]string{
+			"name,city,price_per_night,stars,address",
+			"Sheraton,Córdoba,1000,4,Av. Colón 100",
+			"Hilton,Mendoza,900,5,",
+		}, "\n")
+
+		first, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, first.Created)
+
+		again := strings.Replace(file, "Sheraton,Córdoba,1000", " sheraton ,CÓRDOBA,1200", 1)
+		second, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(again), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, second.Created)
+		assert.Equal(t, 2, second.Updated)
+		assert.Empty(t, second.Errors)
+		page, err := ts.repo.List(context.Background(), domain_hotels.HotelQuery{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 3)
+		for _, h := range page.Items {
+			if h.OwnerID == "u1" && h.City == "Córdoba" {
+				assert.Equal(t, 1200.0, h.PricePerNight)
+			}
+		}
+	})
+
+	t.Run("Import - Natural Key Conflicts", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		ts.seed(t, "u1")
+		ts.seed(t, "u1")
+		file := strings.Join([]string{
+			"name,city,price_per_night,stars",
+			"Sheraton,Córdoba,1000,4", // dos hoteles de u1 con esta clave
+			"Hilton,Mendoza,900,5",
+			"Hilton,Mendoza,950,5",
+		}, "\n")
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		require.Len(t, report.Errors, 2)
+		assert.Contains(t, report.Errors[0].Error, "2 hotels have this name, city and address")
+		assert.Equal(t, 4, report.Errors[1].Row)
+		assert.Equal(t, "same name, city and address as line 3", report.Errors[1].Error)
+	})
+
+	t.Run("Import - Dry Run Does Not Write", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		own := ts.seed(t, "u1")
+		file := strings.Join([]string{
+			"id,name,city,price_per_night,stars",
+			own.ID + ",Sheraton Centro,Córdoba,1500,5",
+			",Nuevo,Mendoza,800,3",
+			",Sin precio,Córdoba,,4",
+		}, "\n")
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader(file), true)
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Failed)
+		page, err := ts.repo.List(context.Background(), domain_hotels.HotelQuery{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		unchanged, err := ts.repo.GetByID(context.Background(), own.ID)
+		require.NoError(t, err)
+		assert.Equal(t, own, unchanged)
+		assert.Empty(t, ts.events.types)
+		history, err := ts.audit.ListByHotel(context.Background(), own.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, history.Items)
+	})
+
+	t.Run("Import - NDJSON", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		file := strings.Join([]string{
+			"\ufeff" + `{"name":"Sheraton","city":"Córdoba","price_per_night":1000,"stars":4,"amenities":["wifi"]}`,
+			"",
+			`{"name":"Hilton","city":"Mendoza","price_per_night":900,"stars":5,"rating":4}`,
+			`{"name":"Roto"`,
+			`{"id":"ver/1","name":"Id inválido","city":"Córdoba","price_per_night":1000,"stars":4}`,
+			`{"name":"Solo latitud","city":"Salta","price_per_night":700,"stars":3,"latitude":-24.7}`,
+		}, "\n")
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatNDJSON, strings.NewReader(file), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, report.Rows) // la línea vacía se saltea
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 4, report.Failed)
+		rows := make([]int, 0, len(report.Errors))
+		for _, rowErr := range report.Errors {
+			rows = append(rows, rowErr.Row)
+		}
+		assert.Equal(t, []int{3, 4, 5, 6}, rows)
+		assert.Contains(t, report.Errors[0].Error, `unknown field "rating"`)
+		assert.Equal(t, "ver/1", report.Errors[2].ID)
+	})
+
+	t.Run("Import - Repeated ID In The File", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		own := ts.seed(t, "u1")
+		line := own.ID + ",Sheraton,Córdoba,1000,4"
+
+		report, err := ts.Import(as("u1", false), domain_hotels.FormatCSV, strings.NewReader("id,name,city,price_per_night,stars\n"+line+"\n"+line), false)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, "id already used in line 2", report.Errors[0].Error)
+	})
+
+	t.Run("Import - Requires Authentication", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+
+		_, err := ts.Import(context.Background(), domain_hotels.FormatCSV, strings.NewReader("name,city,price_per_night,stars\n"), false)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrUnauthorized)
+	})
+
+	t.Run("Import - Unknown Format", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+
+		_, err := ts.Import(as("u1", false), "xlsx", strings.NewReader(""), false)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrInvalidImport)
+	})
+}
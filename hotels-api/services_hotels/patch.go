@@ -0,0 +1,96 @@
+package services_hotels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"hotels/domain_hotels"
+)
+
+// Campos que acepta PATCH; habitaciones, tarifas, galería y archivado tienen sus endpoints
+var patchableFields = map[string]bool{
+	"name":            true,
+	"city":            true,
+	"price_per_night": true,
+	"stars":           true,
+	"amenities":       true,
+	"owner_id":        true,
+	"address":         true,
+	"latitude":        true,
+	"longitude":       true,
+}
+
+// Patch aplica un JSON Merge Patch (RFC 7386): solo cambian los campos que vienen y null borra
+// el valor. El resultado tiene que seguir siendo un hotel válido.
+func (s *Service) Patch(ctx context.Context, id string, patch []byte, expectedVersion int64) (domain_hotels.Hotel, error) {
+	existing, err := s.editableHotel(ctx, id, expectedVersion)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+
+	patched, err := applyMergePatch(existing, patch)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	if !patched.Valid() {
+		return domain_hotels.Hotel{}, domain_hotels.ErrInvalidHotel
+	}
+	if actor, _ := domain_hotels.ActorFrom(ctx); !actor.Admin && patched.OwnerID != existing.OwnerID {
+		return domain_hotels.Hotel{}, domain_hotels.ErrForbidden
+	}
+	return s.save(ctx, existing, patched)
+}
+
+func applyMergePatch(h domain_hotels.Hotel, patch []byte) (domain_hotels.Hotel, error) {
+	var changes map[string]any
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("%w: body must be a JSON object", domain_hotels.ErrInvalidPatch)
+	}
+	for field := range changes {
+		if !patchableFields[field] {
+			return domain_hotels.Hotel{}, fmt.Errorf("%w: field %q cannot be patched", domain_hotels.ErrInvalidPatch, field)
+		}
+	}
+
+	// Se trabaja sobre la representación JSON del hotel, que es a la que se refiere el patch
+	doc, err := json.Marshal(h)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	var target map[string]any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	merged, err := json.Marshal(mergePatch(target, changes))
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+
+	var out domain_hotels.Hotel
+	if err := json.Unmarshal(merged, &out); err != nil {
+		return domain_hotels.Hotel{}, fmt.Errorf("%w: %v", domain_hotels.ErrInvalidPatch, err)
+	}
+	return out, nil
+}
+
+// mergePatch es el algoritmo de la RFC 7386: los objetos se mezclan recursivamente, null borra
+// la clave y cualquier otro valor (arrays incluidos) reemplaza al anterior
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,156 @@
+package services_hotels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+	services "hotels/services_hotels"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target any
+		patch  any
+		want   any
+	}{
+		{
+			name:   "null deletes the field",
+			target: map[string]any{"name": "Sheraton", "address": "Av. Colón 100"},
+			patch:  map[string]any{"address": nil},
+			want:   map[string]any{"name": "Sheraton"},
+		},
+		{
+			name:   "nested objects merge",
+			target: map[string]any{"pricing": map[string]any{"min_stay": 2.0, "weekend_surcharge_pct": 20.0}},
+			patch:  map[string]any{"pricing": map[string]any{"min_stay": 3.0, "weekend_surcharge_pct": nil}},
+			want:   map[string]any{"pricing": map[string]any{"min_stay": 3.0}},
+		},
+		{
+			name:   "object created where there was none",
+			target: map[string]any{"pricing": "none"},
+			patch:  map[string]any{"pricing": map[string]any{"min_stay": 2.0}},
+			want:   map[string]any{"pricing": map[string]any{"min_stay": 2.0}},
+		},
+		{
+			name:   "arrays are replaced",
+			target: map[string]any{"amenities": []any{"wifi", "pool"}},
+			patch:  map[string]any{"amenities": []any{"spa"}},
+			want:   map[string]any{"amenities": []any{"spa"}},
+		},
+		{
+			name:   "missing field to delete",
+			target: map[string]any{"name": "Sheraton"},
+			patch:  map[string]any{"address": nil},
+			want:   map[string]any{"name": "Sheraton"},
+		},
+		{
+			name:   "non object patch replaces everything",
+			target: map[string]any{"name": "Sheraton"},
+			patch:  []any{"a"},
+			want:   []any{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, services.MergePatch(tt.target, tt.patch))
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	latitude, longitude := -31.42, -64.18
+	seed := func(t *testing.T, ts testService) domain_hotels.Hotel {
+		h, err := ts.repo.Create(context.Background(), domain_hotels.Hotel{
+			Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4, OwnerID: "u1",
+			Amenities: []string{"wifi", "pool"}, Address: "Av. Colón 100", Latitude: &latitude, Longitude: &longitude,
+		})
+		require.NoError(t, err)
+		return h
+	}
+
+	t.Run("Patch - Only The Fields In The Patch Change", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := seed(t, ts)
+
+		out, err := ts.Patch(as("u1", false), h.ID, []byte(`{"name":"Sheraton Centro","amenities":["spa"]}`), 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Sheraton Centro", out.Name)
+		assert.Equal(t, []string{"spa"}, out.Amenities)
+		assert.Equal(t, "Córdoba", out.City)
+		assert.Equal(t, "Av. Colón 100", out.Address)
+		assert.Equal(t, int64(2), out.Version)
+		assert.Equal(t, []string{domain_hotels.EventHotelUpdated}, ts.events.types)
+	})
+
+	t.Run("Patch - Null Deletes The Field", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := seed(t, ts)
+
+		out, err := ts.Patch(as("u1", false), h.ID, []byte(`{"address":null,"latitude":null,"longitude":null}`), 0)
+
+		require.NoError(t, err)
+		assert.Empty(t, out.Address)
+		assert.Nil(t, out.Latitude)
+		assert.Nil(t, out.Longitude)
+	})
+
+	t.Run("Patch - Result Must Be A Valid Hotel", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := seed(t, ts)
+
+		for _, patch := range []string{`{"name":null}`, `{"latitude":null}`, `{"stars":9}`} {
+			_, err := ts.Patch(as("u1", false), h.ID, []byte(patch), 0)
+			assert.ErrorIs(t, err, domain_hotels.ErrInvalidHotel, patch)
+		}
+	})
+
+	t.Run("Patch - Invalid Patch", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := seed(t, ts)
+
+		for _, patch := range []string{`[]`, `null`, `{"rooms":[]}`, `{"version":7}`, `{"stars":"four"}`} {
+			_, err := ts.Patch(as("u1", false), h.ID, []byte(patch), 0)
+			assert.ErrorIs(t, err, domain_hotels.ErrInvalidPatch, patch)
+		}
+	})
+
+	t.Run("Patch - Expected Version", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := seed(t, ts)
+
+		_, err := ts.Patch(as("u1", false), h.ID, []byte(`{"stars":5}`), h.Version+1)
+		assert.ErrorIs(t, err, domain_hotels.ErrVersionConflict)
+
+		_, err = ts.Patch(as("u1", false), h.ID, []byte(`{"stars":5}`), h.Version)
+		require.NoError(t, err)
+
+		// la versión vieja ya no sirve; sin versión (0) no se chequea
+		_, err = ts.Patch(as("u1", false), h.ID, []byte(`{"stars":3}`), h.Version)
+		assert.ErrorIs(t, err, domain_hotels.ErrVersionConflict)
+		out, err := ts.Patch(as("u1", false), h.ID, []byte(`{"stars":3}`), 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, out.Stars)
+		assert.Equal(t, int64(3), out.Version)
+	})
+
+	t.Run("Patch - Only Admins Change The Owner", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := seed(t, ts)
+
+		_, err := ts.Patch(as("u1", false), h.ID, []byte(`{"owner_id":"u2"}`), 0)
+		assert.ErrorIs(t, err, domain_hotels.ErrForbidden)
+
+		_, err = ts.Patch(as("u2", false), h.ID, []byte(`{"name":"Ajeno"}`), 0)
+		assert.ErrorIs(t, err, domain_hotels.ErrForbidden)
+
+		out, err := ts.Patch(as("9", true), h.ID, []byte(`{"owner_id":"u2"}`), 0)
+		require.NoError(t, err)
+		assert.Equal(t, "u2", out.OwnerID)
+	})
+}
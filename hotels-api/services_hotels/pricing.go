@@ -0,0 +1,190 @@
+﻿package services_hotels
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"hotels/domain_hotels"
+)
+
+// QuoteRequest son los datos de la estadía a cotizar
+type QuoteRequest struct {
+	CheckIn  time.Time
+	CheckOut time.Time
+	Guests   int
+	RoomType string // id o nombre; obligatorio si el hotel tiene habitaciones cargadas, salvo con Cheapest
+	Cheapest bool   // sin RoomType: cotiza el tipo más barato con lugar para los huéspedes (precio "desde")
+}
+
+// CalculateQuote arma el precio noche por noche: precio base (de la habitación o del hotel),
+// ajuste de temporada, recargo de fin de semana y al final el descuento por estadía larga.
+func CalculateQuote(h domain_hotels.Hotel, req QuoteRequest) (domain_hotels.Quote, error) {
+	checkIn, checkOut := dateOf(req.CheckIn), dateOf(req.CheckOut)
+	nights := int(checkOut.Sub(checkIn).Hours() / 24)
+	if nights < 1 {
+		return domain_hotels.Quote{}, fmt.Errorf("%w: check_out must be after check_in", domain_hotels.ErrInvalidQuote)
+	}
+	if nights > domain_hotels.MaxQuoteNights {
+		return domain_hotels.Quote{}, fmt.Errorf("%w: stays are limited to %d nights", domain_hotels.ErrInvalidQuote, domain_hotels.MaxQuoteNights)
+	}
+	if req.Guests < 1 {
+		return domain_hotels.Quote{}, fmt.Errorf("%w: at least one guest is required", domain_hotels.ErrInvalidQuote)
+	}
+
+	base, roomID, err := basePrice(h, req)
+	if err != nil {
+		return domain_hotels.Quote{}, err
+	}
+
+	rules := domain_hotels.PricingRules{}
+	if h.Pricing != nil {
+		rules = *h.Pricing
+	}
+	currency := rules.Currency
+	if currency == "" {
+		currency = domain_hotels.DefaultCurrency
+	}
+
+	// Estadía mínima: la general o la de la temporada en la que se entra, la mayor
+	minStay := rules.MinStay
+	if season, ok := seasonFor(rules.Seasons, checkIn); ok {
+		minStay = max(minStay, season.MinStay)
+	}
+	if nights < minStay {
+		return domain_hotels.Quote{}, fmt.Errorf("%w: minimum stay is %d nights", domain_hotels.ErrInvalidQuote, minStay)
+	}
+
+	quote := domain_hotels.Quote{
+		HotelID:  h.ID,
+		RoomType: roomID,
+		CheckIn:  checkIn.Format(time.DateOnly),
+		CheckOut: checkOut.Format(time.DateOnly),
+		Nights:   nights,
+		Guests:   req.Guests,
+		Currency: currency,
+		Nightly:  make([]domain_hotels.NightPrice, 0, nights),
+	}
+	for day := checkIn; day.Before(checkOut); day = day.AddDate(0, 0, 1) {
+		night := domain_hotels.NightPrice{Date: day.Format(time.DateOnly), Base: base}
+		factor := 1.0
+		if season, ok := seasonFor(rules.Seasons, day); ok {
+			night.Season = season.Name
+			night.SeasonPct = season.AdjustmentPct
+			factor *= 1 + season.AdjustmentPct/100
+		}
+		if isWeekendNight(day) && rules.WeekendSurchargePct > 0 {
+			night.WeekendPct = rules.WeekendSurchargePct
+			factor *= 1 + rules.WeekendSurchargePct/100
+		}
+		night.Price = roundMoney(base * factor)
+
+		quote.Nightly = append(quote.Nightly, night)
+		quote.Subtotal += night.Price
+	}
+	quote.Subtotal = roundMoney(quote.Subtotal)
+
+	// Se aplica el mejor descuento por cantidad de noches
+	for _, d := range rules.StayDiscounts {
+		if nights >= d.MinNights && d.DiscountPct > quote.DiscountPct {
+			quote.DiscountPct = d.DiscountPct
+		}
+	}
+	quote.Discount = roundMoney(quote.Subtotal * quote.DiscountPct / 100)
+	quote.Total = roundMoney(quote.Subtotal - quote.Discount)
+	return quote, nil
+}
+
+// basePrice: el BasePrice del tipo de habitación pedido, o PricePerNight si el hotel no tiene habitaciones
+func basePrice(h domain_hotels.Hotel, req QuoteRequest) (float64, string, error) {
+	if len(h.Rooms) == 0 {
+		return h.PricePerNight, "", nil
+	}
+	if strings.TrimSpace(req.RoomType) == "" {
+		if req.Cheapest {
+			return cheapestRoom(h.Rooms, req.Guests)
+		}
+		return 0, "", fmt.Errorf("%w: room_type is required", domain_hotels.ErrInvalidQuote)
+	}
+	for _, room := range h.Rooms {
+		if room.ID == req.RoomType || strings.EqualFold(room.Name, strings.TrimSpace(req.RoomType)) {
+			if req.Guests > room.Capacity {
+				return 0, "", fmt.Errorf("%w: room type %s allows up to %d guests", domain_hotels.ErrInvalidQuote, room.Name, room.Capacity)
+			}
+			return room.BasePrice, room.ID, nil
+		}
+	}
+	return 0, "", domain_hotels.ErrRoomNotFound
+}
+
+// cheapestRoom: el tipo de habitación de menor BasePrice con capacidad para los huéspedes
+func cheapestRoom(rooms []domain_hotels.RoomType, guests int) (float64, string, error) {
+	var cheapest *domain_hotels.RoomType
+	for i, room := range rooms {
+		if room.Capacity >= guests && (cheapest == nil || room.BasePrice < cheapest.BasePrice) {
+			cheapest = &rooms[i]
+		}
+	}
+	if cheapest == nil {
+		return 0, "", fmt.Errorf("%w: no room type allows %d guests", domain_hotels.ErrInvalidQuote, guests)
+	}
+	return cheapest.BasePrice, cheapest.ID, nil
+}
+
+// seasonFor: la primera temporada que contiene el día
+func seasonFor(seasons []domain_hotels.Season, day time.Time) (domain_hotels.Season, bool) {
+	for _, season := range seasons {
+		if season.Contains(day) {
+			return season, true
+		}
+	}
+	return domain_hotels.Season{}, false
+}
+
+// Noches de viernes y sábado
+func isWeekendNight(day time.Time) bool {
+	return day.Weekday() == time.Friday || day.Weekday() == time.Saturday
+}
+
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Quote cotiza una estadía con las reglas de precios del hotel
+func (s *Service) Quote(ctx context.Context, hotelID string, req QuoteRequest) (domain_hotels.Quote, error) {
+	h, err := s.repo.GetByID(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.Quote{}, err
+	}
+	if h.Archived {
+		return domain_hotels.Quote{}, domain_hotels.ErrNotFound
+	}
+	return CalculateQuote(h, req)
+}
+
+func (s *Service) UpdatePricing(ctx context.Context, hotelID string, pricing domain_hotels.PricingRules) (domain_hotels.PricingRules, error) {
+	if err := pricing.Valid(); err != nil {
+		return domain_hotels.PricingRules{}, err
+	}
+	if pricing.Currency == "" {
+		pricing.Currency = domain_hotels.DefaultCurrency
+	}
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.PricingRules{}, err
+	}
+	out, err := s.repo.UpdatePricing(ctx, hotelID, pricing)
+	if err != nil {
+		return domain_hotels.PricingRules{}, err
+	}
+	s.record(ctx, domain_hotels.AuditUpdate, h, out)
+	s.publish(ctx, domain_hotels.EventHotelUpdated, hotelID)
+	return *out.Pricing, nil
+}
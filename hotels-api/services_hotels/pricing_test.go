@@ -0,0 +1,139 @@
+package services_hotels_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hotels/domain_hotels"
+	services "hotels/services_hotels"
+)
+
+func date(value string) time.Time {
+	t, _ := time.Parse(time.DateOnly, value)
+	return t
+}
+
+func TestCalculateQuote(t *testing.T) {
+	hotel := domain_hotels.Hotel{
+		ID:            "h1",
+		PricePerNight: 1000,
+		Pricing: &domain_hotels.PricingRules{
+			Seasons: []domain_hotels.Season{
+				{Name: "verano", From: "12-15", To: "03-01", AdjustmentPct: 50},
+			},
+			WeekendSurchargePct: 20,
+			MinStay:             2,
+			StayDiscounts: []domain_hotels.StayDiscount{
+				{MinNights: 5, DiscountPct: 5},
+				{MinNights: 7, DiscountPct: 10},
+			},
+		},
+	}
+
+	t.Run("CalculateQuote - Weekend Surcharge", func(t *testing.T) {
+		// jueves, viernes y sábado a la noche
+		quote, err := services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-23"), Guests: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, quote.Nights)
+		assert.Equal(t, "ARS", quote.Currency)
+		assert.Equal(t, []float64{1000, 1200, 1200}, nightlyPrices(quote))
+		assert.Equal(t, 3400.0, quote.Total)
+	})
+
+	t.Run("CalculateQuote - Season Across New Year", func(t *testing.T) {
+		quote, err := services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-12-30"), CheckOut: date("2026-01-01"), Guests: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1500, 1500}, nightlyPrices(quote))
+		assert.Equal(t, "verano", quote.Nightly[0].Season)
+		assert.Equal(t, 3000.0, quote.Total)
+	})
+
+	t.Run("CalculateQuote - Best Length Of Stay Discount", func(t *testing.T) {
+		quote, err := services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-03"), CheckOut: date("2025-11-10"), Guests: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7400.0, quote.Subtotal)
+		assert.Equal(t, 10.0, quote.DiscountPct)
+		assert.Equal(t, 740.0, quote.Discount)
+		assert.Equal(t, 6660.0, quote.Total)
+	})
+
+	t.Run("CalculateQuote - Minimum Stay", func(t *testing.T) {
+		_, err := services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-21"), Guests: 1})
+
+		assert.ErrorIs(t, err, domain_hotels.ErrInvalidQuote)
+	})
+
+	t.Run("CalculateQuote - Invalid Dates", func(t *testing.T) {
+		_, err := services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-21"), CheckOut: date("2025-11-21"), Guests: 1})
+
+		assert.ErrorIs(t, err, domain_hotels.ErrInvalidQuote)
+	})
+
+	withRooms := domain_hotels.Hotel{
+		ID:            "h2",
+		PricePerNight: 1000,
+		Rooms: []domain_hotels.RoomType{
+			{ID: "rt_1", Name: "Doble", Capacity: 2, Units: 3, BasePrice: 800},
+		},
+	}
+
+	t.Run("CalculateQuote - Room Type Base Price", func(t *testing.T) {
+		quote, err := services.CalculateQuote(withRooms, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 2, RoomType: "doble"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rt_1", quote.RoomType)
+		assert.Equal(t, 1600.0, quote.Total)
+	})
+
+	t.Run("CalculateQuote - Room Type Required", func(t *testing.T) {
+		_, err := services.CalculateQuote(withRooms, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 2})
+
+		assert.ErrorIs(t, err, domain_hotels.ErrInvalidQuote)
+	})
+
+	t.Run("CalculateQuote - Room Capacity Exceeded", func(t *testing.T) {
+		_, err := services.CalculateQuote(withRooms, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 3, RoomType: "rt_1"})
+
+		assert.ErrorIs(t, err, domain_hotels.ErrInvalidQuote)
+	})
+
+	t.Run("CalculateQuote - Unknown Room Type", func(t *testing.T) {
+		_, err := services.CalculateQuote(withRooms, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 1, RoomType: "Suite"})
+
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomNotFound)
+	})
+
+	t.Run("CalculateQuote - Cheapest Room For The Guests", func(t *testing.T) {
+		hotel := withRooms
+		hotel.Rooms = []domain_hotels.RoomType{
+			{ID: "rt_1", Name: "Doble", Capacity: 2, Units: 3, BasePrice: 800},
+			{ID: "rt_2", Name: "Familiar", Capacity: 4, Units: 1, BasePrice: 1500},
+			{ID: "rt_3", Name: "Cuádruple", Capacity: 4, Units: 1, BasePrice: 1200},
+		}
+
+		quote, err := services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 2, Cheapest: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "rt_1", quote.RoomType)
+		assert.Equal(t, 1600.0, quote.Total)
+
+		quote, err = services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 3, Cheapest: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "rt_3", quote.RoomType)
+
+		_, err = services.CalculateQuote(hotel, services.QuoteRequest{CheckIn: date("2025-11-20"), CheckOut: date("2025-11-22"), Guests: 5, Cheapest: true})
+		assert.ErrorIs(t, err, domain_hotels.ErrInvalidQuote)
+	})
+}
+
+func nightlyPrices(quote domain_hotels.Quote) []float64 {
+	prices := make([]float64, 0, len(quote.Nightly))
+	for _, night := range quote.Nightly {
+		prices = append(prices, night.Price)
+	}
+	return prices
+}
@@ -0,0 +1,149 @@
+package services_hotels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"hotels/domain_hotels"
+)
+
+// Las reseñas viven en su propio repo (colección aparte en Mongo)
+type ReviewRepository interface {
+	Create(ctx context.Context, r domain_hotels.Review) (domain_hotels.Review, error)
+	GetByID(ctx context.Context, id string) (domain_hotels.Review, error)
+	List(ctx context.Context, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error)
+	Moderate(ctx context.Context, id string, status string, note string, at time.Time) (domain_hotels.Review, error)
+	Delete(ctx context.Context, id string) error
+	Stats(ctx context.Context, hotelID string) (domain_hotels.RatingStats, error)
+}
+
+// ListReviews: las reseñas aprobadas del hotel, las más nuevas primero
+func (s *Service) ListReviews(ctx context.Context, hotelID string, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error) {
+	if _, err := s.repo.GetByID(ctx, hotelID); err != nil {
+		return domain_hotels.ReviewPage{}, err
+	}
+	q.HotelID, q.Status = hotelID, domain_hotels.ReviewApproved
+	q, err := q.Normalize()
+	if err != nil {
+		return domain_hotels.ReviewPage{}, err
+	}
+	return s.reviews.List(ctx, q)
+}
+
+// CreateReview: solo quien tuvo una estadía terminada en el hotel (según reservations-api).
+// La reseña queda pendiente hasta que un admin la apruebe.
+func (s *Service) CreateReview(ctx context.Context, hotelID string, in domain_hotels.Review) (domain_hotels.Review, error) {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.Review{}, domain_hotels.ErrUnauthorized
+	}
+	if !in.Valid() {
+		return domain_hotels.Review{}, domain_hotels.ErrInvalidReview
+	}
+	h, err := s.repo.GetByID(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.Review{}, err
+	}
+	if h.Archived {
+		return domain_hotels.Review{}, domain_hotels.ErrNotFound
+	}
+
+	reservationID, err := s.reservations.CompletedStay(ctx, hotelID, actor.UserID)
+	if err != nil {
+		return domain_hotels.Review{}, fmt.Errorf("%w: hotel %s: %v", domain_hotels.ErrReservationsUnavailable, hotelID, err)
+	}
+	if reservationID == "" {
+		return domain_hotels.Review{}, domain_hotels.ErrNoCompletedStay
+	}
+
+	return s.reviews.Create(ctx, domain_hotels.Review{
+		HotelID:       hotelID,
+		UserID:        actor.UserID,
+		ReservationID: reservationID,
+		Rating:        in.Rating,
+		Title:         strings.TrimSpace(in.Title),
+		Comment:       strings.TrimSpace(in.Comment),
+		Status:        domain_hotels.ReviewPending,
+		CreatedAt:     time.Now().UTC(),
+	})
+}
+
+// DeleteReview: la puede borrar su autor o un admin
+func (s *Service) DeleteReview(ctx context.Context, hotelID string, reviewID string) error {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.ErrUnauthorized
+	}
+	review, err := s.reviews.GetByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.HotelID != hotelID {
+		return domain_hotels.ErrReviewNotFound
+	}
+	if !actor.Admin && review.UserID != actor.UserID {
+		return domain_hotels.ErrForbidden
+	}
+
+	if err := s.reviews.Delete(ctx, reviewID); err != nil {
+		return err
+	}
+	if review.Status == domain_hotels.ReviewApproved {
+		return s.refreshRating(ctx, hotelID)
+	}
+	return nil
+}
+
+// ListReviewsForModeration: todas las reseñas (por defecto las pendientes), solo admin
+func (s *Service) ListReviewsForModeration(ctx context.Context, q domain_hotels.ReviewQuery) (domain_hotels.ReviewPage, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return domain_hotels.ReviewPage{}, err
+	}
+	if q.Status == "" {
+		q.Status = domain_hotels.ReviewPending
+	}
+	q, err := q.Normalize()
+	if err != nil {
+		return domain_hotels.ReviewPage{}, err
+	}
+	return s.reviews.List(ctx, q)
+}
+
+// ModerateReview aprueba o rechaza una reseña y recalcula el promedio del hotel
+func (s *Service) ModerateReview(ctx context.Context, reviewID string, status string, note string) (domain_hotels.Review, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return domain_hotels.Review{}, err
+	}
+	if !domain_hotels.ValidReviewStatus(status) {
+		return domain_hotels.Review{}, domain_hotels.ErrInvalidReview
+	}
+
+	review, err := s.reviews.Moderate(ctx, reviewID, status, strings.TrimSpace(note), time.Now().UTC())
+	if err != nil {
+		return domain_hotels.Review{}, err
+	}
+	if err := s.refreshRating(ctx, review.HotelID); err != nil {
+		return domain_hotels.Review{}, err
+	}
+	return review, nil
+}
+
+// refreshRating guarda en el hotel el promedio de las reseñas aprobadas y avisa a search-api
+// para que lo reindexe (se puede ordenar la búsqueda por puntaje). Un hotel archivado no está
+// en el buscador: se le guarda el promedio pero no se publica nada.
+func (s *Service) refreshRating(ctx context.Context, hotelID string) error {
+	stats, err := s.reviews.Stats(ctx, hotelID)
+	if err != nil {
+		return err
+	}
+	out, err := s.repo.UpdateRating(ctx, hotelID, stats)
+	if err != nil {
+		return err
+	}
+	if !out.Archived {
+		s.publish(ctx, domain_hotels.EventHotelUpdated, hotelID)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package services_hotels_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+)
+
+func TestReviewRating(t *testing.T) {
+	admin := as("1", true)
+	guests := fakeReservations{completed: map[string]string{"u1": "r1", "u2": "r2", "u3": "r3"}}
+
+	// review crea la reseña de userID y la deja en el estado pedido
+	review := func(t *testing.T, ts testService, hotelID string, userID string, rating int, status string) domain_hotels.Review {
+		t.Helper()
+		r, err := ts.CreateReview(as(userID, false), hotelID, domain_hotels.Review{Rating: rating})
+		require.NoError(t, err)
+		if status != domain_hotels.ReviewPending {
+			r, err = ts.ModerateReview(admin, r.ID, status, "")
+			require.NoError(t, err)
+		}
+		return r
+	}
+	rating := func(t *testing.T, ts testService, hotelID string) (float64, int) {
+		t.Helper()
+		h, err := ts.repo.GetByID(context.Background(), hotelID)
+		require.NoError(t, err)
+		return h.RatingAvg, h.RatingCount
+	}
+
+	t.Run("ModerateReview - Only Approved Reviews Count", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+
+		review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+		review(t, ts, h.ID, "u2", 4, domain_hotels.ReviewApproved)
+		review(t, ts, h.ID, "u3", 1, domain_hotels.ReviewRejected)
+
+		avg, count := rating(t, ts, h.ID)
+		assert.Equal(t, 4.5, avg)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("ModerateReview - Changing The Status Recomputes", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+		second := review(t, ts, h.ID, "u2", 4, domain_hotels.ReviewApproved)
+		third := review(t, ts, h.ID, "u3", 4, domain_hotels.ReviewPending)
+
+		_, err := ts.ModerateReview(admin, second.ID, domain_hotels.ReviewRejected, "spam")
+		require.NoError(t, err)
+		avg, count := rating(t, ts, h.ID)
+		assert.Equal(t, 5.0, avg)
+		assert.Equal(t, 1, count)
+
+		_, err = ts.ModerateReview(admin, third.ID, domain_hotels.ReviewApproved, "")
+		require.NoError(t, err)
+		avg, count = rating(t, ts, h.ID)
+		assert.Equal(t, 4.5, avg)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("ModerateReview - Average Is Rounded To One Decimal", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+		review(t, ts, h.ID, "u2", 5, domain_hotels.ReviewApproved)
+		review(t, ts, h.ID, "u3", 4, domain_hotels.ReviewApproved)
+
+		avg, _ := rating(t, ts, h.ID)
+		assert.Equal(t, 4.7, avg)
+	})
+
+	t.Run("DeleteReview - Deleting An Approved Review Recomputes", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		first := review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+		review(t, ts, h.ID, "u2", 3, domain_hotels.ReviewApproved)
+
+		require.NoError(t, ts.DeleteReview(as("u1", false), h.ID, first.ID))
+		avg, count := rating(t, ts, h.ID)
+		assert.Equal(t, 3.0, avg)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("DeleteReview - Last Approved Review Resets The Rating", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		only := review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+
+		require.NoError(t, ts.DeleteReview(admin, h.ID, only.ID))
+		avg, count := rating(t, ts, h.ID)
+		assert.Equal(t, 0.0, avg)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("DeleteReview - Pending Review Does Not Touch The Hotel", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+		pending := review(t, ts, h.ID, "u2", 1, domain_hotels.ReviewPending)
+		before, err := ts.repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		events := len(ts.events.types)
+
+		require.NoError(t, ts.DeleteReview(as("u2", false), h.ID, pending.ID))
+
+		after, err := ts.repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+		assert.Len(t, ts.events.types, events)
+	})
+
+	t.Run("DeleteReview - Only The Author Or An Admin", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		r := review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+
+		assert.ErrorIs(t, ts.DeleteReview(as("u2", false), h.ID, r.ID), domain_hotels.ErrForbidden)
+		assert.ErrorIs(t, ts.DeleteReview(as("owner", false), h.ID, r.ID), domain_hotels.ErrForbidden)
+		assert.ErrorIs(t, ts.DeleteReview(as("u1", false), "otro-hotel", r.ID), domain_hotels.ErrReviewNotFound)
+		avg, count := rating(t, ts, h.ID)
+		assert.Equal(t, 5.0, avg)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("Rating Changes Are Published For Search", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+		r := review(t, ts, h.ID, "u1", 5, domain_hotels.ReviewApproved)
+		require.NoError(t, ts.DeleteReview(admin, h.ID, r.ID))
+
+		assert.Equal(t, []string{domain_hotels.EventHotelUpdated, domain_hotels.EventHotelUpdated}, ts.events.types)
+	})
+
+	t.Run("CreateReview - Requires A Completed Stay", func(t *testing.T) {
+		ts := newTestService(t, guests)
+		h := ts.seed(t, "owner")
+
+		_, err := ts.CreateReview(as("u9", false), h.ID, domain_hotels.Review{Rating: 5})
+
+		assert.ErrorIs(t, err, domain_hotels.ErrNoCompletedStay)
+	})
+}
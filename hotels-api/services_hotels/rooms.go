@@ -0,0 +1,117 @@
+﻿package services_hotels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"hotels/domain_hotels"
+)
+
+func (s *Service) ListRooms(ctx context.Context, hotelID string) ([]domain_hotels.RoomType, error) {
+	h, err := s.repo.GetByID(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	if h.Rooms == nil {
+		return []domain_hotels.RoomType{}, nil
+	}
+	return h.Rooms, nil
+}
+
+func (s *Service) GetRoom(ctx context.Context, hotelID string, roomID string) (domain_hotels.RoomType, error) {
+	h, err := s.repo.GetByID(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.RoomType{}, err
+	}
+	room, _, ok := h.FindRoom(roomID)
+	if !ok {
+		return domain_hotels.RoomType{}, domain_hotels.ErrRoomNotFound
+	}
+	return room, nil
+}
+
+func (s *Service) CreateRoom(ctx context.Context, hotelID string, room domain_hotels.RoomType) (domain_hotels.RoomType, error) {
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.RoomType{}, err
+	}
+	if nameTaken(h.Rooms, room.Name, "") {
+		return domain_hotels.RoomType{}, domain_hotels.ErrRoomNameTaken
+	}
+
+	room.ID = domain_hotels.NewRoomID()
+	room.Name = strings.TrimSpace(room.Name)
+	rooms := append(append([]domain_hotels.RoomType{}, h.Rooms...), room)
+	if err := s.saveRooms(ctx, h, rooms); err != nil {
+		return domain_hotels.RoomType{}, err
+	}
+	return room, nil
+}
+
+func (s *Service) UpdateRoom(ctx context.Context, hotelID string, roomID string, room domain_hotels.RoomType) (domain_hotels.RoomType, error) {
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return domain_hotels.RoomType{}, err
+	}
+	_, i, ok := h.FindRoom(roomID)
+	if !ok {
+		return domain_hotels.RoomType{}, domain_hotels.ErrRoomNotFound
+	}
+	if nameTaken(h.Rooms, room.Name, roomID) {
+		return domain_hotels.RoomType{}, domain_hotels.ErrRoomNameTaken
+	}
+
+	room.ID = roomID
+	room.Name = strings.TrimSpace(room.Name)
+	rooms := append([]domain_hotels.RoomType{}, h.Rooms...)
+	rooms[i] = room
+	if err := s.saveRooms(ctx, h, rooms); err != nil {
+		return domain_hotels.RoomType{}, err
+	}
+	return room, nil
+}
+
+// DeleteRoom: no se puede sacar un tipo de habitación con reservas activas por delante
+func (s *Service) DeleteRoom(ctx context.Context, hotelID string, roomID string) error {
+	h, err := s.managedHotel(ctx, hotelID)
+	if err != nil {
+		return err
+	}
+	_, i, ok := h.FindRoom(roomID)
+	if !ok {
+		return domain_hotels.ErrRoomNotFound
+	}
+	has, err := s.reservations.HasFutureRoomReservations(ctx, hotelID, roomID)
+	if err != nil {
+		return fmt.Errorf("%w: hotel %s: %v", domain_hotels.ErrReservationsUnavailable, hotelID, err)
+	}
+	if has {
+		return domain_hotels.ErrRoomHasBookings
+	}
+
+	rooms := append(append([]domain_hotels.RoomType{}, h.Rooms[:i]...), h.Rooms[i+1:]...)
+	return s.saveRooms(ctx, h, rooms)
+}
+
+// saveRooms guarda la lista y avisa a search-api que el hotel cambió. La lista sale de h, así
+// que solo se guarda si nadie tocó el hotel desde que se leyó (ErrVersionConflict si no).
+func (s *Service) saveRooms(ctx context.Context, h domain_hotels.Hotel, rooms []domain_hotels.RoomType) error {
+	out, err := s.repo.UpdateRooms(ctx, h.ID, rooms, h.Version)
+	if err != nil {
+		return err
+	}
+	s.record(ctx, domain_hotels.AuditUpdate, h, out)
+	s.publish(ctx, domain_hotels.EventHotelUpdated, h.ID)
+	return nil
+}
+
+func nameTaken(rooms []domain_hotels.RoomType, name string, exceptID string) bool {
+	name = strings.TrimSpace(name)
+	for _, room := range rooms {
+		if room.ID != exceptID && strings.EqualFold(room.Name, name) {
+			return true
+		}
+	}
+	return false
+}
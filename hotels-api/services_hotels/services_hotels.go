@@ -2,50 +2,266 @@
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"time"
 
 	"hotels/domain_hotels"
+	"hotels/storage_hotels"
 )
 
 // El repo que usa el service (lo satisface Mock y el repo Mongo)
 type Repository interface {
 	Create(ctx context.Context, h domain_hotels.Hotel) (domain_hotels.Hotel, error)
-	Update(ctx context.Context, id string, h domain_hotels.Hotel) (domain_hotels.Hotel, error)
+	Update(ctx context.Context, id string, h domain_hotels.Hotel, expectedVersion int64) (domain_hotels.Hotel, error)
 	GetByID(ctx context.Context, id string) (domain_hotels.Hotel, error)
-	List(ctx context.Context, q string) ([]domain_hotels.Hotel, error)
+	List(ctx context.Context, q domain_hotels.HotelQuery) (domain_hotels.HotelPage, error)
+	Archive(ctx context.Context, id string, at time.Time) (domain_hotels.Hotel, error)
+	Delete(ctx context.Context, id string) error
+	UpdateRooms(ctx context.Context, id string, rooms []domain_hotels.RoomType, expectedVersion int64) (domain_hotels.Hotel, error)
+	UpdatePricing(ctx context.Context, id string, pricing domain_hotels.PricingRules) (domain_hotels.Hotel, error)
+	UpdateImages(ctx context.Context, id string, images []domain_hotels.Image, expectedVersion int64) (domain_hotels.Hotel, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]domain_hotels.Hotel, error)
+	UpdateRating(ctx context.Context, id string, stats domain_hotels.RatingStats) (domain_hotels.Hotel, error)
+	Iterate(ctx context.Context, ownerID string, fn func(domain_hotels.Hotel) error) error
 }
 
-// La cola de eventos (para ahora puede ser un stub que no haga nada)
+// La cola de eventos (publica domain_hotels.HotelEvent en JSON)
 type Events interface {
 	Publish(event any) error
 }
 
+// Reservas del hotel en reservations-api (no se borra un hotel con reservas por delante)
+type Reservations interface {
+	HasFutureReservations(ctx context.Context, hotelID string) (bool, error)
+	HasFutureRoomReservations(ctx context.Context, hotelID string, roomID string) (bool, error)
+	CompletedStay(ctx context.Context, hotelID string, userID string) (string, error)
+}
+
 type Service struct {
-	repo Repository
-	ev   Events
+	repo         Repository
+	ev           Events
+	reservations Reservations
+	blobs        storage_hotels.BlobStore
+	reviews      ReviewRepository
+	audit        AuditRepository
 }
 
-func NewService(r Repository, e Events) *Service { return &Service{repo: r, ev: e} }
+func NewService(r Repository, e Events, res Reservations, blobs storage_hotels.BlobStore, reviews ReviewRepository, audit AuditRepository) *Service {
+	return &Service{repo: r, ev: e, reservations: res, blobs: blobs, reviews: reviews, audit: audit}
+}
 
 func (s *Service) Create(ctx context.Context, h domain_hotels.Hotel) (domain_hotels.Hotel, error) {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrUnauthorized
+	}
+	// El dueño es quien lo da de alta; un admin puede cargarlo a nombre de otro
+	if !actor.Admin || h.OwnerID == "" {
+		h.OwnerID = actor.UserID
+	}
+
+	// Las habitaciones que vienen en el alta reciben id propio
+	for i := range h.Rooms {
+		h.Rooms[i].ID = domain_hotels.NewRoomID()
+	}
 	out, err := s.repo.Create(ctx, h)
 	if err == nil {
-		_ = s.ev.Publish(map[string]any{"op": "create", "id": out.ID})
+		s.record(ctx, domain_hotels.AuditCreate, domain_hotels.Hotel{}, out)
+		s.publish(ctx, domain_hotels.EventHotelCreated, out.ID)
 	}
 	return out, err
 }
 
-func (s *Service) Update(ctx context.Context, id string, h domain_hotels.Hotel) (domain_hotels.Hotel, error) {
-	out, err := s.repo.Update(ctx, id, h)
+// publish avisa del cambio a search-api. El cambio ya se guardó, así que un evento que no entra
+// (ErrBufferFull con el broker caído) no se le devuelve al cliente: queda en el log para
+// reindexar ese hotel.
+func (s *Service) publish(ctx context.Context, eventType string, hotelID string) {
+	if err := s.ev.Publish(domain_hotels.NewHotelEvent(ctx, eventType, hotelID)); err != nil {
+		log.Printf("error publishing %s event for hotel %s, search index may be stale: %v", eventType, hotelID, err)
+	}
+}
+
+// Update (PUT): solo pisa los campos que vienen con valor. expectedVersion sale del If-Match
+// (0 = el cliente no lo mandó); igual se escribe contra la versión leída, así dos requests
+// simultáneos no se pisan en silencio.
+func (s *Service) Update(ctx context.Context, id string, h domain_hotels.Hotel, expectedVersion int64) (domain_hotels.Hotel, error) {
+	existing, err := s.editableHotel(ctx, id, expectedVersion)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	// Solo un admin puede pasarle el hotel a otro dueño
+	if actor, _ := domain_hotels.ActorFrom(ctx); !actor.Admin || h.OwnerID == "" {
+		h.OwnerID = existing.OwnerID
+	}
+	return s.save(ctx, existing, mergeHotel(existing, h))
+}
+
+// save escribe los datos editables contra la versión de existing, deja el cambio en el
+// historial y avisa a search-api
+func (s *Service) save(ctx context.Context, existing domain_hotels.Hotel, h domain_hotels.Hotel) (domain_hotels.Hotel, error) {
+	id := existing.ID
+	out, err := s.repo.Update(ctx, id, h, existing.Version)
 	if err == nil {
-		_ = s.ev.Publish(map[string]any{"op": "update", "id": id})
+		s.record(ctx, domain_hotels.AuditUpdate, existing, out)
+		s.publish(ctx, domain_hotels.EventHotelUpdated, id)
 	}
 	return out, err
 }
 
+// editableHotel: el hotel tiene que poder editarlo quien hace el request y estar en la versión esperada
+func (s *Service) editableHotel(ctx context.Context, id string, expectedVersion int64) (domain_hotels.Hotel, error) {
+	existing, err := s.managedHotel(ctx, id)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	if expectedVersion != 0 && existing.Version != expectedVersion {
+		return domain_hotels.Hotel{}, domain_hotels.ErrVersionConflict
+	}
+	return existing, nil
+}
+
+// mergeHotel: semántica de PUT /edit/:id, los campos vacíos conservan el valor actual
+func mergeHotel(existing domain_hotels.Hotel, in domain_hotels.Hotel) domain_hotels.Hotel {
+	if in.Name != "" {
+		existing.Name = in.Name
+	}
+	if in.City != "" {
+		existing.City = in.City
+	}
+	if in.PricePerNight != 0 {
+		existing.PricePerNight = in.PricePerNight
+	}
+	if in.Stars != 0 {
+		existing.Stars = in.Stars
+	}
+	if in.Amenities != nil {
+		existing.Amenities = in.Amenities
+	}
+	if in.OwnerID != "" {
+		existing.OwnerID = in.OwnerID
+	}
+	if in.Address != "" {
+		existing.Address = in.Address
+	}
+	if in.HasLocation() {
+		existing.Latitude, existing.Longitude = in.Latitude, in.Longitude
+	}
+	return existing
+}
+
 func (s *Service) GetByID(ctx context.Context, id string) (domain_hotels.Hotel, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *Service) List(ctx context.Context, q string) ([]domain_hotels.Hotel, error) {
+func (s *Service) List(ctx context.Context, q domain_hotels.HotelQuery) (domain_hotels.HotelPage, error) {
+	q, err := q.Normalize()
+	if err != nil {
+		return domain_hotels.HotelPage{}, err
+	}
 	return s.repo.List(ctx, q)
 }
+
+// ListMine: los hoteles del usuario autenticado (incluye los archivados)
+func (s *Service) ListMine(ctx context.Context) ([]domain_hotels.Hotel, error) {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return nil, domain_hotels.ErrUnauthorized
+	}
+	return s.repo.ListByOwner(ctx, actor.UserID)
+}
+
+// Archive: borrado lógico. Deja de aparecer en List y se saca del buscador.
+func (s *Service) Archive(ctx context.Context, id string) (domain_hotels.Hotel, error) {
+	h, err := s.ownedHotel(ctx, id)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	if h.Archived {
+		return h, nil
+	}
+	if err := s.checkNoFutureReservations(ctx, id); err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+
+	out, err := s.repo.Archive(ctx, id, time.Now().UTC())
+	if err == nil {
+		s.record(ctx, domain_hotels.AuditArchive, h, out)
+		s.publish(ctx, domain_hotels.EventHotelDeleted, id)
+	}
+	return out, err
+}
+
+// Delete: borrado físico (solo admin)
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	h, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkNoFutureReservations(ctx, id); err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, id)
+	if err == nil {
+		s.record(ctx, domain_hotels.AuditDelete, h, domain_hotels.Hotel{})
+		s.publish(ctx, domain_hotels.EventHotelDeleted, id)
+	}
+	return err
+}
+
+// Si no podemos consultar reservations-api no borramos nada
+func (s *Service) checkNoFutureReservations(ctx context.Context, id string) error {
+	has, err := s.reservations.HasFutureReservations(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: hotel %s: %v", domain_hotels.ErrReservationsUnavailable, id, err)
+	}
+	if has {
+		return domain_hotels.ErrHasFutureReservations
+	}
+	return nil
+}
+
+// managedHotel trae un hotel que se puede modificar: no archivado y del usuario del request (o
+// un admin). Un hotel archivado ya salió del buscador; escribirle y publicar hotel.updated lo
+// volvería a indexar, así que para editarlo es como si no existiera.
+func (s *Service) managedHotel(ctx context.Context, id string) (domain_hotels.Hotel, error) {
+	h, err := s.ownedHotel(ctx, id)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	if h.Archived {
+		return domain_hotels.Hotel{}, domain_hotels.ErrNotFound
+	}
+	return h, nil
+}
+
+// ownedHotel trae el hotel (archivado o no) y verifica que quien hace el request sea el dueño o un admin
+func (s *Service) ownedHotel(ctx context.Context, id string) (domain_hotels.Hotel, error) {
+	h, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain_hotels.Hotel{}, err
+	}
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.Hotel{}, domain_hotels.ErrUnauthorized
+	}
+	if !actor.CanManage(h) {
+		return domain_hotels.Hotel{}, domain_hotels.ErrForbidden
+	}
+	return h, nil
+}
+
+func requireAdmin(ctx context.Context) error {
+	actor, ok := domain_hotels.ActorFrom(ctx)
+	if !ok {
+		return domain_hotels.ErrUnauthorized
+	}
+	if !actor.Admin {
+		return domain_hotels.ErrForbidden
+	}
+	return nil
+}
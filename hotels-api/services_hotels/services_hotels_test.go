@@ -0,0 +1,357 @@
+package services_hotels_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hotels/domain_hotels"
+	repositories "hotels/repositories_hotels"
+	services "hotels/services_hotels"
+	storage "hotels/storage_hotels"
+)
+
+// fakeEvents guarda el tipo de cada evento publicado
+type fakeEvents struct {
+	mu    sync.Mutex
+	types []string
+}
+
+func (f *fakeEvents) Publish(event any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.types = append(f.types, event.(domain_hotels.HotelEvent).Type)
+	return nil
+}
+
+// fakeReservations: future dice si los hoteles (y cada tipo de habitación) tienen reservas por
+// delante, err simula que reservations-api no responde; completed es la reserva terminada de
+// cada usuario
+type fakeReservations struct {
+	future    bool
+	err       error
+	completed map[string]string
+}
+
+func (f fakeReservations) HasFutureReservations(ctx context.Context, hotelID string) (bool, error) {
+	return f.future, f.err
+}
+
+func (f fakeReservations) HasFutureRoomReservations(ctx context.Context, hotelID string, roomID string) (bool, error) {
+	return f.future, f.err
+}
+
+func (f fakeReservations) CompletedStay(ctx context.Context, hotelID string, userID string) (string, error) {
+	return f.completed[userID], nil
+}
+
+// testService arma el service con los repos en memoria
+type testService struct {
+	*services.Service
+	repo    *repositories.Mock
+	reviews *repositories.ReviewsMock
+	audit   *repositories.AuditMock
+	blobs   *storage.LocalFS
+	events  *fakeEvents
+}
+
+func newTestService(t *testing.T, reservations fakeReservations) testService {
+	t.Helper()
+	blobs, err := storage.NewLocalFS(t.TempDir())
+	require.NoError(t, err)
+	ts := testService{
+		repo:    repositories.NewMock(),
+		reviews: repositories.NewReviewsMock(),
+		audit:   repositories.NewAuditMock(),
+		blobs:   blobs,
+		events:  &fakeEvents{},
+	}
+	ts.Service = services.NewService(ts.repo, ts.events, reservations, ts.blobs, ts.reviews, ts.audit)
+	return ts
+}
+
+// as autentica el contexto como userID
+func as(userID string, admin bool) context.Context {
+	return domain_hotels.WithActor(context.Background(), domain_hotels.Actor{UserID: userID, Admin: admin})
+}
+
+// seed carga un hotel válido de owner directo en el repo
+func (ts testService) seed(t *testing.T, owner string) domain_hotels.Hotel {
+	t.Helper()
+	h, err := ts.repo.Create(context.Background(), domain_hotels.Hotel{Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4, OwnerID: owner})
+	require.NoError(t, err)
+	return h
+}
+
+func TestArchiveAndDelete(t *testing.T) {
+	t.Run("Archive - Hides The Hotel And Publishes hotel.deleted", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		other := ts.seed(t, "u2")
+
+		out, err := ts.Archive(as("u1", false), h.ID)
+
+		require.NoError(t, err)
+		assert.True(t, out.Archived)
+		assert.NotNil(t, out.ArchivedAt)
+		assert.Equal(t, []string{domain_hotels.EventHotelDeleted}, ts.events.types)
+		page, err := ts.List(context.Background(), domain_hotels.HotelQuery{})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, other.ID, page.Items[0].ID)
+		assert.Equal(t, int64(1), page.Total)
+
+		// El dueño lo sigue viendo en los suyos
+		mine, err := ts.ListMine(as("u1", false))
+		require.NoError(t, err)
+		assert.Len(t, mine, 1)
+	})
+
+	t.Run("Delete - Removes The Hotel And Publishes hotel.deleted", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		err := ts.Delete(as("9", true), h.ID)
+
+		require.NoError(t, err)
+		_, err = ts.repo.GetByID(context.Background(), h.ID)
+		assert.ErrorIs(t, err, domain_hotels.ErrNotFound)
+		assert.Equal(t, []string{domain_hotels.EventHotelDeleted}, ts.events.types)
+	})
+
+	t.Run("Delete - Only Admins", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		err := ts.Delete(as("u1", false), h.ID)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrForbidden)
+	})
+
+	tests := []struct {
+		name         string
+		reservations fakeReservations
+		wantErr      error
+	}{
+		{"Future Reservations", fakeReservations{future: true}, domain_hotels.ErrHasFutureReservations},
+		{"Reservations API Down", fakeReservations{err: errors.New("connection refused")}, domain_hotels.ErrReservationsUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run("Archive And Delete - Refused With "+tt.name, func(t *testing.T) {
+			ts := newTestService(t, tt.reservations)
+			h := ts.seed(t, "u1")
+
+			_, err := ts.Archive(as("u1", false), h.ID)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			err = ts.Delete(as("9", true), h.ID)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			unchanged, err := ts.repo.GetByID(context.Background(), h.ID)
+			require.NoError(t, err)
+			assert.False(t, unchanged.Archived)
+			assert.Empty(t, ts.events.types)
+		})
+	}
+}
+
+func TestArchivedHotel(t *testing.T) {
+	// archived crea un hotel con una reseña pendiente de u1 y lo archiva
+	archived := func(t *testing.T, ts testService) (domain_hotels.Hotel, domain_hotels.Review) {
+		t.Helper()
+		h := ts.seed(t, "u1")
+		review, err := ts.CreateReview(as("u2", false), h.ID, domain_hotels.Review{Rating: 4})
+		require.NoError(t, err)
+		_, err = ts.Archive(as("u1", false), h.ID)
+		require.NoError(t, err)
+		ts.events.types = nil
+		return h, review
+	}
+
+	t.Run("Archived Hotels Cannot Be Modified", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{completed: map[string]string{"u2": "r1"}})
+		h, _ := archived(t, ts)
+		owner, admin := as("u1", false), as("9", true)
+		room := domain_hotels.RoomType{Name: "Suite", Capacity: 2, Units: 1, BasePrice: 1000}
+
+		writes := map[string]func() error{
+			"Update": func() error {
+				_, err := ts.Update(owner, h.ID, domain_hotels.Hotel{Name: "Otro"}, 0)
+				return err
+			},
+			"Patch": func() error {
+				_, err := ts.Patch(admin, h.ID, []byte(`{"stars":5}`), 0)
+				return err
+			},
+			"CreateRoom": func() error {
+				_, err := ts.CreateRoom(owner, h.ID, room)
+				return err
+			},
+			"UpdatePricing": func() error {
+				_, err := ts.UpdatePricing(owner, h.ID, domain_hotels.PricingRules{MinStay: 2})
+				return err
+			},
+			"ReorderImages": func() error {
+				_, err := ts.ReorderImages(owner, h.ID, []string{})
+				return err
+			},
+			"Import": func() error {
+				report, err := ts.Import(owner, domain_hotels.FormatCSV, strings.NewReader("id,name,city,price_per_night,stars\n"+h.ID+",Otro,Córdoba,1000,4\n"), false)
+				if err == nil && report.Failed == 1 {
+					err = errors.New(report.Errors[0].Error)
+				}
+				return err
+			},
+		}
+		for name, write := range writes {
+			err := write()
+			assert.ErrorContains(t, err, domain_hotels.ErrNotFound.Error(), name)
+		}
+
+		unchanged, err := ts.repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Sheraton", unchanged.Name)
+		assert.Empty(t, unchanged.Rooms)
+		assert.Empty(t, ts.events.types)
+	})
+
+	t.Run("Archive Again And History Still Work", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{completed: map[string]string{"u2": "r1"}})
+		h, _ := archived(t, ts)
+
+		out, err := ts.Archive(as("u1", false), h.ID)
+		require.NoError(t, err)
+		assert.True(t, out.Archived)
+
+		history, err := ts.History(as("u1", false), h.ID, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, domain_hotels.AuditArchive, history.Items[0].Action)
+		assert.Empty(t, ts.events.types)
+	})
+
+	t.Run("Rating Of An Archived Hotel Is Saved But Not Published", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{completed: map[string]string{"u2": "r1"}})
+		h, review := archived(t, ts)
+
+		_, err := ts.ModerateReview(as("9", true), review.ID, domain_hotels.ReviewApproved, "")
+		require.NoError(t, err)
+
+		out, err := ts.repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4.0, out.RatingAvg)
+		assert.Empty(t, ts.events.types)
+	})
+}
+
+func TestRooms(t *testing.T) {
+	suite := domain_hotels.RoomType{Name: "Suite", Capacity: 2, Units: 3, BasePrice: 2000, Amenities: []string{"jacuzzi"}}
+
+	t.Run("Rooms - Create, Update And Delete", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		owner := as("u1", false)
+
+		created, err := ts.CreateRoom(owner, h.ID, domain_hotels.RoomType{Name: "  Suite ", Capacity: 2, Units: 3, BasePrice: 2000})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, "Suite", created.Name)
+
+		doble, err := ts.CreateRoom(owner, h.ID, domain_hotels.RoomType{Name: "Doble", Capacity: 2, Units: 10, BasePrice: 1000})
+		require.NoError(t, err)
+
+		updated, err := ts.UpdateRoom(owner, h.ID, created.ID, domain_hotels.RoomType{Name: "Suite Premium", Capacity: 4, Units: 2, BasePrice: 3000})
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, updated.ID)
+
+		got, err := ts.GetRoom(context.Background(), h.ID, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, got)
+
+		require.NoError(t, ts.DeleteRoom(owner, h.ID, doble.ID))
+		rooms, err := ts.ListRooms(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain_hotels.RoomType{updated}, rooms)
+
+		_, err = ts.GetRoom(context.Background(), h.ID, doble.ID)
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomNotFound)
+		out, err := ts.repo.GetByID(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Equal(t, h.Version+4, out.Version)
+		assert.Len(t, ts.events.types, 4)
+	})
+
+	t.Run("Rooms - Names Are Unique Ignoring Case And Spaces", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+		owner := as("u1", false)
+		first, err := ts.CreateRoom(owner, h.ID, suite)
+		require.NoError(t, err)
+		second, err := ts.CreateRoom(owner, h.ID, domain_hotels.RoomType{Name: "Doble", Capacity: 2, Units: 1, BasePrice: 1000})
+		require.NoError(t, err)
+
+		_, err = ts.CreateRoom(owner, h.ID, domain_hotels.RoomType{Name: " SUITE ", Capacity: 2, Units: 1, BasePrice: 1000})
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomNameTaken)
+
+		_, err = ts.UpdateRoom(owner, h.ID, second.ID, domain_hotels.RoomType{Name: "suite", Capacity: 2, Units: 1, BasePrice: 1000})
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomNameTaken)
+
+		// Conservar el propio nombre no es un duplicado
+		_, err = ts.UpdateRoom(owner, h.ID, first.ID, domain_hotels.RoomType{Name: "Suite", Capacity: 4, Units: 1, BasePrice: 2500})
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteRoom - Refused With Future Bookings", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{future: true})
+		h := ts.seed(t, "u1")
+		room, err := ts.CreateRoom(as("u1", false), h.ID, suite)
+		require.NoError(t, err)
+
+		err = ts.DeleteRoom(as("u1", false), h.ID, room.ID)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomHasBookings)
+		rooms, err := ts.ListRooms(context.Background(), h.ID)
+		require.NoError(t, err)
+		assert.Len(t, rooms, 1)
+	})
+
+	t.Run("DeleteRoom - Reservations Unavailable", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{err: errors.New("connection refused")})
+		h := ts.seed(t, "u1")
+		room, err := ts.CreateRoom(as("u1", false), h.ID, suite)
+		require.NoError(t, err)
+
+		err = ts.DeleteRoom(as("u1", false), h.ID, room.ID)
+
+		assert.ErrorIs(t, err, domain_hotels.ErrReservationsUnavailable)
+	})
+
+	t.Run("Rooms - Only The Owner Or An Admin", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		_, err := ts.CreateRoom(as("u2", false), h.ID, suite)
+		assert.ErrorIs(t, err, domain_hotels.ErrForbidden)
+
+		_, err = ts.CreateRoom(context.Background(), h.ID, suite)
+		assert.ErrorIs(t, err, domain_hotels.ErrUnauthorized)
+
+		_, err = ts.CreateRoom(as("9", true), h.ID, suite)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rooms - Unknown Room", func(t *testing.T) {
+		ts := newTestService(t, fakeReservations{})
+		h := ts.seed(t, "u1")
+
+		_, err := ts.UpdateRoom(as("u1", false), h.ID, "rt_missing", suite)
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomNotFound)
+
+		err = ts.DeleteRoom(as("u1", false), h.ID, "rt_missing")
+		assert.ErrorIs(t, err, domain_hotels.ErrRoomNotFound)
+	})
+}
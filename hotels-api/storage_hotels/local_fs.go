@@ -0,0 +1,87 @@
+package storage_hotels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalFS guarda los blobs como archivos debajo de Root
+type LocalFS struct {
+	Root string
+}
+
+func NewLocalFS(root string) (*LocalFS, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating storage dir %s: %w", root, err)
+	}
+	return &LocalFS{Root: root}, nil
+}
+
+// path traduce la clave a un archivo sin dejar salir de Root ("../" y rutas absolutas)
+func (l *LocalFS) path(key string) (string, error) {
+	clean := filepath.Clean("/" + strings.TrimSpace(key))
+	if clean == "/" {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.Root, filepath.FromSlash(clean)), nil
+}
+
+func (l *LocalFS) Put(ctx context.Context, key string, data []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating dir for %s: %w", key, err)
+	}
+
+	// Se escribe a un temporal y se renombra para no servir archivos a medio escribir
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("error writing %s: %w", key, err)
+	}
+	return nil
+}
+
+func (l *LocalFS) Get(ctx context.Context, key string) (Blob, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return Blob{}, ErrBlobNotFound
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return Blob{}, ErrBlobNotFound
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Blob{}, ErrBlobNotFound
+		}
+		return Blob{}, fmt.Errorf("error reading %s: %w", key, err)
+	}
+	return Blob{
+		Data:        data,
+		ContentType: http.DetectContentType(data),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+func (l *LocalFS) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting %s: %w", key, err)
+	}
+	return nil
+}
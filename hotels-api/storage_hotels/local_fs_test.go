@@ -0,0 +1,97 @@
+package storage_hotels_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storage "hotels/storage_hotels"
+)
+
+var gif = []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")
+
+func TestLocalFS(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Put - Get Returns The Data And Content Type", func(t *testing.T) {
+		root := t.TempDir()
+		store, err := storage.NewLocalFS(root)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(ctx, "hotels/h1/img_1.gif", gif))
+		blob, err := store.Get(ctx, "hotels/h1/img_1.gif")
+
+		require.NoError(t, err)
+		assert.Equal(t, gif, blob.Data)
+		assert.Equal(t, "image/gif", blob.ContentType)
+		assert.False(t, blob.ModTime.IsZero())
+		_, err = os.Stat(filepath.Join(root, "hotels", "h1", "img_1.gif.tmp"))
+		assert.True(t, os.IsNotExist(err), "temporary file left behind")
+	})
+
+	t.Run("Put - Replaces An Existing Blob", func(t *testing.T) {
+		store, err := storage.NewLocalFS(t.TempDir())
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(ctx, "a.txt", []byte("old")))
+		require.NoError(t, store.Put(ctx, "a.txt", []byte("new")))
+		blob, err := store.Get(ctx, "a.txt")
+
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(blob.Data))
+	})
+
+	t.Run("Keys Cannot Leave The Root", func(t *testing.T) {
+		parent := t.TempDir()
+		root := filepath.Join(parent, "images")
+		store, err := storage.NewLocalFS(root)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put(ctx, "../../escaped.gif", gif))
+		require.NoError(t, store.Put(ctx, "/abs/escaped.gif", gif))
+
+		_, err = os.Stat(filepath.Join(parent, "escaped.gif"))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(root, "escaped.gif"))
+		assert.NoError(t, err)
+		_, err = os.Stat(filepath.Join(root, "abs", "escaped.gif"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid Keys", func(t *testing.T) {
+		store, err := storage.NewLocalFS(t.TempDir())
+		require.NoError(t, err)
+
+		for _, key := range []string{"", " ", "/", "..", "a/.."} {
+			assert.Error(t, store.Put(ctx, key, gif), key)
+			_, err := store.Get(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrBlobNotFound, key)
+		}
+	})
+
+	t.Run("Get - Missing Blob Or Directory", func(t *testing.T) {
+		store, err := storage.NewLocalFS(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, "hotels/h1/img_1.gif", gif))
+
+		_, err = store.Get(ctx, "hotels/h1/missing.gif")
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+		_, err = store.Get(ctx, "hotels/h1")
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+	})
+
+	t.Run("Delete - Removes The Blob And Ignores Missing Ones", func(t *testing.T) {
+		store, err := storage.NewLocalFS(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Put(ctx, "hotels/h1/img_1.gif", gif))
+
+		require.NoError(t, store.Delete(ctx, "hotels/h1/img_1.gif"))
+		_, err = store.Get(ctx, "hotels/h1/img_1.gif")
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+		assert.NoError(t, store.Delete(ctx, "hotels/h1/img_1.gif"))
+	})
+}
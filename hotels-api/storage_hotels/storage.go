@@ -0,0 +1,24 @@
+package storage_hotels
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Blob es un archivo guardado en el store
+type Blob struct {
+	Data        []byte
+	ContentType string
+	ModTime     time.Time
+}
+
+// BlobStore guarda archivos por clave ("hotels/<id>/<imagen>.jpg"). LocalFS es la primera
+// implementación; un bucket (S3, GCS) tiene que cumplir la misma interfaz.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) (Blob, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,104 @@
+package clients_reservations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	domain "reservations/domain_reservations"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HotelsConfig struct {
+	Host string
+	Port string
+}
+
+// Hotels consulta hotels-api (datos del hotel y sus tipos de habitación)
+type Hotels struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHotels(cfg HotelsConfig) *Hotels {
+	return &Hotels{
+		baseURL: fmt.Sprintf("http://%s:%s", cfg.Host, cfg.Port),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// GetHotel respeta el deadline de ctx además del timeout del cliente
+func (h *Hotels) GetHotel(ctx context.Context, hotelID string) (domain.Hotel, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+"/hotels/"+url.PathEscape(hotelID), nil)
+	if err != nil {
+		return domain.Hotel{}, fmt.Errorf("error building hotels API request: %w", err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return domain.Hotel{}, fmt.Errorf("error contacting hotels API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return domain.Hotel{}, domain.ErrHotelNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return domain.Hotel{}, fmt.Errorf("hotels API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var hotel domain.Hotel
+	if err := json.NewDecoder(resp.Body).Decode(&hotel); err != nil {
+		return domain.Hotel{}, fmt.Errorf("error decoding hotel: %w", err)
+	}
+	return hotel, nil
+}
+
+// Quote pide a hotels-api el precio de la estadía, dentro del deadline de ctx. Un 400 (estadía
+// mínima, capacidad, fechas) vuelve como "invalid stay: <motivo>"; un 404 es que no existe el
+// hotel o el tipo de habitación.
+func (h *Hotels) Quote(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time, guests int) (domain.Quote, error) {
+	query := url.Values{}
+	query.Set("check_in", checkIn.UTC().Format(time.DateOnly))
+	query.Set("check_out", checkOut.UTC().Format(time.DateOnly))
+	query.Set("guests", strconv.Itoa(guests))
+	if roomType != "" {
+		query.Set("room_type", roomType)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+"/hotels/"+url.PathEscape(hotelID)+"/quote?"+query.Encode(), nil)
+	if err != nil {
+		return domain.Quote{}, fmt.Errorf("error building hotels API request: %w", err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return domain.Quote{}, fmt.Errorf("error contacting hotels API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return domain.Quote{}, fmt.Errorf("invalid stay: %s", strings.TrimSpace(string(body)))
+	case http.StatusNotFound:
+		if roomType != "" {
+			return domain.Quote{}, errors.New("room type not found")
+		}
+		return domain.Quote{}, domain.ErrHotelNotFound
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return domain.Quote{}, fmt.Errorf("hotels API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var quote domain.Quote
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		return domain.Quote{}, fmt.Errorf("error decoding quote: %w", err)
+	}
+	return quote, nil
+}
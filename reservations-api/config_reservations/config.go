@@ -0,0 +1,76 @@
+package config_reservations
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Backends posibles para RESERVATIONS_REPOSITORY
+const (
+	RepositoryMySQL  = "mysql"
+	RepositorySQLite = "sqlite"
+	RepositoryMemory = "memory"
+)
+
+// Config de reservations-api; todo sale de variables de entorno con defaults para docker-compose
+type Config struct {
+	Port       string
+	Repository string // "mysql" (default), "sqlite" o "memory"
+	SeedFile   string // reservas de ejemplo que se cargan si la base está vacía ("" para no cargar nada)
+	SQLitePath string
+
+	ExpireInterval time.Duration // cada cuánto se vencen las reservas pendientes
+
+	MySQL MySQL
+
+	HotelsHost string
+	HotelsPort string
+
+	JWTKey string // misma clave con la que users-api firma los tokens
+}
+
+type MySQL struct {
+	Host     string
+	Port     string
+	Database string
+	Username string
+	Password string
+}
+
+// Load lee la configuración del entorno
+func Load() Config {
+	return Config{
+		Port:       getEnv("RESERVATIONS_PORT", "8086"),
+		Repository: strings.ToLower(getEnv("RESERVATIONS_REPOSITORY", RepositoryMySQL)),
+		SeedFile:   getEnv("RESERVATIONS_SEED_FILE", "db/reservations.json"),
+		SQLitePath: getEnv("RESERVATIONS_SQLITE_PATH", "data/reservations.db"),
+
+		ExpireInterval: getDuration("RESERVATIONS_EXPIRE_INTERVAL", 15*time.Minute),
+		MySQL: MySQL{
+			Host:     getEnv("MYSQL_HOST", "mysql"),
+			Port:     getEnv("MYSQL_PORT", "3306"),
+			Database: getEnv("MYSQL_DATABASE", "reservations"),
+			Username: getEnv("MYSQL_USERNAME", "root"),
+			Password: getEnv("MYSQL_PASSWORD", "RaTa8855"),
+		},
+		HotelsHost: getEnv("HOTELS_HOST", "hotels-api"),
+		HotelsPort: getEnv("HOTELS_PORT", "8081"),
+		JWTKey:     getEnv("JWT_KEY", "ThisIsAnExampleJWTKey!"),
+	}
+}
+
+func getEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
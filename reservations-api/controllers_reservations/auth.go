@@ -0,0 +1,68 @@
+package controllers_reservations
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	domain "reservations/domain_reservations"
+)
+
+// RequireAuth valida el JWT de users-api (Authorization: Bearer <token>) y deja el usuario
+// en el contexto del request. Sin token válido corta con 401.
+func RequireAuth(key string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": domain.ErrUnauthorized.Error()})
+			return
+		}
+
+		actor, err := parseToken(strings.TrimSpace(token), key)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(domain.WithActor(ctx.Request.Context(), actor))
+		ctx.Next()
+	}
+}
+
+// parseToken: users-api firma con HS256 y manda username, user_id, admin y expiration_date
+// (fecha en texto, no el "exp" estándar)
+func parseToken(value string, key string) (domain.Actor, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(value, claims, func(*jwt.Token) (any, error) {
+		return []byte(key), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return domain.Actor{}, err
+	}
+
+	expiration, _ := claims["expiration_date"].(string)
+	expiresAt, err := time.Parse(time.RFC3339Nano, expiration)
+	if err != nil {
+		return domain.Actor{}, fmt.Errorf("invalid expiration_date: %w", err)
+	}
+	if time.Now().After(expiresAt) {
+		return domain.Actor{}, errors.New("token expired")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID <= 0 {
+		return domain.Actor{}, errors.New("invalid user_id")
+	}
+	admin, _ := claims["admin"].(bool)
+
+	return domain.Actor{
+		UserID: strconv.FormatInt(int64(userID), 10),
+		Admin:  admin,
+	}, nil
+}
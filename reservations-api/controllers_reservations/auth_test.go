@@ -0,0 +1,89 @@
+package controllers_reservations_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	controllers "reservations/controllers_reservations"
+	domain "reservations/domain_reservations"
+)
+
+const jwtKey = "test-key"
+
+// token firma los claims como users-api
+func token(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func claims(userID float64, admin bool, expiresIn time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"username":        "ana",
+		"user_id":         userID,
+		"admin":           admin,
+		"expiration_date": time.Now().Add(expiresIn).Format(time.RFC3339),
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/reservations/:id/confirm", controllers.RequireAuth(jwtKey), func(ctx *gin.Context) {
+		actor, _ := domain.ActorFrom(ctx.Request.Context())
+		ctx.JSON(http.StatusOK, gin.H{"user_id": actor.UserID, "admin": actor.Admin})
+	})
+	confirm := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/reservations/r1/confirm", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("RequireAuth - Valid Token", func(t *testing.T) {
+		recorder := confirm("Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(7, true, time.Hour)))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"user_id":"7","admin":true}`, recorder.Body.String())
+	})
+
+	t.Run("RequireAuth - Missing Header", func(t *testing.T) {
+		valid := token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(7, false, time.Hour))
+		for _, authorization := range []string{"", "Bearer ", valid, "Basic " + valid} {
+			recorder := confirm(authorization)
+
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code, authorization)
+			assert.JSONEq(t, `{"error":"authentication required"}`, recorder.Body.String(), authorization)
+		}
+	})
+
+	t.Run("RequireAuth - Invalid Tokens", func(t *testing.T) {
+		noUser := claims(7, false, time.Hour)
+		delete(noUser, "user_id")
+		tokens := map[string]string{
+			"expired":   token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(7, false, -time.Minute)),
+			"wrong key": token(t, jwt.SigningMethodHS256, []byte("other-key"), claims(7, false, time.Hour)),
+			"HS512":     token(t, jwt.SigningMethodHS512, []byte(jwtKey), claims(7, false, time.Hour)),
+			"none":      token(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(7, false, time.Hour)),
+			"no user":   token(t, jwt.SigningMethodHS256, []byte(jwtKey), noUser),
+			"garbage":   "not.a.token",
+		}
+		for name, value := range tokens {
+			recorder := confirm("Bearer " + value)
+
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code, name)
+			assert.JSONEq(t, `{"error":"invalid token"}`, recorder.Body.String(), name)
+		}
+	})
+}
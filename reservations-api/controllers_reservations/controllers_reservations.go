@@ -1,8 +1,13 @@
 package controllers_reservations
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	domain "reservations/domain_reservations"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,7 +30,7 @@ func (c *Controller) Create(ctx *gin.Context) {
 		return
 	}
 
-	created, err := c.svc.Create(req)
+	created, err := c.svc.Create(ctx.Request.Context(), req)
 	if err != nil {
 		status := http.StatusInternalServerError
 
@@ -35,7 +40,14 @@ func (c *Controller) Create(ctx *gin.Context) {
 			status = http.StatusNotFound
 		case "check-in must be before check-out",
 			"check-in cannot be in the past",
-			"las fechas se solapan con una reserva existente":
+			"las fechas se solapan con una reserva existente",
+			"no rooms of this type available for the selected dates",
+			"room_type is required",
+			"room type not found",
+			"total_price does not match the quoted price":
+			status = http.StatusBadRequest
+		}
+		if strings.HasPrefix(err.Error(), "invalid stay: ") {
 			status = http.StatusBadRequest
 		}
 
@@ -61,6 +73,19 @@ func (c *Controller) GetByID(ctx *gin.Context) {
 }
 
 func (c *Controller) List(ctx *gin.Context) {
+	// Si hay hotel_id query param, filtrar por hotel (lo usa hotels-api antes de borrar un hotel)
+	if hotelID := ctx.Query("hotel_id"); hotelID != "" {
+		reservations, err := c.svc.GetByHotelID(hotelID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK, reservations)
+		return
+	}
+
 	// Si hay user_id query param, filtrar por usuario
 	userID := ctx.Query("user_id")
 
@@ -88,6 +113,87 @@ func (c *Controller) List(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, reservations)
 }
 
+// Availability: GET /reservations/availability?hotel_ids=a,b&check_in=2025-11-20&check_out=2025-11-23&guests=2
+func (c *Controller) Availability(ctx *gin.Context) {
+	hotelIDs := make([]string, 0)
+	for _, id := range strings.Split(ctx.Query("hotel_ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			hotelIDs = append(hotelIDs, id)
+		}
+	}
+	checkIn, checkOut, guests, ok := parseStay(ctx)
+	if !ok {
+		return
+	}
+
+	availability, err := c.svc.Availability(ctx.Request.Context(), hotelIDs, checkIn, checkOut, guests)
+	if err != nil {
+		ctx.JSON(availabilityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, availability)
+}
+
+// Unavailable: GET /reservations/unavailable?check_in=2025-11-20&check_out=2025-11-23&guests=2
+// Lo usa search-api para sacar de la búsqueda los hoteles completos.
+func (c *Controller) Unavailable(ctx *gin.Context) {
+	checkIn, checkOut, guests, ok := parseStay(ctx)
+	if !ok {
+		return
+	}
+
+	hotelIDs, err := c.svc.Unavailable(ctx.Request.Context(), checkIn, checkOut, guests)
+	if err != nil {
+		ctx.JSON(availabilityErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"hotel_ids": hotelIDs})
+}
+
+// parseStay lee check_in, check_out y guests (opcional) de la query; si alguno es inválido ya respondió 400
+func parseStay(ctx *gin.Context) (time.Time, time.Time, int, bool) {
+	checkIn, err := parseDate(ctx.Query("check_in"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid check_in", "details": err.Error()})
+		return time.Time{}, time.Time{}, 0, false
+	}
+	checkOut, err := parseDate(ctx.Query("check_out"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid check_out", "details": err.Error()})
+		return time.Time{}, time.Time{}, 0, false
+	}
+
+	guests := 0
+	if value := ctx.Query("guests"); value != "" {
+		if guests, err = strconv.Atoi(value); err != nil || guests < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid guests"})
+			return time.Time{}, time.Time{}, 0, false
+		}
+	}
+	return checkIn, checkOut, guests, true
+}
+
+func availabilityErrorStatus(err error) int {
+	switch {
+	case err.Error() == "at least one hotel_id is required", err.Error() == "check-in must be before check-out":
+		return http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseDate acepta fechas "2006-01-02" o RFC3339
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
 func (c *Controller) Update(ctx *gin.Context) {
 	id := ctx.Param("id")
 
@@ -100,14 +206,19 @@ func (c *Controller) Update(ctx *gin.Context) {
 		return
 	}
 
-	updated, err := c.svc.Update(id, req)
+	updated, err := c.svc.Update(ctx.Request.Context(), id, req)
 	if err != nil {
 		status := http.StatusInternalServerError
 
 		if err.Error() == "reservation not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "cannot modify cancelled reservation" ||
-			err.Error() == "las fechas se solapan con otra reserva" {
+		} else if strings.HasPrefix(err.Error(), "cannot modify ") ||
+			err.Error() == "las fechas se solapan con otra reserva" ||
+			err.Error() == "no rooms of this type available for the selected dates" ||
+			err.Error() == "room_type is required" ||
+			err.Error() == "room type not found" ||
+			err.Error() == "total_price does not match the quoted price" ||
+			strings.HasPrefix(err.Error(), "invalid stay: ") {
 			status = http.StatusBadRequest
 		}
 
@@ -142,18 +253,92 @@ func (c *Controller) Cancel(ctx *gin.Context) {
 
 	cancelled, err := c.svc.Cancel(id)
 	if err != nil {
-		status := http.StatusInternalServerError
+		ctx.JSON(transitionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
 
-		if err.Error() == "reservation not found" {
-			status = http.StatusNotFound
-		} else if err.Error() == "reservation already cancelled" ||
-			err.Error() == "cannot cancel reservation that has already started" {
-			status = http.StatusBadRequest
+	ctx.JSON(http.StatusOK, cancelled)
+}
+
+// POST /reservations/:id/confirm
+func (c *Controller) Confirm(ctx *gin.Context) {
+	c.transition(ctx, domain.StatusConfirmed)
+}
+
+// POST /reservations/:id/check-in (solo el día de llegada)
+func (c *Controller) CheckIn(ctx *gin.Context) {
+	c.transition(ctx, domain.StatusCheckedIn)
+}
+
+// POST /reservations/:id/check-out
+func (c *Controller) CheckOut(ctx *gin.Context) {
+	c.transition(ctx, domain.StatusCompleted)
+}
+
+// POST /reservations/:id/no-show (desde el día de llegada)
+func (c *Controller) NoShow(ctx *gin.Context) {
+	c.transition(ctx, domain.StatusNoShow)
+}
+
+// transition aplica el cambio de estado (solo el dueño del hotel o un admin, ver RequireAuth);
+// el body es opcional: {"reason": "..."}
+func (c *Controller) transition(ctx *gin.Context, to string) {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
 		}
+	}
 
-		ctx.JSON(status, gin.H{"error": err.Error()})
+	updated, err := c.svc.HotelTransition(ctx.Request.Context(), ctx.Param("id"), to, body.Reason)
+	if err != nil {
+		ctx.JSON(transitionErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, cancelled)
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// GET /reservations/:id/transitions (historial de estados, del más viejo al más nuevo)
+func (c *Controller) Transitions(ctx *gin.Context) {
+	reservation, err := c.svc.GetByID(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": "Reservation not found",
+		})
+		return
+	}
+
+	transitions := reservation.Transitions
+	if transitions == nil {
+		transitions = []domain.Transition{}
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":      reservation.Status,
+		"transitions": transitions,
+	})
+}
+
+func transitionErrorStatus(err error) int {
+	switch {
+	case err.Error() == "reservation not found":
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrStatusChanged):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrTransitionGuard), errors.Is(err, domain.ErrAlreadyCancelled),
+		errors.Is(err, domain.ErrAlreadyStarted):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
 }
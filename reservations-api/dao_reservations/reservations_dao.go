@@ -0,0 +1,34 @@
+package dao_reservations
+
+import (
+	"time"
+
+	domain "reservations/domain_reservations"
+)
+
+// Reservation es la fila de la tabla reservations (el id es numérico, la API lo expone como texto)
+type Reservation struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	HotelID    string    `gorm:"size:64;not null;index:idx_reservations_hotel_dates,priority:1"`
+	UserID     string    `gorm:"size:64;not null;index"`
+	CheckIn    time.Time `gorm:"not null;index:idx_reservations_hotel_dates,priority:2"`
+	CheckOut   time.Time `gorm:"not null"`
+	Guests     int       `gorm:"not null"`
+	RoomType   string    `gorm:"size:64"`
+	TotalPrice float64
+	Status     string `gorm:"size:20;not null;index"`
+	CreatedAt  time.Time
+
+	Currency       string                 `gorm:"size:3"`
+	PriceBreakdown *domain.PriceBreakdown `gorm:"type:text;serializer:json"`
+	Transitions    []domain.Transition    `gorm:"type:text;serializer:json"`
+}
+
+// HotelLock: una fila por hotel. Crear o editar una reserva la bloquea dentro de la transacción,
+// así dos reservas del mismo hotel no pueden validar disponibilidad al mismo tiempo.
+type HotelLock struct {
+	HotelID  string `gorm:"primaryKey;size:64"`
+	LockedAt time.Time
+}
+
+func (HotelLock) TableName() string { return "reservation_hotel_locks" }
@@ -0,0 +1,39 @@
+package domain_reservations
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnauthorized = errors.New("authentication required")
+	ErrForbidden    = errors.New("only the hotel owner or an admin can do this")
+)
+
+// Actor es el usuario autenticado del request (sale del JWT que emite users-api)
+type Actor struct {
+	UserID string
+	Admin  bool
+}
+
+// CanOperate: los cambios de estado del lado del hotel (confirmar, check-in, check-out, no-show)
+// son del dueño del hotel o de un admin
+func (a Actor) CanOperate(h Hotel) bool {
+	return a.Admin || (a.UserID != "" && a.UserID == h.OwnerID)
+}
+
+type actorKey struct{}
+
+// WithActor guarda el usuario autenticado en el contexto del request
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom devuelve el usuario del contexto (false si el request no viene autenticado)
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -1,6 +1,13 @@
 package domain_reservations
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrHotelNotFound: hotels-api no tiene el hotel
+var ErrHotelNotFound = errors.New("hotel not found")
 
 type Reservation struct {
 	ID         string    `json:"id"`
@@ -9,30 +16,102 @@ type Reservation struct {
 	CheckIn    time.Time `json:"check_in"`
 	CheckOut   time.Time `json:"check_out"`
 	Guests     int       `json:"guests"`
-	RoomType   string    `json:"room_type"`
+	RoomType   string    `json:"room_type"` // id del tipo de habitación en hotels-api
 	TotalPrice float64   `json:"total_price"`
-	Status     string    `json:"status"` // "confirmed", "cancelled", "completed"
+	Status     string    `json:"status"` // ver status.go; solo cambia con las transiciones
 	CreatedAt  time.Time `json:"created_at"`
+
+	Transitions []Transition `json:"transitions,omitempty"`
+
+	// Los calcula el servidor con la cotización de hotels-api (lo que mande el cliente se ignora)
+	Currency       string          `json:"currency,omitempty"`
+	PriceBreakdown *PriceBreakdown `json:"price_breakdown,omitempty"`
+}
+
+// PriceBreakdown es el detalle de la cotización con la que se calculó TotalPrice
+type PriceBreakdown struct {
+	Nightly     []NightPrice `json:"nightly"`
+	Subtotal    float64      `json:"subtotal"`
+	DiscountPct float64      `json:"discount_pct,omitempty"`
+	Discount    float64      `json:"discount,omitempty"`
+}
+
+type NightPrice struct {
+	Date       string  `json:"date"`
+	Base       float64 `json:"base"`
+	Season     string  `json:"season,omitempty"`
+	SeasonPct  float64 `json:"season_pct,omitempty"`
+	WeekendPct float64 `json:"weekend_pct,omitempty"`
+	Price      float64 `json:"price"`
+}
+
+// Quote es la respuesta de GET /hotels/:id/quote de hotels-api
+type Quote struct {
+	HotelID     string       `json:"hotel_id"`
+	RoomType    string       `json:"room_type,omitempty"`
+	Nights      int          `json:"nights"`
+	Guests      int          `json:"guests"`
+	Currency    string       `json:"currency"`
+	Nightly     []NightPrice `json:"nightly"`
+	Subtotal    float64      `json:"subtotal"`
+	DiscountPct float64      `json:"discount_pct,omitempty"`
+	Discount    float64      `json:"discount,omitempty"`
+	Total       float64      `json:"total"`
+}
+
+// Hotel es lo que necesitamos de hotels-api para validar una reserva
+type Hotel struct {
+	ID       string     `json:"id"`
+	OwnerID  string     `json:"owner_id"`
+	Archived bool       `json:"archived"`
+	Rooms    []RoomType `json:"rooms"`
+}
+
+type RoomType struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	Units    int    `json:"units"`
+}
+
+// Inventory son las habitaciones disponibles del tipo reservado.
+// Units == 0 significa hotel sin habitaciones cargadas: cualquier solapamiento lo bloquea entero.
+type Inventory struct {
+	RoomType string
+	Units    int
 }
 
 type Repository interface {
-	Create(r Reservation) (Reservation, error)
+	Create(r Reservation, inv Inventory) (Reservation, error)
 	GetByID(id string) (Reservation, error)
 	GetByUserID(userID string) ([]Reservation, error)
+	GetByHotelID(hotelID string) ([]Reservation, error)
 	List() ([]Reservation, error)
-	Update(id string, r Reservation) (Reservation, error)
+	Update(id string, r Reservation, inv Inventory) (Reservation, error)
+	GetByStatus(status string) ([]Reservation, error)
 	Delete(id string) error
-	Cancel(id string) (Reservation, error)
+	// Transition pasa la reserva de t.From a t.To y agrega t al historial. Si el estado ya no
+	// es t.From (otro request lo cambió) devuelve ErrStatusChanged.
+	Transition(id string, t Transition) (Reservation, error)
 	CheckOverlap(hotelID string, checkIn, checkOut time.Time, excludeID string) (bool, error)
+	RoomsInUse(hotelID, roomType string, checkIn, checkOut time.Time, excludeID string) (int, error)
+	// BookedHotels: hoteles con alguna reserva activa que se cruza con [checkIn, checkOut)
+	BookedHotels(checkIn, checkOut time.Time) ([]string, error)
 	SeedFromJSON(path string) error
 }
 
 type Service interface {
-	Create(r Reservation) (Reservation, error)
+	Create(ctx context.Context, r Reservation) (Reservation, error)
 	GetByID(id string) (Reservation, error)
 	GetByUserID(userID string) ([]Reservation, error)
+	GetByHotelID(hotelID string) ([]Reservation, error)
 	List() ([]Reservation, error)
-	Update(id string, r Reservation) (Reservation, error)
+	Update(ctx context.Context, id string, r Reservation) (Reservation, error)
 	Delete(id string) error
 	Cancel(id string) (Reservation, error)
+	Transition(id string, to string, reason string) (Reservation, error)
+	HotelTransition(ctx context.Context, id string, to string, reason string) (Reservation, error)
+	ExpirePending() (int, error)
+	Availability(ctx context.Context, hotelIDs []string, checkIn, checkOut time.Time, guests int) (map[string]bool, error)
+	Unavailable(ctx context.Context, checkIn, checkOut time.Time, guests int) ([]string, error)
 }
@@ -0,0 +1,109 @@
+package domain_reservations
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Estados de una reserva:
+//
+//	pending → confirmed → checked_in → completed
+//	pending → cancelled | expired
+//	confirmed → cancelled | no_show
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusCheckedIn = "checked_in"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusNoShow    = "no_show"
+	StatusExpired   = "expired"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrTransitionGuard   = errors.New("transition not allowed")
+	ErrStatusChanged     = errors.New("reservation status changed, try again")
+	ErrAlreadyCancelled  = errors.New("reservation already cancelled")
+	ErrAlreadyStarted    = errors.New("cannot cancel reservation that has already started")
+)
+
+// Los estados a los que se puede pasar desde cada uno (los que no están son finales)
+var transitions = map[string][]string{
+	StatusPending:   {StatusConfirmed, StatusCancelled, StatusExpired},
+	StatusConfirmed: {StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusCheckedIn: {StatusCompleted},
+}
+
+// Transition es un cambio de estado en el historial de la reserva
+type Transition struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// CanTransition: si la máquina de estados permite pasar de from a to
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// HoldsRooms: la reserva ocupa habitaciones en sus fechas (cancelada, vencida o no-show las libera)
+func HoldsRooms(status string) bool {
+	return status != StatusCancelled && status != StatusExpired && status != StatusNoShow
+}
+
+// ReleasedStatuses son los estados que no ocupan habitaciones (para filtrar en la base)
+var ReleasedStatuses = []string{StatusCancelled, StatusExpired, StatusNoShow}
+
+// Modifiable: solo se pueden cambiar fechas o habitación antes de la llegada
+func Modifiable(status string) bool {
+	return status == StatusPending || status == StatusConfirmed
+}
+
+// CheckTransition valida el cambio de estado: que la máquina lo permita y las condiciones de
+// fecha de cada uno (las fechas se comparan por día, en UTC)
+func (r Reservation) CheckTransition(to string, now time.Time) error {
+	if to == StatusCancelled && r.Status == StatusCancelled {
+		return ErrAlreadyCancelled
+	}
+	if !CanTransition(r.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, r.Status, to)
+	}
+
+	today, arrival := dateOf(now), dateOf(r.CheckIn)
+	switch to {
+	case StatusConfirmed:
+		if today.After(arrival) {
+			return fmt.Errorf("%w: the arrival date already passed", ErrTransitionGuard)
+		}
+	case StatusCheckedIn:
+		if !today.Equal(arrival) {
+			return fmt.Errorf("%w: check-in is only allowed on the arrival date", ErrTransitionGuard)
+		}
+	case StatusCancelled:
+		if r.CheckIn.Before(now) {
+			return ErrAlreadyStarted
+		}
+	case StatusNoShow:
+		if today.Before(arrival) {
+			return fmt.Errorf("%w: no-show can only be recorded from the arrival date", ErrTransitionGuard)
+		}
+	case StatusExpired:
+		if now.Before(r.CheckIn) {
+			return fmt.Errorf("%w: a pending reservation expires at check-in time", ErrTransitionGuard)
+		}
+	}
+	return nil
+}
+
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,65 @@
+package domain_reservations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domain "reservations/domain_reservations"
+)
+
+func TestStateMachine(t *testing.T) {
+	arrival := time.Date(2025, 11, 20, 15, 0, 0, 0, time.UTC)
+	reservation := func(status string) domain.Reservation {
+		return domain.Reservation{ID: "1", Status: status, CheckIn: arrival, CheckOut: arrival.AddDate(0, 0, 3)}
+	}
+	dayBefore := arrival.AddDate(0, 0, -1)
+	arrivalMorning := time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC)
+	dayAfter := arrival.AddDate(0, 0, 1)
+
+	t.Run("CanTransition - Allowed Paths", func(t *testing.T) {
+		assert.True(t, domain.CanTransition(domain.StatusPending, domain.StatusConfirmed))
+		assert.True(t, domain.CanTransition(domain.StatusConfirmed, domain.StatusCheckedIn))
+		assert.True(t, domain.CanTransition(domain.StatusCheckedIn, domain.StatusCompleted))
+		assert.True(t, domain.CanTransition(domain.StatusPending, domain.StatusExpired))
+		assert.True(t, domain.CanTransition(domain.StatusConfirmed, domain.StatusNoShow))
+
+		assert.False(t, domain.CanTransition(domain.StatusPending, domain.StatusCheckedIn))
+		assert.False(t, domain.CanTransition(domain.StatusCheckedIn, domain.StatusCancelled))
+		assert.False(t, domain.CanTransition(domain.StatusCompleted, domain.StatusConfirmed))
+		assert.False(t, domain.CanTransition(domain.StatusCancelled, domain.StatusConfirmed))
+	})
+
+	t.Run("CheckTransition - Check-in Only On Arrival Date", func(t *testing.T) {
+		r := reservation(domain.StatusConfirmed)
+
+		assert.ErrorIs(t, r.CheckTransition(domain.StatusCheckedIn, dayBefore), domain.ErrTransitionGuard)
+		assert.NoError(t, r.CheckTransition(domain.StatusCheckedIn, arrivalMorning))
+		assert.ErrorIs(t, r.CheckTransition(domain.StatusCheckedIn, dayAfter), domain.ErrTransitionGuard)
+	})
+
+	t.Run("CheckTransition - Cancel Before Start", func(t *testing.T) {
+		assert.NoError(t, reservation(domain.StatusPending).CheckTransition(domain.StatusCancelled, dayBefore))
+		assert.ErrorIs(t, reservation(domain.StatusConfirmed).CheckTransition(domain.StatusCancelled, dayAfter), domain.ErrAlreadyStarted)
+		assert.ErrorIs(t, reservation(domain.StatusCancelled).CheckTransition(domain.StatusCancelled, dayBefore), domain.ErrAlreadyCancelled)
+		assert.ErrorIs(t, reservation(domain.StatusCompleted).CheckTransition(domain.StatusCancelled, dayBefore), domain.ErrInvalidTransition)
+	})
+
+	t.Run("CheckTransition - No-show And Expiry From Arrival", func(t *testing.T) {
+		assert.ErrorIs(t, reservation(domain.StatusConfirmed).CheckTransition(domain.StatusNoShow, dayBefore), domain.ErrTransitionGuard)
+		assert.NoError(t, reservation(domain.StatusConfirmed).CheckTransition(domain.StatusNoShow, arrivalMorning))
+
+		assert.ErrorIs(t, reservation(domain.StatusPending).CheckTransition(domain.StatusExpired, arrivalMorning), domain.ErrTransitionGuard)
+		assert.NoError(t, reservation(domain.StatusPending).CheckTransition(domain.StatusExpired, arrival))
+		assert.ErrorIs(t, reservation(domain.StatusPending).CheckTransition(domain.StatusConfirmed, dayAfter), domain.ErrTransitionGuard)
+	})
+
+	t.Run("HoldsRooms - Released States", func(t *testing.T) {
+		assert.True(t, domain.HoldsRooms(domain.StatusPending))
+		assert.True(t, domain.HoldsRooms(domain.StatusCheckedIn))
+		assert.False(t, domain.HoldsRooms(domain.StatusCancelled))
+		assert.False(t, domain.HoldsRooms(domain.StatusNoShow))
+		assert.False(t, domain.HoldsRooms(domain.StatusExpired))
+	})
+}
@@ -8,20 +8,27 @@ import (
 	"github.com/gin-gonic/gin"
 
 	queues "reservations/clients_reservations"
+	config "reservations/config_reservations"
 	controllers "reservations/controllers_reservations"
+	domain "reservations/domain_reservations"
 	repositories "reservations/repositories_reservations"
 	services "reservations/services_reservations"
 )
 
 func main() {
+	cfg := config.Load()
+
 	// Inicializar repositorio
-	repo := repositories.NewMock()
+	repo, closeRepository := newRepository(cfg)
+	defer closeRepository()
 
 	// Cargar datos semilla
-	if err := repo.SeedFromJSON("db/reservations.json"); err != nil {
-		log.Printf("Warning: Could not load seed data: %v", err)
-	} else {
-		log.Println("Seed data loaded successfully")
+	if cfg.SeedFile != "" {
+		if err := repo.SeedFromJSON(cfg.SeedFile); err != nil {
+			log.Printf("Warning: Could not load seed data: %v", err)
+		} else {
+			log.Println("Seed data loaded successfully")
+		}
 	}
 
 	// Inicializar RabbitMQ
@@ -33,10 +40,19 @@ func main() {
 		QueueName: "reservations-events",
 	})
 
+	// hotels-api (hoteles y tipos de habitación)
+	hotels := queues.NewHotels(queues.HotelsConfig{
+		Host: cfg.HotelsHost,
+		Port: cfg.HotelsPort,
+	})
+
 	// Inicializar servicio y controlador
-	svc := services.NewService(repo, events)
+	svc := services.NewService(repo, events, hotels)
 	ctrl := controllers.NewController(svc)
 
+	// Las reservas pendientes que llegan a la fecha de check-in sin confirmar se vencen
+	go expirePending(svc, cfg.ExpireInterval)
+
 	// Configurar Gin
 	r := gin.Default()
 	_ = r.SetTrustedProxies(nil)
@@ -60,19 +76,73 @@ func main() {
 	})
 
 	// Rutas NUEVAS (RESTful)
+	r.GET("/reservations/availability", ctrl.Availability)
+	r.GET("/reservations/unavailable", ctrl.Unavailable)
 	r.GET("/reservations/:id", ctrl.GetByID)
-	r.GET("/reservations", ctrl.List) // Soporta ?user_id=X
+	r.GET("/reservations", ctrl.List) // Soporta ?user_id=X o ?hotel_id=X
 	r.POST("/reservations", ctrl.Create)
 	r.PUT("/reservations/:id", ctrl.Update)
 	r.DELETE("/reservations/:id", ctrl.Delete)      // ← NUEVA
 	r.POST("/reservations/:id/cancel", ctrl.Cancel) // ← NUEVA
 
+	// Los cambios de estado del hotel: JWT de users-api, dueño del hotel o admin
+	auth := controllers.RequireAuth(cfg.JWTKey)
+	r.POST("/reservations/:id/confirm", auth, ctrl.Confirm)
+	r.POST("/reservations/:id/check-in", auth, ctrl.CheckIn)
+	r.POST("/reservations/:id/check-out", auth, ctrl.CheckOut)
+	r.POST("/reservations/:id/no-show", auth, ctrl.NoShow)
+	r.GET("/reservations/:id/transitions", ctrl.Transitions)
+
 	// Mantener compatibilidad con rutas antiguas
 	r.POST("/createReservation", ctrl.Create)
 	r.PUT("/edit/:id", ctrl.Update)
 
-	log.Println("Reservations API running on :8086")
-	if err := r.Run(":8086"); err != nil {
+	log.Printf("Reservations API running on :%s", cfg.Port)
+	if err := r.Run(":" + cfg.Port); err != nil {
 		log.Fatalf("Error starting server: %v", err)
 	}
 }
+
+func expirePending(svc *services.Service, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		if n, err := svc.ExpirePending(); err != nil {
+			log.Printf("error expiring pending reservations: %v", err)
+		} else if n > 0 {
+			log.Printf("expired %d pending reservations", n)
+		}
+		<-ticker.C
+	}
+}
+
+// newRepository arma el backend elegido en RESERVATIONS_REPOSITORY (mysql, sqlite o memory)
+func newRepository(cfg config.Config) (domain.Repository, func()) {
+	switch cfg.Repository {
+	case config.RepositoryMemory:
+		log.Println("using in-memory reservations repository (data is lost on restart)")
+		return repositories.NewMock(), func() {}
+	case config.RepositorySQLite:
+		repo, err := repositories.NewSQLite(cfg.SQLitePath)
+		if err != nil {
+			log.Fatalf("error initializing sqlite repository: %v", err)
+		}
+		return repo, func() { _ = repo.Close() }
+	case config.RepositoryMySQL:
+		repo, err := repositories.NewMySQL(repositories.MySQLConfig{
+			Host:     cfg.MySQL.Host,
+			Port:     cfg.MySQL.Port,
+			Database: cfg.MySQL.Database,
+			Username: cfg.MySQL.Username,
+			Password: cfg.MySQL.Password,
+		})
+		if err != nil {
+			log.Fatalf("error initializing mysql repository: %v", err)
+		}
+		return repo, func() { _ = repo.Close() }
+	default:
+		log.Fatalf("unknown RESERVATIONS_REPOSITORY %q (use %q, %q or %q)", cfg.Repository,
+			config.RepositoryMySQL, config.RepositorySQLite, config.RepositoryMemory)
+		return nil, nil
+	}
+}
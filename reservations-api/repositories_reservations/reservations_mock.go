@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	domain "reservations/domain_reservations"
+	"sort"
 	"sync"
 	"time"
 )
@@ -60,17 +61,23 @@ func (m *Mock) SeedFromJSON(path string) error {
 	return nil
 }
 
-func (m *Mock) Create(r domain.Reservation) (domain.Reservation, error) {
+func (m *Mock) Create(r domain.Reservation, inv domain.Inventory) (domain.Reservation, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Validar solapamiento
-	hasOverlap, err := m.checkOverlapUnsafe(r.HotelID, r.CheckIn, r.CheckOut, "")
-	if err != nil {
-		return domain.Reservation{}, err
-	}
-	if hasOverlap {
-		return domain.Reservation{}, errors.New("las fechas se solapan con una reserva existente")
+	// Validar disponibilidad
+	if inv.Units > 0 {
+		if m.roomsInUseUnsafe(r.HotelID, inv.RoomType, r.CheckIn, r.CheckOut, "") >= inv.Units {
+			return domain.Reservation{}, errors.New("no rooms of this type available for the selected dates")
+		}
+	} else {
+		hasOverlap, err := m.checkOverlapUnsafe(r.HotelID, r.CheckIn, r.CheckOut, "")
+		if err != nil {
+			return domain.Reservation{}, err
+		}
+		if hasOverlap {
+			return domain.Reservation{}, errors.New("las fechas se solapan con una reserva existente")
+		}
 	}
 
 	// Generar ID
@@ -79,7 +86,7 @@ func (m *Mock) Create(r domain.Reservation) (domain.Reservation, error) {
 
 	// Establecer valores por defecto
 	if r.Status == "" {
-		r.Status = "confirmed"
+		r.Status = domain.StatusPending
 	}
 	r.CreatedAt = time.Now()
 
@@ -111,6 +118,19 @@ func (m *Mock) GetByUserID(userID string) ([]domain.Reservation, error) {
 	return result, nil
 }
 
+func (m *Mock) GetByHotelID(hotelID string) ([]domain.Reservation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]domain.Reservation, 0)
+	for _, res := range m.data {
+		if res.HotelID == hotelID {
+			result = append(result, res)
+		}
+	}
+	return result, nil
+}
+
 func (m *Mock) List() ([]domain.Reservation, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -122,7 +142,7 @@ func (m *Mock) List() ([]domain.Reservation, error) {
 	return result, nil
 }
 
-func (m *Mock) Update(id string, r domain.Reservation) (domain.Reservation, error) {
+func (m *Mock) Update(id string, r domain.Reservation, inv domain.Inventory) (domain.Reservation, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -131,23 +151,31 @@ func (m *Mock) Update(id string, r domain.Reservation) (domain.Reservation, erro
 		return domain.Reservation{}, errors.New("reservation not found")
 	}
 
-	// No permitir modificar reservas canceladas
-	if existing.Status == "cancelled" {
-		return domain.Reservation{}, errors.New("cannot modify cancelled reservation")
+	// Solo se modifican reservas que todavía no empezaron (ni se cancelaron)
+	if !domain.Modifiable(existing.Status) {
+		return domain.Reservation{}, fmt.Errorf("cannot modify %s reservation", existing.Status)
 	}
 
-	// Validar solapamiento (excluyendo la reserva actual)
-	hasOverlap, err := m.checkOverlapUnsafe(r.HotelID, r.CheckIn, r.CheckOut, id)
-	if err != nil {
-		return domain.Reservation{}, err
-	}
-	if hasOverlap {
-		return domain.Reservation{}, errors.New("las fechas se solapan con otra reserva")
+	// Validar disponibilidad (excluyendo la reserva actual)
+	if inv.Units > 0 {
+		if m.roomsInUseUnsafe(r.HotelID, inv.RoomType, r.CheckIn, r.CheckOut, id) >= inv.Units {
+			return domain.Reservation{}, errors.New("no rooms of this type available for the selected dates")
+		}
+	} else {
+		hasOverlap, err := m.checkOverlapUnsafe(r.HotelID, r.CheckIn, r.CheckOut, id)
+		if err != nil {
+			return domain.Reservation{}, err
+		}
+		if hasOverlap {
+			return domain.Reservation{}, errors.New("las fechas se solapan con otra reserva")
+		}
 	}
 
-	// Mantener ID y CreatedAt
+	// Mantener ID, CreatedAt y el estado (solo cambia con Transition)
 	r.ID = id
 	r.CreatedAt = existing.CreatedAt
+	r.Status = existing.Status
+	r.Transitions = existing.Transitions
 
 	m.data[id] = r
 	return r, nil
@@ -165,7 +193,20 @@ func (m *Mock) Delete(id string) error {
 	return nil
 }
 
-func (m *Mock) Cancel(id string) (domain.Reservation, error) {
+func (m *Mock) GetByStatus(status string) ([]domain.Reservation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]domain.Reservation, 0)
+	for _, res := range m.data {
+		if res.Status == status {
+			result = append(result, res)
+		}
+	}
+	return result, nil
+}
+
+func (m *Mock) Transition(id string, t domain.Transition) (domain.Reservation, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -173,17 +214,12 @@ func (m *Mock) Cancel(id string) (domain.Reservation, error) {
 	if !exists {
 		return domain.Reservation{}, errors.New("reservation not found")
 	}
-
-	if res.Status == "cancelled" {
-		return domain.Reservation{}, errors.New("reservation already cancelled")
-	}
-
-	// Validar que no haya empezado
-	if res.CheckIn.Before(time.Now()) {
-		return domain.Reservation{}, errors.New("cannot cancel reservation that has already started")
+	if res.Status != t.From {
+		return domain.Reservation{}, domain.ErrStatusChanged
 	}
 
-	res.Status = "cancelled"
+	res.Status = t.To
+	res.Transitions = append(append([]domain.Transition{}, res.Transitions...), t)
 	m.data[id] = res
 	return res, nil
 }
@@ -194,11 +230,75 @@ func (m *Mock) CheckOverlap(hotelID string, checkIn, checkOut time.Time, exclude
 	return m.checkOverlapUnsafe(hotelID, checkIn, checkOut, excludeID)
 }
 
+// RoomsInUse devuelve cuántas habitaciones del tipo están ocupadas en la noche más cargada del rango
+func (m *Mock) RoomsInUse(hotelID, roomType string, checkIn, checkOut time.Time, excludeID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.roomsInUseUnsafe(hotelID, roomType, checkIn, checkOut, excludeID), nil
+}
+
+func (m *Mock) BookedHotels(checkIn, checkOut time.Time) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	hotelIDs := make([]string, 0)
+	for _, existing := range m.data {
+		if !domain.HoldsRooms(existing.Status) || seen[existing.HotelID] {
+			continue
+		}
+		if existing.CheckIn.Before(checkOut) && checkIn.Before(existing.CheckOut) {
+			seen[existing.HotelID] = true
+			hotelIDs = append(hotelIDs, existing.HotelID)
+		}
+	}
+	sort.Strings(hotelIDs)
+	return hotelIDs, nil
+}
+
+// roomsInUseUnsafe debe llamarse con el mutex ya tomado
+func (m *Mock) roomsInUseUnsafe(hotelID, roomType string, checkIn, checkOut time.Time, excludeID string) int {
+	overlapping := make([]domain.Reservation, 0)
+	for id, existing := range m.data {
+		if id == excludeID || !domain.HoldsRooms(existing.Status) ||
+			existing.HotelID != hotelID || existing.RoomType != roomType {
+			continue
+		}
+		if existing.CheckIn.Before(checkOut) && checkIn.Before(existing.CheckOut) {
+			overlapping = append(overlapping, existing)
+		}
+	}
+
+	return maxNightlyUse(overlapping, checkIn, checkOut)
+}
+
+// maxNightlyUse cuenta noche por noche: dos reservas que no se pisan entre sí usan la misma
+// habitación. overlapping son las reservas activas que se cruzan con el rango.
+func maxNightlyUse(overlapping []domain.Reservation, checkIn, checkOut time.Time) int {
+	maxInUse := 0
+	for night := dateOf(checkIn); night.Before(dateOf(checkOut)); night = night.AddDate(0, 0, 1) {
+		inUse := 0
+		for _, existing := range overlapping {
+			if !night.Before(dateOf(existing.CheckIn)) && night.Before(dateOf(existing.CheckOut)) {
+				inUse++
+			}
+		}
+		maxInUse = max(maxInUse, inUse)
+	}
+	return maxInUse
+}
+
+// dateOf: la noche de una fecha (se ignora la hora de check-in/check-out)
+func dateOf(t time.Time) time.Time {
+	y, mo, d := t.UTC().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+}
+
 // checkOverlapUnsafe debe llamarse con el mutex ya tomado
 func (m *Mock) checkOverlapUnsafe(hotelID string, checkIn, checkOut time.Time, excludeID string) (bool, error) {
 	for id, existing := range m.data {
-		// Excluir la reserva actual (para updates) y reservas canceladas
-		if id == excludeID || existing.Status == "cancelled" {
+		// Excluir la reserva actual (para updates) y las que ya no ocupan habitaciones
+		if id == excludeID || !domain.HoldsRooms(existing.Status) {
 			continue
 		}
 
@@ -0,0 +1,66 @@
+//go:build mysql
+
+package repositories_reservations_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	repositories "reservations/repositories_reservations"
+)
+
+// Corre contra un MySQL de verdad (el de docker-compose expone el 3307):
+//
+//	docker compose up -d mysql
+//	go test -tags mysql ./repositories_reservations/
+//
+// Cada corrida usa una base nueva y la borra al terminar.
+func newMySQL(t *testing.T) *repositories.SQL {
+	config := repositories.MySQLConfig{
+		Host:     getEnv("MYSQL_TEST_HOST", "127.0.0.1"),
+		Port:     getEnv("MYSQL_TEST_PORT", "3307"),
+		Database: fmt.Sprintf("reservations_test_%d", time.Now().UnixNano()),
+		Username: getEnv("MYSQL_TEST_USERNAME", "root"),
+		Password: getEnv("MYSQL_TEST_PASSWORD", "RaTa8855"),
+	}
+	repo, err := repositories.NewMySQL(config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = repo.Close()
+		server := fmt.Sprintf("%s:%s@tcp(%s:%s)/", config.Username, config.Password, config.Host, config.Port)
+		if db, err := gorm.Open(mysql.Open(server), &gorm.Config{}); err == nil {
+			_ = db.Exec("DROP DATABASE IF EXISTS `" + config.Database + "`").Error
+			if sqlDB, err := db.DB(); err == nil {
+				_ = sqlDB.Close()
+			}
+		}
+	})
+	return repo
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func TestMySQL(t *testing.T) {
+	checkIn := time.Now().UTC().Truncate(time.Second).AddDate(0, 1, 0)
+
+	// Con el pool de MySQL cada reserva va por su propia conexión: solo el bloqueo del hotel
+	// (reservation_hotel_locks) impide que dos transacciones vean la suite libre a la vez
+	t.Run("Create - Concurrent Bookings For The Last Room Are Locked", func(t *testing.T) {
+		repo := newMySQL(t)
+
+		assert.Equal(t, 1, concurrentBookings(t, repo, checkIn))
+	})
+}
@@ -0,0 +1,439 @@
+package repositories_reservations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	dao "reservations/dao_reservations"
+	domain "reservations/domain_reservations"
+)
+
+// Mismos mensajes que el Mock: el controller los compara por texto
+var (
+	errNotFound         = errors.New("reservation not found")
+	errNoRoomsAvailable = errors.New("no rooms of this type available for the selected dates")
+)
+
+type MySQLConfig struct {
+	Host     string
+	Port     string
+	Database string
+	Username string
+	Password string
+}
+
+// SQL guarda las reservas con GORM (MySQL en docker-compose, SQLite para correr local o en tests).
+// La disponibilidad se valida en la misma transacción que escribe, con el hotel bloqueado.
+type SQL struct {
+	db *gorm.DB
+}
+
+// NewMySQL crea la base si todavía no existe (el contenedor de mysql solo crea la de users-api)
+// y migra las tablas
+func NewMySQL(config MySQLConfig) (*SQL, error) {
+	if strings.ContainsRune(config.Database, '`') {
+		return nil, fmt.Errorf("invalid database name %q", config.Database)
+	}
+	server := fmt.Sprintf("%s:%s@tcp(%s:%s)/", config.Username, config.Password, config.Host, config.Port)
+	params := "?charset=utf8mb4&parseTime=True&loc=UTC"
+
+	admin, err := gorm.Open(mysql.Open(server+params), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL: %w", err)
+	}
+	err = admin.Exec("CREATE DATABASE IF NOT EXISTS `" + config.Database + "`").Error
+	if sqlDB, dbErr := admin.DB(); dbErr == nil {
+		_ = sqlDB.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating database %s: %w", config.Database, err)
+	}
+
+	db, err := gorm.Open(mysql.Open(server+config.Database+params), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL: %w", err)
+	}
+	return newSQL(db)
+}
+
+// NewSQLite abre (o crea) el archivo de la base. Con ":memory:" queda una base vacía en memoria.
+func NewSQLite(path string) (*SQL, error) {
+	if path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("error creating SQLite directory: %w", err)
+		}
+	}
+	db, err := gorm.Open(sqlite.Open(path+"?_pragma=busy_timeout(5000)"), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite: %w", err)
+	}
+	// SQLite admite un solo escritor: con una conexión las transacciones se ordenan solas
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return newSQL(db)
+}
+
+func newSQL(db *gorm.DB) (*SQL, error) {
+	// AutoMigrate para mantener el esquema sincronizado. Alcanza porque solo crea tablas y
+	// agrega columnas e índices (nunca borra ni renombra) y hasta ahora todos los cambios del
+	// esquema fueron aditivos. Renombrar una columna o cambiarle el tipo necesita una migración
+	// manual antes de desplegar.
+	if err := db.AutoMigrate(&dao.Reservation{}, &dao.HotelLock{}); err != nil {
+		return nil, fmt.Errorf("error running AutoMigrate: %w", err)
+	}
+	return &SQL{db: db}, nil
+}
+
+func (s *SQL) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// SeedFromJSON carga las reservas de ejemplo solo si la tabla está vacía (no pisa datos reales)
+func (s *SQL) SeedFromJSON(path string) error {
+	var count int64
+	if err := s.db.Model(&dao.Reservation{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("error counting reservations: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		// Si el archivo no existe, no es error crítico
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading seed file: %w", err)
+	}
+	var seedData struct {
+		Reservations []domain.Reservation `json:"reservations"`
+	}
+	if err := json.Unmarshal(file, &seedData); err != nil {
+		return fmt.Errorf("error parsing seed JSON: %w", err)
+	}
+
+	rows := make([]dao.Reservation, 0, len(seedData.Reservations))
+	for _, res := range seedData.Reservations {
+		row := toRow(res)
+		row.ID, _ = parseID(res.ID)
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := s.db.Create(&rows).Error; err != nil {
+		return fmt.Errorf("error seeding reservations: %w", err)
+	}
+	return nil
+}
+
+func (s *SQL) Create(r domain.Reservation, inv domain.Inventory) (domain.Reservation, error) {
+	// Establecer valores por defecto
+	if r.Status == "" {
+		r.Status = domain.StatusPending
+	}
+	r.CreatedAt = time.Now()
+	row := toRow(r)
+	row.ID = 0
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockHotel(tx, r.HotelID); err != nil {
+			return err
+		}
+		if err := checkAvailability(tx, r, inv, "", "las fechas se solapan con una reserva existente"); err != nil {
+			return err
+		}
+		if err := tx.Create(&row).Error; err != nil {
+			return fmt.Errorf("error creating reservation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (s *SQL) GetByID(id string) (domain.Reservation, error) {
+	row, err := findRow(s.db, id)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (s *SQL) GetByUserID(userID string) ([]domain.Reservation, error) {
+	return s.find("user_id = ?", userID)
+}
+
+func (s *SQL) GetByHotelID(hotelID string) ([]domain.Reservation, error) {
+	return s.find("hotel_id = ?", hotelID)
+}
+
+func (s *SQL) List() ([]domain.Reservation, error) {
+	return s.find("1 = 1")
+}
+
+func (s *SQL) find(query string, args ...any) ([]domain.Reservation, error) {
+	var rows []dao.Reservation
+	if err := s.db.Where(query, args...).Order("id").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error fetching reservations: %w", err)
+	}
+	result := make([]domain.Reservation, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toDomain(row))
+	}
+	return result, nil
+}
+
+func (s *SQL) Update(id string, r domain.Reservation, inv domain.Inventory) (domain.Reservation, error) {
+	var row dao.Reservation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockHotel(tx, r.HotelID); err != nil {
+			return err
+		}
+		existing, err := findRow(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Solo se modifican reservas que todavía no empezaron (ni se cancelaron)
+		if !domain.Modifiable(existing.Status) {
+			return fmt.Errorf("cannot modify %s reservation", existing.Status)
+		}
+
+		// Validar disponibilidad (excluyendo la reserva actual)
+		if err := checkAvailability(tx, r, inv, id, "las fechas se solapan con otra reserva"); err != nil {
+			return err
+		}
+
+		// Mantener ID, CreatedAt y el estado (solo cambia con Transition)
+		row = toRow(r)
+		row.ID = existing.ID
+		row.CreatedAt = existing.CreatedAt
+		row.Status = existing.Status
+		row.Transitions = existing.Transitions
+		if err := tx.Save(&row).Error; err != nil {
+			return fmt.Errorf("error updating reservation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (s *SQL) Delete(id string) error {
+	numID, ok := parseID(id)
+	if !ok {
+		return errNotFound
+	}
+	res := s.db.Delete(&dao.Reservation{}, numID)
+	if res.Error != nil {
+		return fmt.Errorf("error deleting reservation: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+func (s *SQL) GetByStatus(status string) ([]domain.Reservation, error) {
+	return s.find("status = ?", status)
+}
+
+// Transition bloquea la fila y solo escribe si el estado sigue siendo t.From
+func (s *SQL) Transition(id string, t domain.Transition) (domain.Reservation, error) {
+	var row dao.Reservation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		row, err = findRow(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+		if err != nil {
+			return err
+		}
+		if row.Status != t.From {
+			return domain.ErrStatusChanged
+		}
+
+		row.Status = t.To
+		row.Transitions = append(row.Transitions, t)
+		if err := tx.Model(&row).Select("Status", "Transitions").Updates(&row).Error; err != nil {
+			return fmt.Errorf("error updating reservation status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (s *SQL) CheckOverlap(hotelID string, checkIn, checkOut time.Time, excludeID string) (bool, error) {
+	return checkOverlap(s.db, hotelID, checkIn, checkOut, excludeID)
+}
+
+// RoomsInUse devuelve cuántas habitaciones del tipo están ocupadas en la noche más cargada del rango
+func (s *SQL) RoomsInUse(hotelID, roomType string, checkIn, checkOut time.Time, excludeID string) (int, error) {
+	return roomsInUse(s.db, hotelID, roomType, checkIn, checkOut, excludeID)
+}
+
+func (s *SQL) BookedHotels(checkIn, checkOut time.Time) ([]string, error) {
+	var hotelIDs []string
+	err := s.db.Model(&dao.Reservation{}).
+		Distinct("hotel_id").
+		Where("status NOT IN ? AND check_in < ? AND check_out > ?", domain.ReleasedStatuses, checkOut.UTC(), checkIn.UTC()).
+		Order("hotel_id").
+		Pluck("hotel_id", &hotelIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing booked hotels: %w", err)
+	}
+	return hotelIDs, nil
+}
+
+// lockHotel escribe la fila del hotel en reservation_hotel_locks: queda bloqueada hasta el
+// commit, así que la siguiente reserva del mismo hotel espera a que esta termine
+func lockHotel(tx *gorm.DB, hotelID string) error {
+	lock := dao.HotelLock{HotelID: hotelID, LockedAt: time.Now().UTC()}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hotel_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"locked_at"}),
+	}).Create(&lock).Error
+	if err != nil {
+		return fmt.Errorf("error locking hotel %s: %w", hotelID, err)
+	}
+	return nil
+}
+
+// checkAvailability aplica las mismas reglas que el Mock: por unidades si el tipo de habitación
+// tiene inventario, si no cualquier solapamiento bloquea el hotel
+func checkAvailability(tx *gorm.DB, r domain.Reservation, inv domain.Inventory, excludeID string, overlapMessage string) error {
+	if inv.Units > 0 {
+		inUse, err := roomsInUse(tx, r.HotelID, inv.RoomType, r.CheckIn, r.CheckOut, excludeID)
+		if err != nil {
+			return err
+		}
+		if inUse >= inv.Units {
+			return errNoRoomsAvailable
+		}
+		return nil
+	}
+	hasOverlap, err := checkOverlap(tx, r.HotelID, r.CheckIn, r.CheckOut, excludeID)
+	if err != nil {
+		return err
+	}
+	if hasOverlap {
+		return errors.New(overlapMessage)
+	}
+	return nil
+}
+
+func checkOverlap(db *gorm.DB, hotelID string, checkIn, checkOut time.Time, excludeID string) (bool, error) {
+	var count int64
+	if err := overlapping(db, hotelID, checkIn, checkOut, excludeID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("error checking overlap: %w", err)
+	}
+	return count > 0, nil
+}
+
+func roomsInUse(db *gorm.DB, hotelID, roomType string, checkIn, checkOut time.Time, excludeID string) (int, error) {
+	var rows []dao.Reservation
+	err := overlapping(db, hotelID, checkIn, checkOut, excludeID).Where("room_type = ?", roomType).Find(&rows).Error
+	if err != nil {
+		return 0, fmt.Errorf("error checking rooms in use: %w", err)
+	}
+	reservations := make([]domain.Reservation, 0, len(rows))
+	for _, row := range rows {
+		reservations = append(reservations, toDomain(row))
+	}
+	return maxNightlyUse(reservations, checkIn, checkOut), nil
+}
+
+// overlapping: reservas activas del hotel que se cruzan con [checkIn, checkOut)
+func overlapping(db *gorm.DB, hotelID string, checkIn, checkOut time.Time, excludeID string) *gorm.DB {
+	q := db.Model(&dao.Reservation{}).
+		Where("hotel_id = ? AND status NOT IN ? AND check_in < ? AND check_out > ?", hotelID, domain.ReleasedStatuses, checkOut.UTC(), checkIn.UTC())
+	if numID, ok := parseID(excludeID); ok {
+		q = q.Where("id <> ?", numID)
+	}
+	return q
+}
+
+func findRow(db *gorm.DB, id string) (dao.Reservation, error) {
+	var row dao.Reservation
+	numID, ok := parseID(id)
+	if !ok {
+		return row, errNotFound
+	}
+	res := db.Where("id = ?", numID).Limit(1).Find(&row)
+	if res.Error != nil {
+		return row, fmt.Errorf("error fetching reservation: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return row, errNotFound
+	}
+	return row, nil
+}
+
+func parseID(id string) (uint64, bool) {
+	n, err := strconv.ParseUint(strings.TrimSpace(id), 10, 64)
+	return n, err == nil && n > 0
+}
+
+func toRow(r domain.Reservation) dao.Reservation {
+	id, _ := parseID(r.ID)
+	return dao.Reservation{
+		ID:         id,
+		HotelID:    r.HotelID,
+		UserID:     r.UserID,
+		CheckIn:    r.CheckIn.UTC(),
+		CheckOut:   r.CheckOut.UTC(),
+		Guests:     r.Guests,
+		RoomType:   r.RoomType,
+		TotalPrice: r.TotalPrice,
+		Status:     r.Status,
+		CreatedAt:  r.CreatedAt.UTC(),
+
+		Currency:       r.Currency,
+		PriceBreakdown: r.PriceBreakdown,
+		Transitions:    r.Transitions,
+	}
+}
+
+func toDomain(row dao.Reservation) domain.Reservation {
+	return domain.Reservation{
+		ID:         strconv.FormatUint(row.ID, 10),
+		HotelID:    row.HotelID,
+		UserID:     row.UserID,
+		CheckIn:    row.CheckIn.UTC(),
+		CheckOut:   row.CheckOut.UTC(),
+		Guests:     row.Guests,
+		RoomType:   row.RoomType,
+		TotalPrice: row.TotalPrice,
+		Status:     row.Status,
+		CreatedAt:  row.CreatedAt.UTC(),
+
+		Currency:       row.Currency,
+		PriceBreakdown: row.PriceBreakdown,
+		Transitions:    row.Transitions,
+	}
+}
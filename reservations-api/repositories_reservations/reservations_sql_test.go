@@ -0,0 +1,144 @@
+package repositories_reservations_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "reservations/domain_reservations"
+	repositories "reservations/repositories_reservations"
+)
+
+func newSQLite(t *testing.T) *repositories.SQL {
+	repo, err := repositories.NewSQLite(filepath.Join(t.TempDir(), "reservations.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+	return repo
+}
+
+func reservation(roomType string, checkIn time.Time, nights int) domain.Reservation {
+	return domain.Reservation{
+		HotelID:  "h1",
+		UserID:   "1",
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, nights),
+		Guests:   2,
+		RoomType: roomType,
+		Currency: "ARS",
+		PriceBreakdown: &domain.PriceBreakdown{
+			Nightly:  []domain.NightPrice{{Date: checkIn.Format(time.DateOnly), Base: 100, Price: 100}},
+			Subtotal: 100,
+		},
+	}
+}
+
+func TestSQL(t *testing.T) {
+	checkIn := time.Now().UTC().Truncate(time.Second).AddDate(0, 1, 0)
+	doble := domain.Inventory{RoomType: "doble", Units: 2}
+
+	t.Run("Create - Rooms Of Type Run Out", func(t *testing.T) {
+		repo := newSQLite(t)
+
+		first, err := repo.Create(reservation("doble", checkIn, 3), doble)
+		require.NoError(t, err)
+		assert.Equal(t, "1", first.ID)
+		assert.Equal(t, domain.StatusPending, first.Status)
+		stored, err := repo.GetByID(first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "ARS", stored.Currency)
+		assert.Equal(t, first.PriceBreakdown, stored.PriceBreakdown)
+		_, err = repo.Create(reservation("doble", checkIn.AddDate(0, 0, 1), 3), doble)
+		require.NoError(t, err)
+
+		_, err = repo.Create(reservation("doble", checkIn.AddDate(0, 0, 2), 1), doble)
+		assert.EqualError(t, err, "no rooms of this type available for the selected dates")
+
+		inUse, err := repo.RoomsInUse("h1", "doble", checkIn, checkIn.AddDate(0, 0, 5), "")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inUse)
+
+		booked, err := repo.BookedHotels(checkIn.AddDate(0, 0, 2), checkIn.AddDate(0, 0, 3))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"h1"}, booked)
+		booked, err = repo.BookedHotels(checkIn.AddDate(0, 0, 4), checkIn.AddDate(0, 0, 6))
+		assert.NoError(t, err)
+		assert.Empty(t, booked)
+	})
+
+	t.Run("Update And Transition - Free The Dates", func(t *testing.T) {
+		repo := newSQLite(t)
+		created, err := repo.Create(reservation("", checkIn, 2), domain.Inventory{})
+		require.NoError(t, err)
+
+		_, err = repo.Create(reservation("", checkIn.AddDate(0, 0, 1), 2), domain.Inventory{})
+		assert.EqualError(t, err, "las fechas se solapan con una reserva existente")
+
+		moved, err := repo.Update(created.ID, reservation("", checkIn.AddDate(0, 0, 10), 2), domain.Inventory{})
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, moved.ID)
+		assert.Equal(t, created.CreatedAt.Unix(), moved.CreatedAt.Unix())
+
+		_, err = repo.Transition(created.ID, domain.Transition{From: domain.StatusConfirmed, To: domain.StatusCancelled})
+		assert.ErrorIs(t, err, domain.ErrStatusChanged)
+		cancelled, err := repo.Transition(created.ID, domain.Transition{From: domain.StatusPending, To: domain.StatusCancelled})
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusCancelled, cancelled.Status)
+		assert.Len(t, cancelled.Transitions, 1)
+		_, err = repo.Update(created.ID, reservation("", checkIn, 2), domain.Inventory{})
+		assert.EqualError(t, err, "cannot modify cancelled reservation")
+
+		overlap, err := repo.CheckOverlap("h1", checkIn.AddDate(0, 0, 10), checkIn.AddDate(0, 0, 12), "")
+		assert.NoError(t, err)
+		assert.False(t, overlap)
+
+		assert.NoError(t, repo.Delete(created.ID))
+		_, err = repo.GetByID(created.ID)
+		assert.EqualError(t, err, "reservation not found")
+	})
+
+	// SQLite usa una sola conexión, así que las transacciones ya llegan en fila: esto verifica que
+	// se respeta el inventario con pedidos simultáneos, no el bloqueo del hotel. El bloqueo con
+	// varias conexiones lo prueba reservations_mysql_test.go (go test -tags mysql).
+	t.Run("Create - Concurrent Bookings For The Last Room", func(t *testing.T) {
+		repo := newSQLite(t)
+
+		assert.Equal(t, 1, concurrentBookings(t, repo, checkIn))
+	})
+}
+
+// concurrentBookings lanza 10 reservas a la vez por la única suite del hotel y devuelve cuántas
+// se crearon; también verifica que quedó guardada una sola
+func concurrentBookings(t *testing.T, repo domain.Repository, checkIn time.Time) int {
+	single := domain.Inventory{RoomType: "suite", Units: 1}
+
+	var wg sync.WaitGroup
+	results := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Create(reservation("suite", checkIn, 2), single)
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.EqualError(t, err, "no rooms of this type available for the selected dates")
+		}
+	}
+
+	list, err := repo.GetByHotelID("h1")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	return succeeded
+}
@@ -0,0 +1,12 @@
+package services_reservations
+
+import (
+	"context"
+
+	domain "reservations/domain_reservations"
+)
+
+// PriceFor expone priceFor a los tests: Create y Update además validan el usuario contra users-api
+func (s *Service) PriceFor(ctx context.Context, r *domain.Reservation) error {
+	return s.priceFor(ctx, r)
+}
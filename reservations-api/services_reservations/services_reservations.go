@@ -1,11 +1,14 @@
 package services_reservations
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"io"
+	"math"
 	"net/http"
 	domain "reservations/domain_reservations"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,19 +16,36 @@ type EventQueue interface {
 	Publish(event string) error
 }
 
+// HotelsClient trae de hotels-api el hotel con sus tipos de habitación y la cotización de una estadía
+type HotelsClient interface {
+	GetHotel(ctx context.Context, hotelID string) (domain.Hotel, error)
+	Quote(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time, guests int) (domain.Quote, error)
+}
+
+// Cuántos hoteles se consultan a la vez en hotels-api al calcular disponibilidad
+const availabilityWorkers = 8
+
+// Diferencia que se tolera entre el total del cliente y el cotizado (redondeo a centavos)
+const priceTolerance = 0.01
+
+// ErrPriceMismatch: el cliente mandó un total distinto al que cotiza hotels-api
+var ErrPriceMismatch = errors.New("total_price does not match the quoted price")
+
 type Service struct {
 	repo   domain.Repository
 	events EventQueue
+	hotels HotelsClient
 }
 
-func NewService(repo domain.Repository, events EventQueue) *Service {
+func NewService(repo domain.Repository, events EventQueue, hotels HotelsClient) *Service {
 	return &Service{
 		repo:   repo,
 		events: events,
+		hotels: hotels,
 	}
 }
 
-func (s *Service) Create(r domain.Reservation) (domain.Reservation, error) {
+func (s *Service) Create(ctx context.Context, r domain.Reservation) (domain.Reservation, error) {
 	// Validaciones
 	if err := s.validateReservation(r); err != nil {
 		return domain.Reservation{}, err
@@ -36,13 +56,21 @@ func (s *Service) Create(r domain.Reservation) (domain.Reservation, error) {
 		return domain.Reservation{}, fmt.Errorf("invalid user: %w", err)
 	}
 
-	// Validar que el hotel existe
-	if err := s.validateHotelExists(r.HotelID); err != nil {
-		return domain.Reservation{}, fmt.Errorf("invalid hotel: %w", err)
+	// Validar que el hotel existe y resolver el tipo de habitación
+	inv, err := s.inventoryFor(ctx, &r)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	if err := s.priceFor(ctx, &r); err != nil {
+		return domain.Reservation{}, err
 	}
 
+	// Toda reserva nueva arranca pendiente de confirmación
+	r.Status = domain.StatusPending
+	r.Transitions = []domain.Transition{{To: domain.StatusPending, At: time.Now().UTC()}}
+
 	// Crear
-	created, err := s.repo.Create(r)
+	created, err := s.repo.Create(r, inv)
 	if err != nil {
 		return domain.Reservation{}, err
 	}
@@ -114,25 +142,68 @@ func (s *Service) validateUserExists(userID string) error {
 	return nil
 }
 
-func (s *Service) validateHotelExists(hotelID string) error {
-	url := fmt.Sprintf("http://hotels-api:8082/hotels/%s", hotelID)
-	resp, err := http.Get(url)
+// inventoryFor valida el hotel y el tipo de habitación pedido. Deja en r.RoomType el id del tipo
+// (se acepta también el nombre) y devuelve cuántas unidades tiene. Si el hotel no tiene
+// habitaciones cargadas la reserva bloquea el hotel entero, como antes.
+func (s *Service) inventoryFor(ctx context.Context, r *domain.Reservation) (domain.Inventory, error) {
+	hotel, err := s.hotels.GetHotel(ctx, r.HotelID)
 	if err != nil {
-		return fmt.Errorf("error contacting hotels API: %w", err)
+		return domain.Inventory{}, fmt.Errorf("invalid hotel: %w", err)
+	}
+	if hotel.Archived {
+		return domain.Inventory{}, errors.New("invalid hotel: hotel not available")
+	}
+	if len(hotel.Rooms) == 0 {
+		return domain.Inventory{}, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("hotel not found")
+	if strings.TrimSpace(r.RoomType) == "" {
+		return domain.Inventory{}, errors.New("room_type is required")
 	}
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("hotels API returned status %d: %s", resp.StatusCode, string(body))
+	room, ok := findRoom(hotel.Rooms, r.RoomType)
+	if !ok {
+		return domain.Inventory{}, errors.New("room type not found")
+	}
+	if r.Guests > room.Capacity {
+		return domain.Inventory{}, fmt.Errorf("room type %s allows up to %d guests", room.Name, room.Capacity)
 	}
 
+	r.RoomType = room.ID
+	return domain.Inventory{RoomType: room.ID, Units: room.Units}, nil
+}
+
+// priceFor cotiza la estadía en hotels-api y deja en r el total, la moneda y el detalle. Si el
+// cliente mandó un total tiene que coincidir con el cotizado; si no lo mandó se usa el cotizado.
+func (s *Service) priceFor(ctx context.Context, r *domain.Reservation) error {
+	quote, err := s.hotels.Quote(ctx, r.HotelID, r.RoomType, r.CheckIn, r.CheckOut, r.Guests)
+	if err != nil {
+		return err
+	}
+	if r.TotalPrice != 0 && math.Abs(r.TotalPrice-quote.Total) > priceTolerance {
+		return ErrPriceMismatch
+	}
+
+	r.TotalPrice = quote.Total
+	r.Currency = quote.Currency
+	r.PriceBreakdown = &domain.PriceBreakdown{
+		Nightly:     quote.Nightly,
+		Subtotal:    quote.Subtotal,
+		DiscountPct: quote.DiscountPct,
+		Discount:    quote.Discount,
+	}
 	return nil
 }
 
+func findRoom(rooms []domain.RoomType, idOrName string) (domain.RoomType, bool) {
+	idOrName = strings.TrimSpace(idOrName)
+	for _, room := range rooms {
+		if room.ID == idOrName || strings.EqualFold(room.Name, idOrName) {
+			return room, true
+		}
+	}
+	return domain.RoomType{}, false
+}
+
 func (s *Service) GetByID(id string) (domain.Reservation, error) {
 	if id == "" {
 		return domain.Reservation{}, errors.New("reservation ID is required")
@@ -147,11 +218,18 @@ func (s *Service) GetByUserID(userID string) ([]domain.Reservation, error) {
 	return s.repo.GetByUserID(userID)
 }
 
+func (s *Service) GetByHotelID(hotelID string) ([]domain.Reservation, error) {
+	if hotelID == "" {
+		return nil, errors.New("hotel ID is required")
+	}
+	return s.repo.GetByHotelID(hotelID)
+}
+
 func (s *Service) List() ([]domain.Reservation, error) {
 	return s.repo.List()
 }
 
-func (s *Service) Update(id string, r domain.Reservation) (domain.Reservation, error) {
+func (s *Service) Update(ctx context.Context, id string, r domain.Reservation) (domain.Reservation, error) {
 	if id == "" {
 		return domain.Reservation{}, errors.New("reservation ID is required")
 	}
@@ -161,8 +239,16 @@ func (s *Service) Update(id string, r domain.Reservation) (domain.Reservation, e
 		return domain.Reservation{}, err
 	}
 
+	inv, err := s.inventoryFor(ctx, &r)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	if err := s.priceFor(ctx, &r); err != nil {
+		return domain.Reservation{}, err
+	}
+
 	// Actualizar
-	updated, err := s.repo.Update(id, r)
+	updated, err := s.repo.Update(id, r, inv)
 	if err != nil {
 		return domain.Reservation{}, err
 	}
@@ -188,18 +274,211 @@ func (s *Service) Delete(id string) error {
 	return nil
 }
 
+// Availability indica, para cada hotel, si le queda lugar en las fechas: algún tipo de habitación
+// con capacidad para los huéspedes (0 = cualquiera) y unidades libres. Los hoteles sin habitaciones
+// cargadas están libres si no tienen reservas que se solapen; los que hotels-api no conoce o están
+// archivados no tienen lugar. Los hoteles se consultan en paralelo y dentro del deadline de ctx:
+// si hotels-api falla para alguno se devuelve el error en lugar de adivinar.
+func (s *Service) Availability(ctx context.Context, hotelIDs []string, checkIn, checkOut time.Time, guests int) (map[string]bool, error) {
+	if len(hotelIDs) == 0 {
+		return nil, errors.New("at least one hotel_id is required")
+	}
+	if !checkIn.Before(checkOut) {
+		return nil, errors.New("check-in must be before check-out")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		firstErr     error
+		workers      = make(chan struct{}, availabilityWorkers)
+		availability = make(map[string]bool, len(hotelIDs))
+	)
+	for _, hotelID := range hotelIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			available, err := s.hotelAvailable(ctx, hotelID, checkIn, checkOut, guests)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("error checking availability for hotel %s: %w", hotelID, err)
+					cancel() // el resultado ya no sirve: se cortan las consultas pendientes
+				}
+				return
+			}
+			availability[hotelID] = available
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return availability, nil
+}
+
+// Unavailable devuelve, ordenados, los hoteles que no tienen lugar en las fechas. Solo puede estar
+// completo un hotel con alguna reserva que se cruce con el rango, así que se revisan esos y no todo
+// el catálogo. Un hotel sin reservas cuyas habitaciones son chicas para los huéspedes no figura:
+// search-api lo descarta por capacidad.
+func (s *Service) Unavailable(ctx context.Context, checkIn, checkOut time.Time, guests int) ([]string, error) {
+	if !checkIn.Before(checkOut) {
+		return nil, errors.New("check-in must be before check-out")
+	}
+	booked, err := s.repo.BookedHotels(checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	if len(booked) == 0 {
+		return []string{}, nil
+	}
+
+	availability, err := s.Availability(ctx, booked, checkIn, checkOut, guests)
+	if err != nil {
+		return nil, err
+	}
+	unavailable := make([]string, 0)
+	for _, hotelID := range booked {
+		if !availability[hotelID] {
+			unavailable = append(unavailable, hotelID)
+		}
+	}
+	return unavailable, nil
+}
+
+func (s *Service) hotelAvailable(ctx context.Context, hotelID string, checkIn, checkOut time.Time, guests int) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	hotel, err := s.hotels.GetHotel(ctx, hotelID)
+	if errors.Is(err, domain.ErrHotelNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if hotel.Archived {
+		return false, nil
+	}
+	if len(hotel.Rooms) == 0 {
+		overlap, err := s.repo.CheckOverlap(hotelID, checkIn, checkOut, "")
+		return !overlap, err
+	}
+
+	for _, room := range hotel.Rooms {
+		if room.Capacity < guests {
+			continue
+		}
+		inUse, err := s.repo.RoomsInUse(hotelID, room.ID, checkIn, checkOut, "")
+		if err != nil {
+			return false, err
+		}
+		if inUse < room.Units {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (s *Service) Cancel(id string) (domain.Reservation, error) {
+	return s.Transition(id, domain.StatusCancelled, "")
+}
+
+// Transition cambia el estado de la reserva si la máquina de estados y las fechas lo permiten,
+// lo deja en el historial y publica "reservation.<estado>:<id>"
+func (s *Service) Transition(id string, to string, reason string) (domain.Reservation, error) {
 	if id == "" {
 		return domain.Reservation{}, errors.New("reservation ID is required")
 	}
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return s.transition(r, to, reason)
+}
+
+// HotelTransition es Transition para los cambios que hace el hotel (confirmar, check-in,
+// check-out, no-show): el usuario del contexto tiene que ser admin o el dueño del hotel según
+// hotels-api
+func (s *Service) HotelTransition(ctx context.Context, id string, to string, reason string) (domain.Reservation, error) {
+	actor, ok := domain.ActorFrom(ctx)
+	if !ok {
+		return domain.Reservation{}, domain.ErrUnauthorized
+	}
+	if id == "" {
+		return domain.Reservation{}, errors.New("reservation ID is required")
+	}
+	r, err := s.repo.GetByID(id)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	if !actor.Admin {
+		hotel, err := s.hotels.GetHotel(ctx, r.HotelID)
+		if errors.Is(err, domain.ErrHotelNotFound) {
+			return domain.Reservation{}, domain.ErrForbidden
+		}
+		if err != nil {
+			return domain.Reservation{}, fmt.Errorf("error checking hotel owner: %w", err)
+		}
+		if !actor.CanOperate(hotel) {
+			return domain.Reservation{}, domain.ErrForbidden
+		}
+	}
+	return s.transition(r, to, reason)
+}
+
+func (s *Service) transition(r domain.Reservation, to string, reason string) (domain.Reservation, error) {
+	id := r.ID
+	now := time.Now().UTC()
+	if err := r.CheckTransition(to, now); err != nil {
+		return domain.Reservation{}, err
+	}
 
-	cancelled, err := s.repo.Cancel(id)
+	updated, err := s.repo.Transition(id, domain.Transition{
+		From:   r.Status,
+		To:     to,
+		At:     now,
+		Reason: strings.TrimSpace(reason),
+	})
 	if err != nil {
 		return domain.Reservation{}, err
 	}
 
 	// Publicar evento
-	_ = s.events.Publish(fmt.Sprintf("reservation.cancelled:%s", id))
+	_ = s.events.Publish(fmt.Sprintf("reservation.%s:%s", to, id))
 
-	return cancelled, nil
+	return updated, nil
+}
+
+// ExpirePending vence las reservas pendientes a las que les llegó la hora de check-in sin
+// confirmarse; devuelve cuántas venció
+func (s *Service) ExpirePending() (int, error) {
+	pending, err := s.repo.GetByStatus(domain.StatusPending)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, r := range pending {
+		if r.CheckTransition(domain.StatusExpired, time.Now().UTC()) != nil {
+			continue
+		}
+		_, err := s.Transition(r.ID, domain.StatusExpired, "not confirmed before check-in")
+		if errors.Is(err, domain.ErrStatusChanged) || errors.Is(err, domain.ErrInvalidTransition) {
+			continue // alguien la confirmó o canceló mientras tanto
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
 }
@@ -0,0 +1,250 @@
+package services_reservations_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clients "reservations/clients_reservations"
+	domain "reservations/domain_reservations"
+	repositories "reservations/repositories_reservations"
+	services "reservations/services_reservations"
+)
+
+// fakeHotels responde con los hoteles cargados; errs simula fallas de hotels-api por hotel.
+// Quote devuelve siempre quote.
+type fakeHotels struct {
+	hotels map[string]domain.Hotel
+	errs   map[string]error
+	delay  time.Duration
+	quote  domain.Quote
+}
+
+func (f *fakeHotels) GetHotel(ctx context.Context, hotelID string) (domain.Hotel, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return domain.Hotel{}, ctx.Err()
+	}
+	if err := f.errs[hotelID]; err != nil {
+		return domain.Hotel{}, err
+	}
+	hotel, ok := f.hotels[hotelID]
+	if !ok {
+		return domain.Hotel{}, domain.ErrHotelNotFound
+	}
+	return hotel, nil
+}
+
+func (f *fakeHotels) Quote(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time, guests int) (domain.Quote, error) {
+	if err := f.errs[hotelID]; err != nil {
+		return domain.Quote{}, err
+	}
+	return f.quote, nil
+}
+
+func TestAvailability(t *testing.T) {
+	checkIn := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+
+	newService := func(hotels *fakeHotels) *services.Service {
+		repo := repositories.NewMock()
+		_, err := repo.Create(domain.Reservation{HotelID: "full", RoomType: "rt_1", CheckIn: checkIn, CheckOut: checkOut, Guests: 2},
+			domain.Inventory{RoomType: "rt_1", Units: 1})
+		require.NoError(t, err)
+		_, err = repo.Create(domain.Reservation{HotelID: "no-rooms", CheckIn: checkIn, CheckOut: checkOut, Guests: 1}, domain.Inventory{})
+		require.NoError(t, err)
+		return services.NewService(repo, clients.NewRabbit(clients.RabbitConfig{}), hotels)
+	}
+	room := func(capacity int) []domain.RoomType {
+		return []domain.RoomType{{ID: "rt_1", Name: "Doble", Capacity: capacity, Units: 1}}
+	}
+
+	t.Run("Availability - Rooms, Capacity And Missing Hotels", func(t *testing.T) {
+		hotels := &fakeHotels{hotels: map[string]domain.Hotel{
+			"full":     {ID: "full", Rooms: room(2)},
+			"free":     {ID: "free", Rooms: room(2)},
+			"small":    {ID: "small", Rooms: room(1)},
+			"no-rooms": {ID: "no-rooms"},
+			"archived": {ID: "archived", Archived: true},
+		}}
+		svc := newService(hotels)
+
+		availability, err := svc.Availability(context.Background(), []string{"full", "free", "small", "no-rooms", "archived", "gone"}, checkIn, checkOut, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{
+			"full": false, "free": true, "small": false, "no-rooms": false, "archived": false, "gone": false,
+		}, availability)
+	})
+
+	t.Run("Availability - Hotels API Error Is Returned", func(t *testing.T) {
+		hotels := &fakeHotels{
+			hotels: map[string]domain.Hotel{"free": {ID: "free", Rooms: room(2)}},
+			errs:   map[string]error{"broken": errors.New("hotels API returned status 500")},
+		}
+		svc := newService(hotels)
+
+		availability, err := svc.Availability(context.Background(), []string{"free", "broken"}, checkIn, checkOut, 2)
+
+		assert.ErrorContains(t, err, "broken")
+		assert.Nil(t, availability)
+	})
+
+	t.Run("Availability - Hotels Are Fetched In Parallel Within The Deadline", func(t *testing.T) {
+		ids := make([]string, 0, 16)
+		hotels := &fakeHotels{hotels: map[string]domain.Hotel{}, delay: 50 * time.Millisecond}
+		for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p"} {
+			hotels.hotels[id] = domain.Hotel{ID: id, Rooms: room(2)}
+			ids = append(ids, id)
+		}
+		svc := newService(hotels)
+
+		// Uno por vez serían 800ms
+		ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+		defer cancel()
+		availability, err := svc.Availability(ctx, ids, checkIn, checkOut, 2)
+
+		require.NoError(t, err)
+		assert.Len(t, availability, len(ids))
+	})
+
+	t.Run("Availability - Caller Deadline Exceeded", func(t *testing.T) {
+		hotels := &fakeHotels{hotels: map[string]domain.Hotel{"free": {ID: "free", Rooms: room(2)}}, delay: time.Second}
+		svc := newService(hotels)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := svc.Availability(ctx, []string{"free"}, checkIn, checkOut, 2)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Unavailable - Only Booked Hotels Without Rooms Left", func(t *testing.T) {
+		hotels := &fakeHotels{hotels: map[string]domain.Hotel{
+			"full":     {ID: "full", Rooms: room(2)},
+			"no-rooms": {ID: "no-rooms"},
+		}}
+		svc := newService(hotels)
+
+		unavailable, err := svc.Unavailable(context.Background(), checkIn, checkOut, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"full", "no-rooms"}, unavailable)
+
+		unavailable, err = svc.Unavailable(context.Background(), checkOut, checkOut.AddDate(0, 0, 2), 2)
+		require.NoError(t, err)
+		assert.Empty(t, unavailable)
+	})
+}
+
+func TestPriceFor(t *testing.T) {
+	checkIn := time.Date(2025, 11, 21, 0, 0, 0, 0, time.UTC)
+	nightly := []domain.NightPrice{
+		{Date: "2025-11-21", Base: 1000, WeekendPct: 20, Price: 1200},
+		{Date: "2025-11-22", Base: 1000, WeekendPct: 20, Price: 1200},
+	}
+	quote := domain.Quote{HotelID: "h1", Nights: 2, Guests: 2, Currency: "ARS", Nightly: nightly, Subtotal: 2400, DiscountPct: 10, Discount: 240, Total: 2160}
+	hotels := &fakeHotels{quote: quote, errs: map[string]error{"broken": errors.New("hotels API returned status 500")}}
+	svc := services.NewService(repositories.NewMock(), clients.NewRabbit(clients.RabbitConfig{}), hotels)
+
+	tests := []struct {
+		name    string
+		hotelID string
+		total   float64
+		wantErr error
+	}{
+		{"Matching Total", "h1", 2160, nil},
+		{"Within The Tolerance", "h1", 2160.005, nil},
+		{"Missing Total Is Filled From The Quote", "h1", 0, nil},
+		{"Mismatch Beyond The Tolerance", "h1", 2160.05, services.ErrPriceMismatch},
+		{"Client Sent The Price Before Discount", "h1", 2400, services.ErrPriceMismatch},
+	}
+	for _, tt := range tests {
+		t.Run("PriceFor - "+tt.name, func(t *testing.T) {
+			r := domain.Reservation{HotelID: tt.hotelID, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 2, TotalPrice: tt.total}
+
+			err := svc.PriceFor(context.Background(), &r)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, r.Currency)
+				assert.Nil(t, r.PriceBreakdown)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 2160.0, r.TotalPrice)
+			assert.Equal(t, "ARS", r.Currency)
+			assert.Equal(t, &domain.PriceBreakdown{Nightly: nightly, Subtotal: 2400, DiscountPct: 10, Discount: 240}, r.PriceBreakdown)
+		})
+	}
+
+	t.Run("PriceFor - Quote Error Is Returned", func(t *testing.T) {
+		r := domain.Reservation{HotelID: "broken", CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 2}
+
+		err := svc.PriceFor(context.Background(), &r)
+
+		assert.ErrorContains(t, err, "status 500")
+	})
+}
+
+func TestHotelTransition(t *testing.T) {
+	checkIn := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 10)
+	hotels := &fakeHotels{hotels: map[string]domain.Hotel{"h1": {ID: "h1", OwnerID: "7"}}}
+
+	// newPending deja una reserva pendiente en hotelID
+	newPending := func(t *testing.T, hotels *fakeHotels, hotelID string) (*services.Service, domain.Reservation) {
+		t.Helper()
+		repo := repositories.NewMock()
+		r, err := repo.Create(domain.Reservation{HotelID: hotelID, UserID: "3", CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 2, Status: domain.StatusPending}, domain.Inventory{})
+		require.NoError(t, err)
+		return services.NewService(repo, clients.NewRabbit(clients.RabbitConfig{}), hotels), r
+	}
+	as := func(userID string, admin bool) context.Context {
+		return domain.WithActor(context.Background(), domain.Actor{UserID: userID, Admin: admin})
+	}
+
+	tests := []struct {
+		name    string
+		hotelID string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"Hotel Owner", "h1", as("7", false), nil},
+		{"Admin", "h1", as("1", true), nil},
+		{"Admin On A Hotel hotels-api No Longer Has", "gone", as("1", true), nil},
+		{"Guest Cannot Confirm Their Own Reservation", "h1", as("3", false), domain.ErrForbidden},
+		{"Owner Of Another Hotel", "gone", as("7", false), domain.ErrForbidden},
+		{"Not Authenticated", "h1", context.Background(), domain.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run("HotelTransition - "+tt.name, func(t *testing.T) {
+			svc, r := newPending(t, hotels, tt.hotelID)
+
+			out, err := svc.HotelTransition(tt.ctx, r.ID, domain.StatusConfirmed, "")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				unchanged, err := svc.GetByID(r.ID)
+				require.NoError(t, err)
+				assert.Equal(t, domain.StatusPending, unchanged.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.StatusConfirmed, out.Status)
+		})
+	}
+
+	t.Run("HotelTransition - hotels-api Error", func(t *testing.T) {
+		broken := &fakeHotels{errs: map[string]error{"h1": errors.New("hotels API returned status 500")}}
+		svc, r := newPending(t, broken, "h1")
+
+		_, err := svc.HotelTransition(as("7", false), r.ID, domain.StatusConfirmed, "")
+
+		assert.ErrorContains(t, err, "status 500")
+		assert.NotErrorIs(t, err, domain.ErrForbidden)
+	})
+}
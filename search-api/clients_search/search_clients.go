@@ -1,13 +1,28 @@
 package queues
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	sirup "github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 	"log"
 	"search/domain_search"
 	"search/services_search"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultRetryBaseDelay = time.Second
+	defaultRetryMaxDelay  = time.Minute
+	defaultPrefetch       = 10
+
+	attemptHeader = "x-attempt"
+	errorHeader   = "x-error"
 )
 
 type RabbitConfig struct {
@@ -16,15 +31,146 @@ type RabbitConfig struct {
 	Username  string
 	Password  string
 	QueueName string
+
+	MaxAttempts    int           // Times a message is processed before dead-lettering it (default 5)
+	RetryBaseDelay time.Duration // Delay before the first retry, doubled on every attempt (default 1s)
+	RetryMaxDelay  time.Duration // Upper bound for the retry delay (default 1m)
+}
+
+// Channel is the part of *amqp.Channel used to move messages between queues and read the
+// dead-letter queue (replaced by a fake in tests)
+type Channel interface {
+	Publish(exchange string, key string, mandatory bool, immediate bool, msg amqp.Publishing) error
+	Get(queue string, autoAck bool) (amqp.Delivery, bool, error)
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation
+}
+
+// confirmedChannel is a Channel in confirm mode. A message is only moved (and the original
+// acked) once the broker confirms the copy: a publish the broker drops would lose the event.
+type confirmedChannel struct {
+	Channel
+	mu       *sync.Mutex // one publish waits for its confirmation before the next one goes out
+	confirms chan amqp.Confirmation
+}
+
+func newConfirmedChannel(channel Channel) (confirmedChannel, error) {
+	if err := channel.Confirm(false); err != nil {
+		return confirmedChannel{}, fmt.Errorf("error putting Rabbit channel in confirm mode: %w", err)
+	}
+	return confirmedChannel{
+		Channel:  channel,
+		mu:       &sync.Mutex{},
+		confirms: channel.NotifyPublish(make(chan amqp.Confirmation, 1)),
+	}, nil
+}
+
+// publish sends msg to the queue through the default exchange and waits for the broker to confirm it
+func (channel confirmedChannel) publish(queueName string, msg amqp.Publishing) error {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
+	if err := channel.Publish("", queueName, false, false, msg); err != nil {
+		return err
+	}
+	confirm, ok := <-channel.confirms
+	if !ok {
+		return fmt.Errorf("channel closed before the broker confirmed the message for %s", queueName)
+	}
+	if !confirm.Ack {
+		return fmt.Errorf("broker did not accept the message for %s", queueName)
+	}
+	return nil
+}
+
+// EventHandler processes a hotel event taken from the queue
+type EventHandler interface {
+	HandleHotelEvent(event domain_search.HotelEvent) error
 }
 
 type Rabbit struct {
+	config     RabbitConfig
 	connection *amqp.Connection
-	channel    *amqp.Channel
-	queue      amqp.Queue
+	consumer   *amqp.Channel
+	channel    confirmedChannel // publishes retries and dead letters (same channel as consumer)
+	admin      confirmedChannel // separate channel for dead-letter inspection and replay
+	adminMu    *sync.Mutex
+	queue      string
+	deadLetter string
+	retries    []string // retry queue per attempt, retries[0] is used after the first failure
+}
+
+// deadLetterQueueName is where messages that cannot be processed end up, e.g. "hotels-news.dead-letter"
+func deadLetterQueueName(queueName string) string {
+	return queueName + ".dead-letter"
+}
+
+// retryQueueName names the delayed queue by its TTL so that changing the delays never clashes
+// with an already declared queue, e.g. "hotels-news.retry.2000ms"
+func retryQueueName(queueName string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", queueName, delay.Milliseconds())
+}
+
+// retryDelay returns the exponential backoff for the given attempt (1 based)
+func retryDelay(config RabbitConfig, attempt int) time.Duration {
+	delay := config.RetryBaseDelay
+	for i := 1; i < attempt && delay < config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > config.RetryMaxDelay {
+		delay = config.RetryMaxDelay
+	}
+	return delay
+}
+
+func withDefaults(config RabbitConfig) RabbitConfig {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.RetryBaseDelay <= 0 {
+		config.RetryBaseDelay = defaultRetryBaseDelay
+	}
+	if config.RetryMaxDelay <= 0 {
+		config.RetryMaxDelay = defaultRetryMaxDelay
+	}
+	return config
+}
+
+// retryQueues names the retry queue of every attempt but the last one
+func retryQueues(config RabbitConfig) []string {
+	retries := make([]string, 0, config.MaxAttempts-1)
+	for attempt := 1; attempt < config.MaxAttempts; attempt++ {
+		retries = append(retries, retryQueueName(config.QueueName, retryDelay(config, attempt)))
+	}
+	return retries
+}
+
+// NewRabbitWithChannels builds a Rabbit over queues that already exist, publishing on channel and
+// reading the dead-letter queue through admin. It cannot consume; NewRabbit is the real thing.
+func NewRabbitWithChannels(config RabbitConfig, channel Channel, admin Channel) (Rabbit, error) {
+	config = withDefaults(config)
+	publisher, err := newConfirmedChannel(channel)
+	if err != nil {
+		return Rabbit{}, err
+	}
+	adminChannel, err := newConfirmedChannel(admin)
+	if err != nil {
+		return Rabbit{}, err
+	}
+	return Rabbit{
+		config:     config,
+		channel:    publisher,
+		admin:      adminChannel,
+		adminMu:    &sync.Mutex{},
+		queue:      config.QueueName,
+		deadLetter: deadLetterQueueName(config.QueueName),
+		retries:    retryQueues(config),
+	}, nil
 }
 
 func NewRabbit(config RabbitConfig) Rabbit {
+	config = withDefaults(config)
+
 	connection, err := amqp.Dial(fmt.Sprintf("amqp://%s:%s@%s:%s/", config.Username, config.Password, config.Host, config.Port))
 	if err != nil {
 		log.Fatalf("error getting Rabbit connection: %v", err)
@@ -33,23 +179,64 @@ func NewRabbit(config RabbitConfig) Rabbit {
 	if err != nil {
 		log.Fatalf("error creating Rabbit channel: %v", err)
 	}
-	queue, err := channel.QueueDeclare(config.QueueName, false, false, false, false, nil)
+	admin, err := connection.Channel()
+	if err != nil {
+		log.Fatalf("error creating Rabbit admin channel: %v", err)
+	}
+	queue, err := channel.QueueDeclare(config.QueueName, true, false, false, false, nil)
 	if err != nil {
 		log.Fatalf("error declaring Rabbit queue: %v", err)
 	}
+	deadLetter, err := channel.QueueDeclare(deadLetterQueueName(config.QueueName), true, false, false, false, nil)
+	if err != nil {
+		log.Fatalf("error declaring Rabbit dead-letter queue: %v", err)
+	}
+
+	// Delayed requeue: each retry queue has a TTL and dead-letters expired messages back to the main queue
+	retries := retryQueues(config)
+	for i, name := range retries {
+		_, err := channel.QueueDeclare(name, true, false, false, false, amqp.Table{
+			"x-message-ttl":             retryDelay(config, i+1).Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": config.QueueName,
+		})
+		if err != nil {
+			log.Fatalf("error declaring Rabbit retry queue %s: %v", name, err)
+		}
+	}
+
+	if err := channel.Qos(defaultPrefetch, 0, false); err != nil {
+		log.Fatalf("error setting Rabbit prefetch: %v", err)
+	}
+	publisher, err := newConfirmedChannel(channel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	adminChannel, err := newConfirmedChannel(admin)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	return Rabbit{
+		config:     config,
 		connection: connection,
-		channel:    channel,
-		queue:      queue,
+		consumer:   channel,
+		channel:    publisher,
+		admin:      adminChannel,
+		adminMu:    &sync.Mutex{},
+		queue:      queue.Name,
+		deadLetter: deadLetter.Name,
+		retries:    retries,
 	}
 }
 
-// StartConsumer starts listening for messages on the RabbitMQ queue using the provided service handler
-func (queue Rabbit) StartConsumer(service services_search.Service) error {
-	messages, err := queue.channel.Consume(
-		queue.queue.Name,
+// StartConsumer starts listening for messages on the RabbitMQ queue using the provided service handler.
+// Messages are acknowledged manually once they are processed, retried or dead-lettered.
+func (queue Rabbit) StartConsumer(handler EventHandler) error {
+	messages, err := queue.consumer.Consume(
+		queue.queue,
 		"",
-		true,
+		false,
 		false,
 		false,
 		false,
@@ -61,24 +248,226 @@ func (queue Rabbit) StartConsumer(service services_search.Service) error {
 
 	go func() {
 		for msg := range messages {
-			var courseNew domain_search.CourseNew
-			err := json.Unmarshal(msg.Body, &courseNew)
-			if err != nil {
-				sirup.Error("Error unmarshaling message:", err)
-				continue
-			}
-			fmt.Println(courseNew)
-			// Call the service method
-			service.HandleCourseNew(courseNew)
+			queue.Handle(handler, msg)
 		}
 	}()
 
 	return nil
 }
 
+// Handle processes one delivery: it is acked once handled, moved to the retry queue of its attempt
+// or, when it cannot succeed or ran out of attempts, to the dead-letter queue. If moving it fails
+// it is nacked back to the queue.
+func (queue Rabbit) Handle(handler EventHandler, msg amqp.Delivery) {
+	var event domain_search.HotelEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		sirup.Error("Error unmarshaling message:", err)
+		queue.settle(msg, queue.sendToDeadLetter(msg, attemptsFrom(msg.Headers), err))
+		return
+	}
+
+	// Call the service method
+	err := handler.HandleHotelEvent(event)
+	if err == nil {
+		queue.settle(msg, nil)
+		return
+	}
+	if errors.Is(err, services_search.ErrUnsupportedEvent) {
+		queue.settle(msg, queue.sendToDeadLetter(msg, attemptsFrom(msg.Headers), err))
+		return
+	}
+
+	attempt := attemptsFrom(msg.Headers) + 1
+	if attempt >= queue.config.MaxAttempts {
+		sirup.Errorf("Giving up on event %s (%s) after %d attempts: %v", event.Type, event.CorrelationID, attempt, err)
+		queue.settle(msg, queue.sendToDeadLetter(msg, attempt, err))
+		return
+	}
+	sirup.Warnf("Error handling event %s (%s), retrying in %s: %v", event.Type, event.CorrelationID, retryDelay(queue.config, attempt), err)
+	queue.settle(msg, queue.scheduleRetry(msg, attempt, err))
+}
+
+// settle acks the message once it has been handled or moved elsewhere (the broker confirmed
+// the copy); if moving it failed the message goes back to the queue so it is never lost
+func (queue Rabbit) settle(msg amqp.Delivery, moveErr error) {
+	if moveErr != nil {
+		sirup.Errorf("Error moving message, requeueing it: %v", moveErr)
+		if err := msg.Nack(false, true); err != nil {
+			sirup.Error("Error requeueing message:", err)
+		}
+		return
+	}
+	if err := msg.Ack(false); err != nil {
+		sirup.Error("Error acknowledging message:", err)
+	}
+}
+
+// scheduleRetry publishes the message on the retry queue for the attempt; it comes back once the TTL expires
+func (queue Rabbit) scheduleRetry(msg amqp.Delivery, attempt int, reason error) error {
+	return queue.channel.publish(queue.retries[attempt-1], republish(msg, amqp.Table{
+		attemptHeader: int32(attempt),
+		errorHeader:   reason.Error(),
+	}))
+}
+
+// sendToDeadLetter moves a message we cannot process to the dead-letter queue, keeping the reason
+func (queue Rabbit) sendToDeadLetter(msg amqp.Delivery, attempts int, reason error) error {
+	publishing := republish(msg, amqp.Table{
+		attemptHeader:      int32(attempts),
+		errorHeader:        reason.Error(),
+		"x-original-queue": queue.queue,
+	})
+	if publishing.MessageId == "" {
+		publishing.MessageId = newMessageID()
+	}
+	if err := queue.channel.publish(queue.deadLetter, publishing); err != nil {
+		return fmt.Errorf("error sending message to dead-letter queue: %w", err)
+	}
+	sirup.Warnf("Message %s sent to dead-letter queue: %v", publishing.MessageId, reason)
+	return nil
+}
+
+// ListDeadLetters returns up to limit dead-lettered messages without removing them from the queue
+func (queue Rabbit) ListDeadLetters(limit int) ([]domain_search.DeadLetterDto, error) {
+	queue.adminMu.Lock()
+	defer queue.adminMu.Unlock()
+
+	deliveries, err := queue.peekDeadLetters(limit)
+	defer queue.release(deliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain_search.DeadLetterDto, 0, len(deliveries))
+	for _, msg := range deliveries {
+		result = append(result, toDeadLetterDto(msg))
+	}
+	return result, nil
+}
+
+// ReplayDeadLetter publishes a dead-lettered message back to the main queue with a fresh attempt
+// count. The dead-letter queue is read only up to the message: the ones ahead of it stay unacked
+// while looking and then go back, and RabbitMQ requeues them in their original position.
+func (queue Rabbit) ReplayDeadLetter(id string) error {
+	queue.adminMu.Lock()
+	defer queue.adminMu.Unlock()
+
+	skipped := make([]amqp.Delivery, 0)
+	defer func() { queue.release(skipped) }()
+
+	for {
+		msg, ok, err := queue.admin.Get(queue.deadLetter, false)
+		if err != nil {
+			return fmt.Errorf("error reading dead-letter queue: %w", err)
+		}
+		if !ok {
+			return domain_search.ErrDeadLetterNotFound
+		}
+		if msg.MessageId != id {
+			skipped = append(skipped, msg)
+			continue
+		}
+
+		// Only removed from the dead-letter queue once the broker confirmed the copy
+		if err := queue.admin.publish(queue.queue, republish(msg, nil)); err != nil {
+			_ = msg.Nack(false, true)
+			return fmt.Errorf("error replaying message %s: %w", id, err)
+		}
+		if err := msg.Ack(false); err != nil {
+			return fmt.Errorf("error removing message %s from dead-letter queue: %w", id, err)
+		}
+		sirup.Infof("Dead-lettered message %s replayed", id)
+		return nil
+	}
+}
+
+// peekDeadLetters gets up to limit messages from the dead-letter queue without acking them
+func (queue Rabbit) peekDeadLetters(limit int) ([]amqp.Delivery, error) {
+	deliveries := make([]amqp.Delivery, 0)
+	for len(deliveries) < limit {
+		msg, ok, err := queue.admin.Get(queue.deadLetter, false)
+		if err != nil {
+			return deliveries, fmt.Errorf("error reading dead-letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+		deliveries = append(deliveries, msg)
+	}
+	return deliveries, nil
+}
+
+// release puts peeked messages back in the dead-letter queue
+func (queue Rabbit) release(deliveries []amqp.Delivery) {
+	for _, msg := range deliveries {
+		if err := msg.Nack(false, true); err != nil {
+			sirup.Error("Error returning message to dead-letter queue:", err)
+		}
+	}
+}
+
+// republish copies a delivery into a new persistent message, replacing its headers
+func republish(msg amqp.Delivery, headers amqp.Table) amqp.Publishing {
+	return amqp.Publishing{
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.MessageId,
+		Timestamp:    time.Now(),
+		Headers:      headers,
+		Body:         msg.Body,
+	}
+}
+
+func toDeadLetterDto(msg amqp.Delivery) domain_search.DeadLetterDto {
+	reason, _ := msg.Headers[errorHeader].(string)
+	originalQueue, _ := msg.Headers["x-original-queue"].(string)
+	body := json.RawMessage(msg.Body)
+	if !json.Valid(msg.Body) {
+		// Keep the raw payload visible even when it is not JSON
+		body, _ = json.Marshal(string(msg.Body))
+	}
+	return domain_search.DeadLetterDto{
+		ID:             msg.MessageId,
+		Error:          reason,
+		Attempts:       attemptsFrom(msg.Headers),
+		OriginalQueue:  originalQueue,
+		DeadLetteredAt: msg.Timestamp,
+		Body:           body,
+	}
+}
+
+// attemptsFrom reads how many times the message has already been processed
+func attemptsFrom(headers amqp.Table) int {
+	switch value := headers[attemptHeader].(type) {
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	case int:
+		return value
+	}
+	return 0
+}
+
+func newMessageID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 // Close cleans up the RabbitMQ resources
 func (queue Rabbit) Close() {
-	if err := queue.channel.Close(); err != nil {
+	if queue.connection == nil {
+		return
+	}
+	if admin, ok := queue.admin.Channel.(*amqp.Channel); ok {
+		if err := admin.Close(); err != nil {
+			sirup.Error("error closing Rabbit admin channel:", err)
+		}
+	}
+	if err := queue.consumer.Close(); err != nil {
 		sirup.Error("error closing Rabbit channel:", err)
 	}
 	if err := queue.connection.Close(); err != nil {
@@ -0,0 +1,269 @@
+package queues_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	queues "search/clients_search"
+	"search/domain_search"
+	"search/services_search"
+)
+
+// fakeChannel records what is published and serves Get from a list of dead-lettered messages.
+// In confirm mode every publish is confirmed, negatively when nack is set.
+type fakeChannel struct {
+	published  []published
+	publishErr error
+	nack       bool
+	confirm    bool
+	confirms   chan amqp.Confirmation
+	deadLetter []amqp.Delivery
+	gets       int
+}
+
+type published struct {
+	Key string
+	Msg amqp.Publishing
+}
+
+func (f *fakeChannel) Publish(exchange string, key string, mandatory bool, immediate bool, msg amqp.Publishing) error {
+	if f.publishErr != nil {
+		return f.publishErr
+	}
+	f.published = append(f.published, published{Key: key, Msg: msg})
+	if f.confirm {
+		f.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(f.published)), Ack: !f.nack}
+	}
+	return nil
+}
+
+func (f *fakeChannel) Confirm(noWait bool) error {
+	f.confirm = true
+	return nil
+}
+
+func (f *fakeChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	f.confirms = confirm
+	return confirm
+}
+
+func (f *fakeChannel) Get(queue string, autoAck bool) (amqp.Delivery, bool, error) {
+	f.gets++
+	if len(f.deadLetter) == 0 {
+		return amqp.Delivery{}, false, nil
+	}
+	msg := f.deadLetter[0]
+	f.deadLetter = f.deadLetter[1:]
+	return msg, true, nil
+}
+
+// fakeAcknowledger records how a delivery was settled
+type fakeAcknowledger struct {
+	acked    []uint64
+	requeued []uint64
+}
+
+func (f *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	f.acked = append(f.acked, tag)
+	return nil
+}
+
+func (f *fakeAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	if requeue {
+		f.requeued = append(f.requeued, tag)
+	}
+	return nil
+}
+
+func (f *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	return f.Nack(tag, false, requeue)
+}
+
+type fakeHandler struct {
+	err error
+}
+
+func (f fakeHandler) HandleHotelEvent(event domain_search.HotelEvent) error {
+	return f.err
+}
+
+var config = queues.RabbitConfig{
+	QueueName:      "hotels-news",
+	MaxAttempts:    4,
+	RetryBaseDelay: 2 * time.Second,
+	RetryMaxDelay:  5 * time.Second,
+}
+
+func newRabbit(t *testing.T, channel *fakeChannel, admin *fakeChannel) queues.Rabbit {
+	t.Helper()
+	rabbit, err := queues.NewRabbitWithChannels(config, channel, admin)
+	require.NoError(t, err)
+	require.True(t, channel.confirm, "retries and dead letters need publisher confirms")
+	require.True(t, admin.confirm, "replays need publisher confirms")
+	return rabbit
+}
+
+func delivery(ack *fakeAcknowledger, tag uint64, attempt int, body string) amqp.Delivery {
+	msg := amqp.Delivery{Acknowledger: ack, DeliveryTag: tag, MessageId: fmt.Sprintf("m%d", tag), Body: []byte(body)}
+	if attempt > 0 {
+		msg.Headers = amqp.Table{"x-attempt": int32(attempt)}
+	}
+	return msg
+}
+
+const event = `{"version":1,"type":"hotel.updated","entity_id":"h1"}`
+
+func TestHandle(t *testing.T) {
+	t.Run("Handle - Success Is Acked", func(t *testing.T) {
+		channel, ack := &fakeChannel{}, &fakeAcknowledger{}
+		rabbit := newRabbit(t, channel, &fakeChannel{})
+
+		rabbit.Handle(fakeHandler{}, delivery(ack, 1, 0, event))
+
+		assert.Equal(t, []uint64{1}, ack.acked)
+		assert.Empty(t, channel.published)
+	})
+
+	t.Run("Handle - Retry Delay Doubles Up To The Maximum", func(t *testing.T) {
+		expected := map[int]string{
+			0: "hotels-news.retry.2000ms", // first failure
+			1: "hotels-news.retry.4000ms",
+			2: "hotels-news.retry.5000ms", // 8s capped at 5s
+		}
+		for previous, queue := range expected {
+			channel, ack := &fakeChannel{}, &fakeAcknowledger{}
+			rabbit := newRabbit(t, channel, &fakeChannel{})
+
+			rabbit.Handle(fakeHandler{err: errors.New("hotels-api down")}, delivery(ack, 7, previous, event))
+
+			require.Len(t, channel.published, 1)
+			assert.Equal(t, queue, channel.published[0].Key)
+			assert.Equal(t, int32(previous+1), channel.published[0].Msg.Headers["x-attempt"])
+			assert.Equal(t, "hotels-api down", channel.published[0].Msg.Headers["x-error"])
+			assert.Equal(t, []uint64{7}, ack.acked)
+		}
+	})
+
+	t.Run("Handle - Last Attempt Goes To The Dead-Letter Queue", func(t *testing.T) {
+		channel, ack := &fakeChannel{}, &fakeAcknowledger{}
+		rabbit := newRabbit(t, channel, &fakeChannel{})
+
+		rabbit.Handle(fakeHandler{err: errors.New("hotels-api down")}, delivery(ack, 3, 3, event))
+
+		require.Len(t, channel.published, 1)
+		assert.Equal(t, "hotels-news.dead-letter", channel.published[0].Key)
+		assert.Equal(t, int32(4), channel.published[0].Msg.Headers["x-attempt"])
+		assert.Equal(t, "hotels-news", channel.published[0].Msg.Headers["x-original-queue"])
+		assert.Equal(t, []uint64{3}, ack.acked)
+	})
+
+	t.Run("Handle - Unsupported And Invalid Events Are Not Retried", func(t *testing.T) {
+		unsupported := fakeHandler{err: fmt.Errorf("%w: version 9", services_search.ErrUnsupportedEvent)}
+		for _, msg := range []string{event, `{not json`} {
+			channel, ack := &fakeChannel{}, &fakeAcknowledger{}
+			rabbit := newRabbit(t, channel, &fakeChannel{})
+
+			rabbit.Handle(unsupported, delivery(ack, 1, 0, msg))
+
+			require.Len(t, channel.published, 1)
+			assert.Equal(t, "hotels-news.dead-letter", channel.published[0].Key)
+			assert.Equal(t, []uint64{1}, ack.acked)
+		}
+	})
+
+	t.Run("Handle - Message Is Requeued When It Cannot Be Moved", func(t *testing.T) {
+		channel, ack := &fakeChannel{publishErr: errors.New("channel closed")}, &fakeAcknowledger{}
+		rabbit := newRabbit(t, channel, &fakeChannel{})
+
+		rabbit.Handle(fakeHandler{err: errors.New("hotels-api down")}, delivery(ack, 5, 0, event))
+
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{5}, ack.requeued)
+	})
+}
+
+func TestPublisherConfirms(t *testing.T) {
+	t.Run("Handle - Retry Not Confirmed Requeues The Original", func(t *testing.T) {
+		channel, ack := &fakeChannel{nack: true}, &fakeAcknowledger{}
+		rabbit := newRabbit(t, channel, &fakeChannel{})
+
+		rabbit.Handle(fakeHandler{err: errors.New("hotels-api down")}, delivery(ack, 5, 0, event))
+
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{5}, ack.requeued)
+	})
+
+	t.Run("Handle - Dead Letter Not Confirmed Requeues The Original", func(t *testing.T) {
+		channel, ack := &fakeChannel{nack: true}, &fakeAcknowledger{}
+		rabbit := newRabbit(t, channel, &fakeChannel{})
+
+		rabbit.Handle(fakeHandler{}, delivery(ack, 6, 0, `{not json`))
+
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{6}, ack.requeued)
+	})
+
+	t.Run("Handle - Channel Closed Before The Confirmation", func(t *testing.T) {
+		channel, ack := &fakeChannel{}, &fakeAcknowledger{}
+		rabbit := newRabbit(t, channel, &fakeChannel{})
+		channel.confirm = false // the broker never answers and the channel closes
+		close(channel.confirms)
+
+		rabbit.Handle(fakeHandler{err: errors.New("hotels-api down")}, delivery(ack, 7, 0, event))
+
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{7}, ack.requeued)
+	})
+
+	t.Run("ReplayDeadLetter - Not Confirmed Keeps The Message", func(t *testing.T) {
+		ack := &fakeAcknowledger{}
+		admin := &fakeChannel{nack: true, deadLetter: []amqp.Delivery{delivery(ack, 1, 5, event)}}
+		rabbit := newRabbit(t, &fakeChannel{}, admin)
+
+		err := rabbit.ReplayDeadLetter("m1")
+
+		assert.Error(t, err)
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{1}, ack.requeued)
+	})
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	deadLetters := func(ack *fakeAcknowledger) []amqp.Delivery {
+		return []amqp.Delivery{delivery(ack, 1, 5, event), delivery(ack, 2, 5, event), delivery(ack, 3, 5, event)}
+	}
+
+	t.Run("ReplayDeadLetter - Stops At The Message", func(t *testing.T) {
+		ack := &fakeAcknowledger{}
+		admin := &fakeChannel{deadLetter: deadLetters(ack)}
+		rabbit := newRabbit(t, &fakeChannel{}, admin)
+
+		require.NoError(t, rabbit.ReplayDeadLetter("m2"))
+
+		assert.Equal(t, 2, admin.gets) // m3 is never read
+		require.Len(t, admin.published, 1)
+		assert.Equal(t, "hotels-news", admin.published[0].Key)
+		assert.Nil(t, admin.published[0].Msg.Headers) // fresh attempt count
+		assert.Equal(t, []uint64{2}, ack.acked)
+		assert.Equal(t, []uint64{1}, ack.requeued)
+	})
+
+	t.Run("ReplayDeadLetter - Not Found Returns Everything", func(t *testing.T) {
+		ack := &fakeAcknowledger{}
+		admin := &fakeChannel{deadLetter: deadLetters(ack)}
+		rabbit := newRabbit(t, &fakeChannel{}, admin)
+
+		err := rabbit.ReplayDeadLetter("missing")
+
+		assert.ErrorIs(t, err, domain_search.ErrDeadLetterNotFound)
+		assert.Empty(t, admin.published)
+		assert.Empty(t, ack.acked)
+		assert.Equal(t, []uint64{1, 2, 3}, ack.requeued)
+	})
+}
@@ -0,0 +1,67 @@
+package controllers_search
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequireAdmin guards the /admin endpoints with the JWT users-api issues (Authorization: Bearer
+// <token>), the same check hotels-api does: 401 without a valid token, 403 if the user is not an admin.
+func RequireAdmin(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "authentication required",
+			})
+			return
+		}
+
+		admin, err := parseAdminClaim(strings.TrimSpace(token), key)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid token",
+			})
+			return
+		}
+		if !admin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "admin access required",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// parseAdminClaim validates an HS256 token from users-api, which carries user_id, admin and
+// expiration_date (a date string instead of the standard "exp"), and returns its admin claim
+func parseAdminClaim(value string, key string) (bool, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(value, claims, func(*jwt.Token) (any, error) {
+		return []byte(key), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return false, err
+	}
+
+	expiration, _ := claims["expiration_date"].(string)
+	expiresAt, err := time.Parse(time.RFC3339Nano, expiration)
+	if err != nil {
+		return false, fmt.Errorf("invalid expiration_date: %w", err)
+	}
+	if time.Now().After(expiresAt) {
+		return false, errors.New("token expired")
+	}
+	if userID, ok := claims["user_id"].(float64); !ok || userID <= 0 {
+		return false, errors.New("invalid user_id")
+	}
+
+	admin, _ := claims["admin"].(bool)
+	return admin, nil
+}
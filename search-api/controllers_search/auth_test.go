@@ -0,0 +1,87 @@
+package controllers_search_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	controllers "search/controllers_search"
+)
+
+const jwtKey = "test-key"
+
+// token signs claims like users-api does
+func token(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func claims(admin bool, expiresIn time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"username":        "ana",
+		"user_id":         float64(7),
+		"admin":           admin,
+		"expiration_date": time.Now().Add(expiresIn).Format(time.RFC3339),
+	}
+}
+
+func adminRequest(authorization string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/reindex", controllers.RequireAdmin(jwtKey), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"running": false})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/reindex", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRequireAdmin(t *testing.T) {
+	t.Run("RequireAdmin - Admin Token", func(t *testing.T) {
+		recorder := adminRequest("Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(true, time.Hour)))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("RequireAdmin - Missing Header", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, adminRequest("").Code)
+		assert.Equal(t, http.StatusUnauthorized, adminRequest("Bearer ").Code)
+		assert.Equal(t, http.StatusUnauthorized, adminRequest(token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(true, time.Hour))).Code)
+	})
+
+	t.Run("RequireAdmin - Non Admin Caller", func(t *testing.T) {
+		recorder := adminRequest("Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(false, time.Hour)))
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("RequireAdmin - Expired Token", func(t *testing.T) {
+		recorder := adminRequest("Bearer " + token(t, jwt.SigningMethodHS256, []byte(jwtKey), claims(true, -time.Minute)))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("RequireAdmin - Wrong Key Or Signing Method", func(t *testing.T) {
+		wrongKey := adminRequest("Bearer " + token(t, jwt.SigningMethodHS256, []byte("other-key"), claims(true, time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, wrongKey.Code)
+
+		hs512 := adminRequest("Bearer " + token(t, jwt.SigningMethodHS512, []byte(jwtKey), claims(true, time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, hs512.Code)
+
+		unsigned := adminRequest("Bearer " + token(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(true, time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, unsigned.Code)
+	})
+}
@@ -0,0 +1,72 @@
+package controllers_search
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"search/domain_search"
+	"strconv"
+)
+
+const defaultDeadLettersLimit = 50
+
+type DeadLetterQueue interface {
+	ListDeadLetters(limit int) ([]domain_search.DeadLetterDto, error)
+	ReplayDeadLetter(id string) error
+}
+
+type DeadLettersController struct {
+	queue DeadLetterQueue
+}
+
+func NewDeadLettersController(queue DeadLetterQueue) DeadLettersController {
+	return DeadLettersController{
+		queue: queue,
+	}
+}
+
+// List returns the messages currently sitting in the dead-letter queue
+func (controller DeadLettersController) List(c *gin.Context) {
+	limit := defaultDeadLettersLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("invalid limit: %s", value),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	messages, err := controller.queue.ListDeadLetters(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("error listing dead-lettered messages: %s", err.Error()),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// Replay sends a dead-lettered message back to the main queue
+func (controller DeadLettersController) Replay(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := controller.queue.ReplayDeadLetter(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain_search.ErrDeadLetterNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": fmt.Sprintf("message %s replayed", id),
+	})
+}
@@ -0,0 +1,46 @@
+package controllers_search
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"search/services_search"
+)
+
+type Reindexer interface {
+	Start() (services_search.ReindexStatus, error)
+	Status() services_search.ReindexStatus
+}
+
+type ReindexController struct {
+	reindexer Reindexer
+}
+
+func NewReindexController(reindexer Reindexer) ReindexController {
+	return ReindexController{
+		reindexer: reindexer,
+	}
+}
+
+// Start launches a full reindex in the background
+func (controller ReindexController) Start(c *gin.Context) {
+	status, err := controller.reindexer.Start()
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, services_search.ErrReindexRunning) {
+			code = http.StatusConflict
+		}
+		c.JSON(code, gin.H{
+			"error":  err.Error(),
+			"status": status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// Status reports the progress of the running (or last) reindex
+func (controller ReindexController) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, controller.reindexer.Status())
+}
@@ -4,15 +4,33 @@ import (
 	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"math"
 	"net/http"
+	"net/url"
 	"search/domain_search"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Service interface {
-	Search(ctx context.Context, query string, offset int, limit int) ([]domain_search.CourseDto, error)
+	Search(ctx context.Context, query domain_search.SearchQuery) (domain_search.SearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) domain_search.SuggestionsDto
 }
 
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+	maxGuests    = 10 // same limit reservations-api enforces
+
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+	maxPrefixLength     = 50
+
+	defaultRadiusKm = 10
+	maxRadiusKm     = 500
+)
+
 type Controller struct {
 	service Service
 }
@@ -23,12 +41,20 @@ func NewController(service Service) Controller {
 	}
 }
 
-func (controller Controller) Search(c *gin.Context) {
-	// Parse query from URL
-	query := c.Query("q")
+var validSorts = map[string]bool{
+	"":                          true,
+	domain_search.SortRelevance: true,
+	domain_search.SortPriceAsc:  true,
+	domain_search.SortPriceDesc: true,
+	domain_search.SortStarsAsc:  true,
+	domain_search.SortStarsDesc: true,
+	domain_search.SortRating:    true,
+	domain_search.SortDistance:  true,
+}
 
-	// Parse offset from URL
-	offset, err := strconv.Atoi(c.Query("offset"))
+// Search handles GET /search?q=&city=&min_stars=&max_stars=&min_price=&max_price=&amenities=wifi,spa&sort=&offset=&limit=&cursor=&check_in=&check_out=&guests=&lat=&lon=&radius_km=
+func (controller Controller) Search(c *gin.Context) {
+	query, err := parseSearchQuery(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": fmt.Sprintf("invalid request: %s", err),
@@ -36,24 +62,243 @@ func (controller Controller) Search(c *gin.Context) {
 		return
 	}
 
-	// Parse limit from URL
-	limit, err := strconv.Atoi(c.Query("limit"))
+	// Invoke service
+	result, err := controller.service.Search(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": fmt.Sprintf("invalid request: %s", err),
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("error searching hotels: %s", err.Error()),
 		})
 		return
 	}
 
-	// Invoke service
-	courses, err := controller.service.Search(c.Request.Context(), query, offset, limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("error searching courses: %s", err.Error()),
+	result.Next = nextLink(c, query, result)
+
+	// Send response
+	c.JSON(http.StatusOK, result)
+}
+
+// Suggest handles GET /search/suggest?prefix=&limit= for the search bar typeahead.
+// It always answers 200: an empty list when there is nothing to suggest or Solr is slow.
+func (controller Controller) Suggest(c *gin.Context) {
+	prefix := strings.TrimSpace(c.Query("prefix"))
+	if len([]rune(prefix)) > maxPrefixLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("invalid request: prefix cannot be longer than %d characters", maxPrefixLength),
 		})
 		return
 	}
 
-	// Send response
-	c.JSON(http.StatusOK, courses)
+	limit := defaultSuggestLimit
+	if c.Query("limit") != "" {
+		var err error
+		if limit, err = intParam(c, "limit"); err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid request: limit must be greater than zero",
+			})
+			return
+		}
+		limit = min(limit, maxSuggestLimit)
+	}
+
+	// Browsers may reuse the answer while the user keeps typing
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, controller.service.Suggest(c.Request.Context(), prefix, limit))
+}
+
+// nextLink builds the URL of the following page keeping every other query param, or "" on the last page
+func nextLink(c *gin.Context, query domain_search.SearchQuery, result domain_search.SearchResult) string {
+	params := c.Request.URL.Query()
+	if query.Cursor != "" {
+		// Solr hands back the cursor it was sent once there is nothing left (NextCursor stays empty)
+		if result.NextCursor == "" {
+			return ""
+		}
+		params.Set("cursor", result.NextCursor)
+	} else {
+		if query.Offset+query.Limit >= result.Total {
+			return ""
+		}
+		params.Set("offset", strconv.Itoa(query.Offset+query.Limit))
+	}
+	params.Set("limit", strconv.Itoa(query.Limit))
+
+	next := url.URL{Path: c.Request.URL.Path, RawQuery: params.Encode()}
+	return next.String()
+}
+
+func parseSearchQuery(c *gin.Context) (domain_search.SearchQuery, error) {
+	query := domain_search.SearchQuery{
+		Query:  strings.TrimSpace(c.Query("q")),
+		City:   strings.TrimSpace(c.Query("city")),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+		Limit:  defaultLimit,
+	}
+
+	var err error
+	if query.Offset, err = intParam(c, "offset"); err != nil {
+		return query, err
+	}
+	if c.Query("limit") != "" {
+		if query.Limit, err = intParam(c, "limit"); err != nil {
+			return query, err
+		}
+	}
+	if query.Offset < 0 {
+		return query, fmt.Errorf("offset cannot be negative")
+	}
+	if query.Limit < 1 {
+		return query, fmt.Errorf("limit must be greater than zero")
+	}
+	if query.Limit > maxLimit {
+		query.Limit = maxLimit
+	}
+	if query.Cursor != "" && query.Offset > 0 {
+		return query, fmt.Errorf("offset cannot be combined with cursor")
+	}
+	if query.MinStars, err = intParam(c, "min_stars"); err != nil {
+		return query, err
+	}
+	if query.MaxStars, err = intParam(c, "max_stars"); err != nil {
+		return query, err
+	}
+	if query.MinPrice, err = floatParam(c, "min_price"); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = floatParam(c, "max_price"); err != nil {
+		return query, err
+	}
+
+	// amenities=wifi,spa and amenities=wifi&amenities=spa are both accepted
+	for _, value := range c.QueryArray("amenities") {
+		for _, amenity := range strings.Split(value, ",") {
+			if amenity = strings.TrimSpace(amenity); amenity != "" {
+				query.Amenities = append(query.Amenities, amenity)
+			}
+		}
+	}
+
+	if err := parseStay(c, &query); err != nil {
+		return query, err
+	}
+	if err := parseNear(c, &query); err != nil {
+		return query, err
+	}
+
+	if !validSorts[query.Sort] {
+		return query, fmt.Errorf("unknown sort %q", query.Sort)
+	}
+	if query.MinStars < 0 || query.MaxStars < 0 || query.MinPrice < 0 || query.MaxPrice < 0 {
+		return query, fmt.Errorf("filters cannot be negative")
+	}
+	if query.MaxStars > 0 && query.MinStars > query.MaxStars {
+		return query, fmt.Errorf("min_stars cannot be greater than max_stars")
+	}
+	if query.MaxPrice > 0 && query.MinPrice > query.MaxPrice {
+		return query, fmt.Errorf("min_price cannot be greater than max_price")
+	}
+	return query, nil
+}
+
+// parseStay reads check_in/check_out (YYYY-MM-DD, both or neither) and guests
+func parseStay(c *gin.Context, query *domain_search.SearchQuery) error {
+	checkIn, checkOut := c.Query("check_in"), c.Query("check_out")
+	if checkIn == "" && checkOut == "" {
+		if c.Query("guests") != "" {
+			return fmt.Errorf("guests requires check_in and check_out")
+		}
+		return nil
+	}
+	if checkIn == "" || checkOut == "" {
+		return fmt.Errorf("check_in and check_out must be sent together")
+	}
+
+	var err error
+	if query.CheckIn, err = time.Parse(time.DateOnly, checkIn); err != nil {
+		return fmt.Errorf("invalid check_in: %s", checkIn)
+	}
+	if query.CheckOut, err = time.Parse(time.DateOnly, checkOut); err != nil {
+		return fmt.Errorf("invalid check_out: %s", checkOut)
+	}
+	if !query.CheckIn.Before(query.CheckOut) {
+		return fmt.Errorf("check_in must be before check_out")
+	}
+
+	query.Guests = 1
+	if c.Query("guests") != "" {
+		if query.Guests, err = intParam(c, "guests"); err != nil {
+			return err
+		}
+	}
+	if query.Guests < 1 || query.Guests > maxGuests {
+		return fmt.Errorf("guests must be between 1 and %d", maxGuests)
+	}
+	return nil
+}
+
+// parseNear reads lat/lon (both or neither) and radius_km for a geo search
+func parseNear(c *gin.Context, query *domain_search.SearchQuery) error {
+	if c.Query("lat") == "" && c.Query("lon") == "" {
+		if c.Query("radius_km") != "" {
+			return fmt.Errorf("radius_km requires lat and lon")
+		}
+		if query.Sort == domain_search.SortDistance {
+			return fmt.Errorf("sort distance requires lat and lon")
+		}
+		return nil
+	}
+	if c.Query("lat") == "" || c.Query("lon") == "" {
+		return fmt.Errorf("lat and lon must be sent together")
+	}
+
+	lat, err := floatParam(c, "lat")
+	if err != nil {
+		return err
+	}
+	lon, err := floatParam(c, "lon")
+	if err != nil {
+		return err
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return fmt.Errorf("lat must be between -90 and 90 and lon between -180 and 180")
+	}
+	query.Near = &domain_search.GeoPoint{Lat: lat, Lon: lon}
+
+	query.RadiusKm = defaultRadiusKm
+	if c.Query("radius_km") != "" {
+		if query.RadiusKm, err = floatParam(c, "radius_km"); err != nil {
+			return err
+		}
+	}
+	if query.RadiusKm <= 0 || query.RadiusKm > maxRadiusKm {
+		return fmt.Errorf("radius_km must be greater than 0 and at most %d", maxRadiusKm)
+	}
+	return nil
+}
+
+// intParam parses an optional integer query param (0 when missing)
+func intParam(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return parsed, nil
+}
+
+// floatParam parses an optional decimal query param (0 when missing). NaN and ±Inf are rejected:
+// they pass every range check and would end up in the Solr filters.
+func floatParam(c *gin.Context, name string) (float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return 0, fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return parsed, nil
 }
@@ -0,0 +1,108 @@
+package controllers_search_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	controllers "search/controllers_search"
+	"search/domain_search"
+)
+
+// fakeSearchService answers with result and remembers the parsed query
+type fakeSearchService struct {
+	result domain_search.SearchResult
+	query  *domain_search.SearchQuery
+}
+
+func (f fakeSearchService) Search(ctx context.Context, query domain_search.SearchQuery) (domain_search.SearchResult, error) {
+	*f.query = query
+	return f.result, nil
+}
+
+func (f fakeSearchService) Suggest(ctx context.Context, prefix string, limit int) domain_search.SuggestionsDto {
+	return domain_search.SuggestionsDto{}
+}
+
+func search(t *testing.T, service fakeSearchService, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/search", controllers.NewController(service).Search)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("Search - Rejects NaN And Infinity", func(t *testing.T) {
+		targets := []string{
+			"/search?min_price=NaN",
+			"/search?max_price=nan",
+			"/search?min_price=-Inf",
+			"/search?max_price=%2BInf",
+			"/search?max_price=Infinity",
+			"/search?lat=NaN&lon=-64.18",
+			"/search?lat=-31.42&lon=Inf",
+			"/search?lat=-31.42&lon=-64.18&radius_km=NaN",
+			"/search?lat=-31.42&lon=-64.18&radius_km=-Infinity",
+		}
+		for _, target := range targets {
+			query := domain_search.SearchQuery{}
+			recorder := search(t, fakeSearchService{query: &query}, target)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, target)
+			assert.Contains(t, recorder.Body.String(), "invalid request", target)
+		}
+	})
+
+	t.Run("Search - Parses Prices And Geo", func(t *testing.T) {
+		query := domain_search.SearchQuery{}
+		recorder := search(t, fakeSearchService{query: &query}, "/search?min_price=100.5&max_price=2000&lat=-31.42&lon=-64.18&radius_km=2.5")
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, 100.5, query.MinPrice)
+		assert.Equal(t, 2000.0, query.MaxPrice)
+		require.NotNil(t, query.Near)
+		assert.Equal(t, domain_search.GeoPoint{Lat: -31.42, Lon: -64.18}, *query.Near)
+		assert.Equal(t, 2.5, query.RadiusKm)
+	})
+
+	t.Run("Search - Out Of Range Coordinates", func(t *testing.T) {
+		query := domain_search.SearchQuery{}
+		recorder := search(t, fakeSearchService{query: &query}, "/search?lat=91&lon=0")
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Search - Cursor Link Follows Solr Even On A Short Page", func(t *testing.T) {
+		query := domain_search.SearchQuery{}
+		service := fakeSearchService{query: &query, result: domain_search.SearchResult{
+			Total:      30,
+			NextCursor: "AoE/aDE=",
+			Hotels:     domain_search.HotelsDto{{ID: "h1"}},
+		}}
+		recorder := search(t, service, "/search?cursor=*&limit=10")
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var body domain_search.SearchResult
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, "/search?cursor=AoE%2FaDE%3D&limit=10", body.Next)
+	})
+
+	t.Run("Search - No Cursor Link On The Last Page", func(t *testing.T) {
+		query := domain_search.SearchQuery{}
+		service := fakeSearchService{query: &query, result: domain_search.SearchResult{Total: 1, Hotels: domain_search.HotelsDto{{ID: "h1"}}}}
+		recorder := search(t, service, "/search?cursor=AoE%2FaDE%3D&limit=10")
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), `"next"`)
+	})
+}
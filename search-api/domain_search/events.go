@@ -0,0 +1,23 @@
+package domain_search
+
+import "time"
+
+// EventVersion is the version of the event contract shared with hotels-api
+// (see domain_hotels.HotelEvent). Both services must bump it together. Both test
+// against the example events in contracts/hotel-events.v1.json.
+const EventVersion = 1
+
+// Event types published by hotels-api on the "hotels-news" queue
+const (
+	EventHotelCreated = "hotel.created"
+	EventHotelUpdated = "hotel.updated"
+	EventHotelDeleted = "hotel.deleted"
+)
+
+type HotelEvent struct {
+	Type          string    `json:"type"`
+	EntityID      string    `json:"entity_id"`
+	Version       int       `json:"version"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	CorrelationID string    `json:"correlation_id"`
+}
@@ -0,0 +1,45 @@
+package domain_search_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"search/domain_search"
+)
+
+// hotels-api tests the same file, so changing HotelEvent on one side only breaks one of them
+const eventsContract = "../../contracts/hotel-events.v1.json"
+
+func TestHotelEventContract(t *testing.T) {
+	data, err := os.ReadFile(eventsContract)
+	require.NoError(t, err)
+	var fixtures []json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fixtures))
+	require.Len(t, fixtures, 3)
+
+	t.Run("HotelEvent - Round Trip", func(t *testing.T) {
+		types := make([]string, 0, len(fixtures))
+		for _, fixture := range fixtures {
+			decoder := json.NewDecoder(bytes.NewReader(fixture))
+			decoder.DisallowUnknownFields()
+			var event domain_search.HotelEvent
+			require.NoError(t, decoder.Decode(&event))
+
+			assert.Equal(t, domain_search.EventVersion, event.Version)
+			assert.NotEmpty(t, event.EntityID)
+			assert.NotEmpty(t, event.CorrelationID)
+			assert.False(t, event.OccurredAt.IsZero())
+			types = append(types, event.Type)
+
+			encoded, err := json.Marshal(event)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(fixture), string(encoded))
+		}
+		assert.Equal(t, []string{domain_search.EventHotelCreated, domain_search.EventHotelUpdated, domain_search.EventHotelDeleted}, types)
+	})
+}
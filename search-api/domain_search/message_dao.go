@@ -1,8 +1,28 @@
 package domain_search
 
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
 type QueueMessageDto struct {
 	Id      string `json:"id"`
 	Message string `json:"message"`
 }
 
 type QueueMessagesDto []QueueMessageDto
+
+// DeadLetterDto is a message that could not be processed and was moved to the dead-letter queue
+type DeadLetterDto struct {
+	ID             string          `json:"id"`
+	Error          string          `json:"error"`
+	Attempts       int             `json:"attempts"`
+	OriginalQueue  string          `json:"original_queue"`
+	DeadLetteredAt time.Time       `json:"dead_lettered_at"`
+	Body           json.RawMessage `json:"body"`
+}
+
+type DeadLettersDto []DeadLetterDto
+
+var ErrDeadLetterNotFound = errors.New("dead-lettered message not found")
@@ -1,24 +1,131 @@
 package domain_search
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
-type CourseDto struct {
-	Course_id    string    `json:"course_id"`
-	Nombre       string    `json:"nombre"`
-	Profesor_id  int       `json:"profesor_id"`
-	Categoria    string    `json:"categoria"`
-	Descripcion  string    `json:"descripcion"`
-	Valoracion   float64   `json:"valoracion"`
-	Duracion     int       `json:"duracion"`
-	Requisitos   string    `json:"requisitos"`
-	Url_image    string    `json:"url_image"`
-	Fecha_inicio time.Time `json:"fecha_inicio"`
-	Capacidad    int       `json:"capacidad"`
+// ErrHotelNotFound is returned when hotels-api has no hotel with the requested id
+var ErrHotelNotFound = errors.New("hotel not found")
+
+type HotelDto struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	City          string   `json:"city"`
+	PricePerNight float64  `json:"price_per_night"`
+	Stars         int      `json:"stars"`
+	Amenities     []string `json:"amenities"`
+	OwnerID       string   `json:"owner_id"`
+	Address       string   `json:"address,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	RatingAvg     float64  `json:"rating_avg"`
+	RatingCount   int      `json:"rating_count"`
+	MaxGuests     int      `json:"max_guests,omitempty"` // capacity of the largest room type, 0 when the hotel has none loaded
+	Archived      bool     `json:"archived,omitempty"`   // soft-deleted in hotels-api; never indexed
+
+	// Only set in geo searches: distance from the requested point
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+
+	// Only set when the search has dates: Available is nil when reservations-api did not answer in time.
+	// TotalPrice is the hotels-api quote for the stay (cheapest room type for the guests, with
+	// seasons, weekends and length of stay discounts) and is left empty if the quote failed.
+	Available  *bool   `json:"available,omitempty"`
+	Nights     int     `json:"nights,omitempty"`
+	TotalPrice float64 `json:"total_price,omitempty"`
+	Currency   string  `json:"currency,omitempty"`
 }
 
-type CoursesDto []CourseDto
+// Quote is the part of the hotels-api GET /hotels/:id/quote response the search needs
+type Quote struct {
+	Total    float64 `json:"total"`
+	Currency string  `json:"currency"`
+}
+
+type HotelsDto []HotelDto
+
+// Supported values for SearchQuery.Sort
+const (
+	SortRelevance = "relevance"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortStarsAsc  = "stars_asc"
+	SortStarsDesc = "stars_desc"
+	SortRating    = "rating"   // best rated first (average of approved reviews, then number of reviews)
+	SortDistance  = "distance" // only with a geo search; default when it has no free text
+)
+
+// GeoPoint is a latitude/longitude pair in degrees
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// SearchQuery holds the free-text query plus the filters, sorting and paging of a hotel search.
+// Zero values mean "no filter".
+type SearchQuery struct {
+	Query     string
+	City      string
+	MinStars  int
+	MaxStars  int
+	MinPrice  float64
+	MaxPrice  float64
+	Amenities []string
+	Sort      string
+	Offset    int
+	Limit     int
+	Cursor    string // Solr cursorMark for deep paging ("*" starts a new cursor); Offset is ignored when set
+	CheckIn   time.Time
+	CheckOut  time.Time
+	Guests    int
+	Near      *GeoPoint // geo search: only hotels within RadiusKm of this point
+	RadiusKm  float64
+
+	// Set by the service, not by the caller: hotels with no rooms left for the dates
+	ExcludeIDs []string
+}
 
-type CourseNew struct {
-	Operation string `json:"operation"`
-	Curso_id  string `json:"course_id"`
+// HasDates reports whether the search asks for availability
+func (query SearchQuery) HasDates() bool {
+	return !query.CheckIn.IsZero() && !query.CheckOut.IsZero()
 }
+
+// Nights is the length of the stay, 0 without dates
+func (query SearchQuery) Nights() int {
+	if !query.HasDates() {
+		return 0
+	}
+	return int(query.CheckOut.Sub(query.CheckIn).Hours() / 24)
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchResult is a page of hotels plus the paging metadata and facet counts by city, stars and amenity
+type SearchResult struct {
+	Total      int                     `json:"total"`
+	Offset     int                     `json:"offset"`
+	Limit      int                     `json:"limit"`
+	Next       string                  `json:"next,omitempty"`        // link to the following page, empty on the last one
+	NextCursor string                  `json:"next_cursor,omitempty"` // only in cursor mode
+	Hotels     HotelsDto               `json:"results"`
+	Facets     map[string][]FacetCount `json:"facets"`
+}
+
+// Types of autocomplete suggestions
+const (
+	SuggestionHotel = "hotel"
+	SuggestionCity  = "city"
+)
+
+// Suggestion is an autocomplete entry: a hotel (with its id) or a city (with how many hotels it has)
+type Suggestion struct {
+	Text  string `json:"text"`
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+type SuggestionsDto []Suggestion
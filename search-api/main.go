@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"os"
 	queues "search/clients_search"
 	controllers "search/controllers_search"
 	repositories "search/repositories_search"
@@ -15,28 +18,47 @@ import (
 func main() {
 	// Solr
 	solrRepo := repositories.NewSolr(repositories.SolrConfig{
-		Host:       "solr",    // Solr host
-		Port:       "8983",    // Solr port
-		Collection: "courses", // Collection name
+		Host:       "solr",   // Solr host
+		Port:       "8983",   // Solr port
+		Collection: "hotels", // Collection name
 	})
 
+	// hotels API
+	hotelsAPI := repositories.NewHTTP(repositories.HTTPConfig{
+		Host: "hotels-api",
+		Port: "8081",
+	})
+
+	// reservations API (disponibilidad por fechas)
+	reservationsAPI := repositories.NewReservations(repositories.ReservationsConfig{
+		Host:    "reservations-api",
+		Port:    "8086",
+		Timeout: 2 * time.Second,
+	})
+
+	// Subcomando: "search-api reindex [-batch-size N]" repuebla Solr y termina
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		runReindex(solrRepo, hotelsAPI, os.Args[2:])
+		return
+	}
+
 	// Rabbit
 	eventsQueue := queues.NewRabbit(queues.RabbitConfig{
 		Host:      "rabbitmq",
 		Port:      "5672",
 		Username:  "user",
 		Password:  "root",
-		QueueName: "courses-news",
-	})
+		QueueName: "hotels-news",
 
-	// courses API
-	hotelsAPI := repositories.NewHTTP(repositories.HTTPConfig{
-		Host: "hotels-api",
-		Port: "8081",
+		MaxAttempts:    5,
+		RetryBaseDelay: 2 * time.Second,
+		RetryMaxDelay:  time.Minute,
 	})
+	defer eventsQueue.Close()
 
 	// Crear instancia del servicio
-	service := services.NewService(solrRepo, hotelsAPI)
+	service := services.NewService(solrRepo, hotelsAPI, reservationsAPI)
+	reindexer := services.NewReindexer(solrRepo, hotelsAPI, 100)
 
 	// Iniciar el consumidor y pasarle el servicio
 	if err := eventsQueue.StartConsumer(service); err != nil {
@@ -49,7 +71,7 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Content-Type"},
+		AllowHeaders:     []string{"Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -57,7 +79,47 @@ func main() {
 
 	controller := controllers.NewController(service)
 	router.GET("/search", controller.Search)
+	router.GET("/search/suggest", controller.Suggest)
+
+	// Admin: solo usuarios admin (JWT de users-api, misma clave que hotels-api)
+	admin := router.Group("/admin", controllers.RequireAdmin(getEnv("JWT_KEY", "ThisIsAnExampleJWTKey!")))
+
+	deadLetters := controllers.NewDeadLettersController(eventsQueue)
+	admin.GET("/dead-letters", deadLetters.List)
+	admin.POST("/dead-letters/:id/replay", deadLetters.Replay)
+
+	reindex := controllers.NewReindexController(reindexer)
+	admin.POST("/reindex", reindex.Start)
+	admin.GET("/reindex", reindex.Status)
+
 	if err := router.Run(":8082"); err != nil {
 		log.Fatalf("Error running application: %v", err)
 	}
 }
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// runReindex corre el reindex completo desde la línea de comandos mostrando el progreso
+func runReindex(solrRepo repositories.Solr, hotelsAPI repositories.HTTP, args []string) {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	batchSize := flags.Int("batch-size", 100, "hotels fetched and indexed per batch (at most 100, the hotels-api page size)")
+	_ = flags.Parse(args)
+
+	reindexer := services.NewReindexer(solrRepo, hotelsAPI, *batchSize)
+	status, err := reindexer.Run(context.Background(), func(status services.ReindexStatus) {
+		log.Printf("reindex: pages=%d fetched=%d indexed=%d failed=%d deleted=%d",
+			status.Pages, status.Fetched, status.Indexed, status.Failed, status.Deleted)
+	})
+	if err != nil {
+		log.Fatalf("Error reindexing hotels: %v", err)
+	}
+	log.Printf("Reindex finished: %d indexed, %d failed, %d orphans deleted", status.Indexed, status.Failed, status.Deleted)
+	if status.Failed > 0 {
+		os.Exit(1)
+	}
+}
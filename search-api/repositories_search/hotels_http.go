@@ -0,0 +1,150 @@
+package repositories_search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	hotelsDomain "search/domain_search"
+	"strconv"
+	"time"
+)
+
+type HTTPConfig struct {
+	Host string
+	Port string
+}
+
+type HTTP struct {
+	baseURL  func(hotelID string) string
+	listURL  func(offset int, limit int) string
+	quoteURL func(hotelID string, checkIn time.Time, checkOut time.Time, guests int) string
+}
+
+// hotelResponse is a hotel as hotels-api returns it: the HotelDto fields plus the room types,
+// of which only the capacity is kept (as HotelDto.MaxGuests)
+type hotelResponse struct {
+	hotelsDomain.HotelDto
+	Rooms []struct {
+		Capacity int `json:"capacity"`
+	} `json:"rooms"`
+}
+
+func (response hotelResponse) hotel() hotelsDomain.HotelDto {
+	hotel := response.HotelDto
+	for _, room := range response.Rooms {
+		hotel.MaxGuests = max(hotel.MaxGuests, room.Capacity)
+	}
+	return hotel
+}
+
+func NewHTTP(config HTTPConfig) HTTP {
+	return HTTP{
+		baseURL: func(hotelID string) string {
+			return fmt.Sprintf("http://%s:%s/hotels/%s", config.Host, config.Port, hotelID)
+		},
+		listURL: func(offset int, limit int) string {
+			return fmt.Sprintf("http://%s:%s/hotels?offset=%d&limit=%d", config.Host, config.Port, offset, limit)
+		},
+		quoteURL: func(hotelID string, checkIn time.Time, checkOut time.Time, guests int) string {
+			params := url.Values{}
+			params.Set("check_in", checkIn.Format(time.DateOnly))
+			params.Set("check_out", checkOut.Format(time.DateOnly))
+			params.Set("guests", strconv.Itoa(guests))
+			params.Set("cheapest", "true")
+			return fmt.Sprintf("http://%s:%s/hotels/%s/quote?%s", config.Host, config.Port, url.PathEscape(hotelID), params.Encode())
+		},
+	}
+}
+
+func (repository HTTP) GetHotelByID(ctx context.Context, id string) (hotelsDomain.HotelDto, error) {
+	url := repository.baseURL(id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return hotelsDomain.HotelDto{}, fmt.Errorf("error building request for hotel (%s): %w", id, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return hotelsDomain.HotelDto{}, fmt.Errorf("error fetching hotel (%s): %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return hotelsDomain.HotelDto{}, fmt.Errorf("%w: %s", hotelsDomain.ErrHotelNotFound, id)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return hotelsDomain.HotelDto{}, fmt.Errorf("failed to fetch hotel (%s): received status code %d", id, resp.StatusCode)
+	}
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return hotelsDomain.HotelDto{}, fmt.Errorf("error reading response body for hotel (%s): %w", id, err)
+	}
+
+	// Unmarshal the hotel details into the hotel struct
+	var hotel hotelResponse
+	if err := json.Unmarshal(body, &hotel); err != nil {
+		return hotelsDomain.HotelDto{}, fmt.Errorf("error unmarshaling hotel data (%s): %w", id, err)
+	}
+
+	return hotel.hotel(), nil
+}
+
+// ListHotels fetches a page of hotels from hotels-api GET /hotels
+func (repository HTTP) ListHotels(ctx context.Context, offset int, limit int) ([]hotelsDomain.HotelDto, error) {
+	url := repository.listURL(offset, limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request for hotels page (offset %d): %w", offset, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching hotels page (offset %d): %w", offset, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch hotels page (offset %d): received status code %d", offset, resp.StatusCode)
+	}
+
+	// hotels-api devuelve { items, total, limit, offset }
+	var page struct {
+		Items []hotelResponse `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("error unmarshaling hotels page (offset %d): %w", offset, err)
+	}
+
+	hotels := make([]hotelsDomain.HotelDto, 0, len(page.Items))
+	for _, item := range page.Items {
+		hotels = append(hotels, item.hotel())
+	}
+	return hotels, nil
+}
+
+// Quote prices a stay with hotels-api GET /hotels/:id/quote, in the cheapest room type for the guests
+func (repository HTTP) Quote(ctx context.Context, hotelID string, checkIn time.Time, checkOut time.Time, guests int) (hotelsDomain.Quote, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, repository.quoteURL(hotelID, checkIn, checkOut, guests), nil)
+	if err != nil {
+		return hotelsDomain.Quote{}, fmt.Errorf("error building quote request for hotel (%s): %w", hotelID, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return hotelsDomain.Quote{}, fmt.Errorf("error fetching quote for hotel (%s): %w", hotelID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return hotelsDomain.Quote{}, fmt.Errorf("failed to fetch quote for hotel (%s): received status code %d: %s", hotelID, resp.StatusCode, body)
+	}
+
+	var quote hotelsDomain.Quote
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		return hotelsDomain.Quote{}, fmt.Errorf("error unmarshaling quote for hotel (%s): %w", hotelID, err)
+	}
+	return quote, nil
+}
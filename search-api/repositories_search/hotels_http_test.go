@@ -0,0 +1,169 @@
+package repositories_search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"search/domain_search"
+	repositories "search/repositories_search"
+)
+
+// newHotelsAPI starts handler as hotels-api and returns the HTTP client pointed at it
+func newHotelsAPI(t *testing.T, handler http.HandlerFunc) repositories.HTTP {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	address, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(address.Host)
+	require.NoError(t, err)
+	return repositories.NewHTTP(repositories.HTTPConfig{Host: host, Port: port})
+}
+
+func TestHotelsHTTP(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetHotelByID - Hotel With Room Types", func(t *testing.T) {
+		var path string
+		hotelsAPI := newHotelsAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			fmt.Fprint(w, `{"id":"h1","name":"Sheraton","city":"Córdoba","price_per_night":1000,"stars":4,
+				"owner_id":"7","archived":true,"rooms":[{"capacity":2,"units":5},{"capacity":4,"units":1}]}`)
+		})
+
+		hotel, err := hotelsAPI.GetHotelByID(ctx, "h1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "/hotels/h1", path)
+		assert.Equal(t, domain_search.HotelDto{
+			ID: "h1", Name: "Sheraton", City: "Córdoba", PricePerNight: 1000, Stars: 4, OwnerID: "7", Archived: true, MaxGuests: 4,
+		}, hotel)
+	})
+
+	t.Run("GetHotelByID - Status Mapping", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			status   int
+			body     string
+			notFound bool
+			contains string
+		}{
+			{"not found", http.StatusNotFound, "hotel not found", true, "h1"},
+			{"server error", http.StatusInternalServerError, "boom", false, "received status code 500"},
+			{"invalid json", http.StatusOK, "{", false, "error unmarshaling hotel data"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				hotelsAPI := newHotelsAPI(t, func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, tt.body)
+				})
+
+				_, err := hotelsAPI.GetHotelByID(ctx, "h1")
+
+				require.Error(t, err)
+				assert.Equal(t, tt.notFound, errors.Is(err, domain_search.ErrHotelNotFound))
+				assert.Contains(t, err.Error(), tt.contains)
+			})
+		}
+	})
+
+	t.Run("ListHotels - Pages Through The Catalog", func(t *testing.T) {
+		catalog := make([]map[string]any, 0, 25)
+		for i := 0; i < 25; i++ {
+			catalog = append(catalog, map[string]any{"id": fmt.Sprintf("h%02d", i), "rooms": []map[string]int{{"capacity": i%4 + 1}}})
+		}
+		requests := make([]string, 0)
+		hotelsAPI := newHotelsAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RequestURI())
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			start, end := min(offset, len(catalog)), min(offset+limit, len(catalog))
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": catalog[start:end], "total": len(catalog), "limit": limit, "offset": offset})
+		})
+
+		ids := make([]string, 0)
+		for offset := 0; ; offset += 10 {
+			page, err := hotelsAPI.ListHotels(ctx, offset, 10)
+			require.NoError(t, err)
+			for _, hotel := range page {
+				assert.Equal(t, len(ids)%4+1, hotel.MaxGuests, hotel.ID)
+				ids = append(ids, hotel.ID)
+			}
+			if len(page) < 10 {
+				break
+			}
+		}
+
+		assert.Equal(t, []string{"/hotels?offset=0&limit=10", "/hotels?offset=10&limit=10", "/hotels?offset=20&limit=10"}, requests)
+		require.Len(t, ids, 25)
+		assert.Equal(t, "h00", ids[0])
+		assert.Equal(t, "h24", ids[24])
+	})
+
+	t.Run("ListHotels - Error Status", func(t *testing.T) {
+		hotelsAPI := newHotelsAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "limit must be at most 100", http.StatusBadRequest)
+		})
+
+		_, err := hotelsAPI.ListHotels(ctx, 0, 500)
+
+		assert.ErrorContains(t, err, "failed to fetch hotels page (offset 0): received status code 400")
+	})
+
+	t.Run("Quote - Request And Status Mapping", func(t *testing.T) {
+		checkIn := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+		tests := []struct {
+			name     string
+			status   int
+			body     string
+			want     domain_search.Quote
+			contains string
+		}{
+			{"quoted", http.StatusOK, `{"total":2160,"currency":"ARS","nights":2}`, domain_search.Quote{Total: 2160, Currency: "ARS"}, ""},
+			{"invalid stay", http.StatusBadRequest, "minimum stay is 3 nights", domain_search.Quote{}, "received status code 400: minimum stay is 3 nights"},
+			{"unknown hotel", http.StatusNotFound, "hotel not found", domain_search.Quote{}, "received status code 404: hotel not found"},
+			{"server error", http.StatusInternalServerError, strings.Repeat("x", 5000), domain_search.Quote{}, "received status code 500"},
+			{"invalid json", http.StatusOK, "{", domain_search.Quote{}, "error unmarshaling quote"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var query url.Values
+				var path string
+				hotelsAPI := newHotelsAPI(t, func(w http.ResponseWriter, r *http.Request) {
+					path, query = r.URL.Path, r.URL.Query()
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, tt.body)
+				})
+
+				quote, err := hotelsAPI.Quote(ctx, "h 1", checkIn, checkIn.AddDate(0, 0, 2), 3)
+
+				assert.Equal(t, "/hotels/h 1/quote", path)
+				assert.Equal(t, url.Values{
+					"check_in": {"2025-11-20"}, "check_out": {"2025-11-22"}, "guests": {"3"}, "cheapest": {"true"},
+				}, query)
+				if tt.contains == "" {
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, quote)
+					return
+				}
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.contains)
+				assert.Less(t, len(err.Error()), 1200) // the body is cut at 1KB
+			})
+		}
+	})
+}
@@ -0,0 +1,452 @@
+package repositories_search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stevenferrer/solr-go"
+	"net/http"
+	"net/url"
+	hotelsDomain "search/domain_search"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SolrConfig struct {
+	Host       string // Solr host
+	Port       string // Solr port
+	Collection string // Solr collection name
+}
+
+type Solr struct {
+	Client     *solr.JSONClient
+	HTTPClient *http.Client
+	BaseURL    string
+	Collection string
+}
+
+// NewSolr initializes a new Solr client
+func NewSolr(config SolrConfig) Solr {
+	// Construct the BaseURL using the provided host and port
+	baseURL := fmt.Sprintf("http://%s:%s", config.Host, config.Port)
+	client := solr.NewJSONClient(baseURL)
+
+	return Solr{
+		Client:     client,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		BaseURL:    baseURL,
+		Collection: config.Collection,
+	}
+}
+
+// hotelDocument maps a hotel to the Solr document stored in the collection
+func hotelDocument(hotel hotelsDomain.HotelDto) map[string]interface{} {
+	doc := map[string]interface{}{
+		"id":              hotel.ID,
+		"name":            hotel.Name,
+		"city":            hotel.City,
+		"price_per_night": hotel.PricePerNight,
+		"stars":           hotel.Stars,
+		"amenities":       hotel.Amenities,
+		"owner_id":        hotel.OwnerID,
+		"address":         hotel.Address,
+		"rating_avg":      hotel.RatingAvg,
+		"rating_count":    hotel.RatingCount,
+	}
+	if hotel.MaxGuests > 0 {
+		doc["max_guests"] = hotel.MaxGuests
+	}
+	if hotel.Latitude != nil && hotel.Longitude != nil {
+		doc["location"] = fmt.Sprintf("%s,%s", formatCoordinate(*hotel.Latitude), formatCoordinate(*hotel.Longitude))
+	}
+	return doc
+}
+
+// Index adds a new hotel document to the Solr collection
+func (searchEngine Solr) Index(ctx context.Context, hotel hotelsDomain.HotelDto) (string, error) {
+	// Prepare the index request
+	indexRequest := map[string]interface{}{
+		"add": []interface{}{hotelDocument(hotel)}, // Use "add" with a list of documents
+	}
+
+	// Index the document in Solr
+	body, err := json.Marshal(indexRequest)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling hotel document: %w", err)
+	}
+
+	// Index the document in Solr
+	resp, err := searchEngine.Client.Update(ctx, searchEngine.Collection, solr.JSON, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("error indexing hotel: %w", err)
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("failed to index hotel: %v", resp.Error)
+	}
+
+	// Commit the changes
+	if err := searchEngine.Client.Commit(ctx, searchEngine.Collection); err != nil {
+		return "", fmt.Errorf("error committing changes to Solr: %w", err)
+	}
+
+	return hotel.ID, nil
+}
+
+// Update modifies an existing hotel document in the Solr collection
+func (searchEngine Solr) Update(ctx context.Context, hotel hotelsDomain.HotelDto) error {
+	// Prepare the update request
+	updateRequest := map[string]interface{}{
+		"add": []interface{}{hotelDocument(hotel)}, // Use "add" with a list of documents
+	}
+
+	// Update the document in Solr
+	body, err := json.Marshal(updateRequest)
+	if err != nil {
+		return fmt.Errorf("error marshaling hotel document: %w", err)
+	}
+
+	// Execute the update request using the Update method
+	resp, err := searchEngine.Client.Update(ctx, searchEngine.Collection, solr.JSON, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error updating hotel: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("failed to update hotel: %v", resp.Error)
+	}
+
+	// Commit the changes
+	if err := searchEngine.Client.Commit(ctx, searchEngine.Collection); err != nil {
+		return fmt.Errorf("error committing changes to Solr: %w", err)
+	}
+
+	return nil
+}
+
+func (searchEngine Solr) Delete(ctx context.Context, id string) error {
+	// Prepare the delete request
+	docToDelete := map[string]interface{}{
+		"delete": map[string]interface{}{
+			"id": id,
+		},
+	}
+
+	// Update the document in Solr
+	body, err := json.Marshal(docToDelete)
+	if err != nil {
+		return fmt.Errorf("error marshaling hotel document: %w", err)
+	}
+
+	// Execute the delete request using the Update method
+	resp, err := searchEngine.Client.Update(ctx, searchEngine.Collection, solr.JSON, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error deleting hotel: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("failed to delete hotel: %v", resp.Error)
+	}
+
+	// Commit the changes
+	if err := searchEngine.Client.Commit(ctx, searchEngine.Collection); err != nil {
+		return fmt.Errorf("error committing changes to Solr: %w", err)
+	}
+
+	return nil
+}
+
+// IndexBatch adds or replaces several hotel documents with a single request and commit
+func (searchEngine Solr) IndexBatch(ctx context.Context, hotels []hotelsDomain.HotelDto) error {
+	if len(hotels) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(hotels))
+	for _, hotel := range hotels {
+		docs = append(docs, hotelDocument(hotel))
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"add": docs,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling hotel documents: %w", err)
+	}
+
+	resp, err := searchEngine.Client.Update(ctx, searchEngine.Collection, solr.JSON, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error indexing hotels: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("failed to index hotels: %v", resp.Error)
+	}
+
+	if err := searchEngine.Client.Commit(ctx, searchEngine.Collection); err != nil {
+		return fmt.Errorf("error committing changes to Solr: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBatch removes several hotel documents with a single request and commit
+func (searchEngine Solr) DeleteBatch(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"delete": ids,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling delete request: %w", err)
+	}
+
+	resp, err := searchEngine.Client.Update(ctx, searchEngine.Collection, solr.JSON, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error deleting hotels: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("failed to delete hotels: %v", resp.Error)
+	}
+
+	if err := searchEngine.Client.Commit(ctx, searchEngine.Collection); err != nil {
+		return fmt.Errorf("error committing changes to Solr: %w", err)
+	}
+
+	return nil
+}
+
+// ListIDs returns the ids of every hotel currently in the index
+func (searchEngine Solr) ListIDs(ctx context.Context) ([]string, error) {
+	const pageSize = 1000
+
+	ids := make([]string, 0)
+	for offset := 0; ; offset += pageSize {
+		query := solr.NewQuery("*:*").Fields("id").Sort("id asc").Offset(offset).Limit(pageSize)
+		resp, err := searchEngine.Client.Query(ctx, searchEngine.Collection, query)
+		if err != nil {
+			return nil, fmt.Errorf("error listing indexed hotels: %w", err)
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("failed to list indexed hotels: %v", resp.Error)
+		}
+
+		for _, doc := range resp.Response.Documents {
+			ids = append(ids, getStringField(doc, "id"))
+		}
+		if len(resp.Response.Documents) < pageSize {
+			return ids, nil
+		}
+	}
+}
+
+func (searchEngine Solr) Search(ctx context.Context, query hotelsDomain.SearchQuery) (hotelsDomain.SearchResult, error) {
+	resp, err := searchEngine.selectQuery(ctx, BuildSearchParams(query))
+	if err != nil {
+		return hotelsDomain.SearchResult{}, err
+	}
+
+	// Parse the response and extract hotel documents
+	result := hotelsDomain.SearchResult{
+		Total:  resp.Response.NumFound,
+		Hotels: make(hotelsDomain.HotelsDto, 0, len(resp.Response.Docs)),
+		Facets: make(map[string][]hotelsDomain.FacetCount, len(searchFacets)),
+	}
+	// Solr returns the same cursor once there is nothing left
+	if query.Cursor != "" && resp.NextCursorMark != query.Cursor {
+		result.NextCursor = resp.NextCursorMark
+	}
+	for _, doc := range resp.Response.Docs {
+		// Safely extract hotel fields with type assertions
+		hotel := hotelsDomain.HotelDto{
+			ID:            getStringField(doc, "id"),
+			Name:          getStringField(doc, "name"),
+			City:          getStringField(doc, "city"),
+			PricePerNight: getFloatField(doc, "price_per_night"),
+			Stars:         getIntField(doc, "stars"),
+			Amenities:     getStringsField(doc, "amenities"),
+			OwnerID:       getStringField(doc, "owner_id"),
+			Address:       getStringField(doc, "address"),
+			RatingAvg:     getFloatField(doc, "rating_avg"),
+			RatingCount:   getIntField(doc, "rating_count"),
+			MaxGuests:     getIntField(doc, "max_guests"),
+		}
+		hotel.Latitude, hotel.Longitude = getLocationField(doc, "location")
+		if distance, ok := doc["distance"].(float64); ok {
+			hotel.DistanceKm = &distance
+		}
+		result.Hotels = append(result.Hotels, hotel)
+	}
+	for _, facet := range searchFacets {
+		result.Facets[facet.Name] = facetCounts(resp.FacetCounts.FacetFields[facet.Field])
+	}
+	return result, nil
+}
+
+// Suggest returns up to limit cities (ranked by number of hotels) followed by hotels whose
+// name starts with the typed words. Both queries run concurrently under the caller's context.
+func (searchEngine Solr) Suggest(ctx context.Context, prefix string, limit int) (hotelsDomain.SuggestionsDto, error) {
+	type answer struct {
+		resp selectResponse
+		err  error
+	}
+	cities := make(chan answer, 1)
+	go func() {
+		resp, err := searchEngine.selectQuery(ctx, BuildCitySuggestParams(prefix, limit))
+		cities <- answer{resp: resp, err: err}
+	}()
+
+	hotels, err := searchEngine.selectQuery(ctx, BuildSuggestParams(prefix, "name_prefix", limit))
+	if err != nil {
+		return nil, fmt.Errorf("error suggesting hotels: %w", err)
+	}
+	cityAnswer := <-cities
+	if cityAnswer.err != nil {
+		return nil, fmt.Errorf("error suggesting cities: %w", cityAnswer.err)
+	}
+
+	// Cities go first but leave at least half of the list to hotels when there are enough of them
+	cityCounts := facetCounts(cityAnswer.resp.FacetCounts.FacetFields["city_exact"])
+	cityCounts = cityCounts[:min(len(cityCounts), limit-min(len(hotels.Response.Docs), limit/2))]
+
+	suggestions := make(hotelsDomain.SuggestionsDto, 0, limit)
+	for _, city := range cityCounts {
+		suggestions = append(suggestions, hotelsDomain.Suggestion{
+			Text:  city.Value,
+			Type:  hotelsDomain.SuggestionCity,
+			Count: city.Count,
+		})
+	}
+	for _, doc := range hotels.Response.Docs {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, hotelsDomain.Suggestion{
+			Text: getStringField(doc, "name"),
+			Type: hotelsDomain.SuggestionHotel,
+			ID:   getStringField(doc, "id"),
+		})
+	}
+	return suggestions, nil
+}
+
+// selectResponse is the part of the /select JSON response we use
+type selectResponse struct {
+	Response struct {
+		NumFound int                      `json:"numFound"`
+		Docs     []map[string]interface{} `json:"docs"`
+	} `json:"response"`
+	FacetCounts struct {
+		FacetFields map[string][]interface{} `json:"facet_fields"`
+	} `json:"facet_counts"`
+	NextCursorMark string              `json:"nextCursorMark"`
+	Error          *solr.ResponseError `json:"error,omitempty"`
+}
+
+// selectQuery runs a query against the /select handler with the given (form encoded) parameters
+func (searchEngine Solr) selectQuery(ctx context.Context, params url.Values) (selectResponse, error) {
+	params.Set("wt", "json")
+	endpoint := fmt.Sprintf("%s/solr/%s/select", searchEngine.BaseURL, searchEngine.Collection)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return selectResponse{}, fmt.Errorf("error building search query: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpResp, err := searchEngine.HTTPClient.Do(req)
+	if err != nil {
+		return selectResponse{}, fmt.Errorf("error executing search query: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	var resp selectResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return selectResponse{}, fmt.Errorf("error decoding search response (status %d): %w", httpResp.StatusCode, err)
+	}
+	if resp.Error != nil {
+		return selectResponse{}, fmt.Errorf("failed to execute search query: %v", resp.Error)
+	}
+	return resp, nil
+}
+
+// facetCounts converts Solr's flat [value, count, value, count...] list
+func facetCounts(flat []interface{}) []hotelsDomain.FacetCount {
+	counts := make([]hotelsDomain.FacetCount, 0, len(flat)/2)
+	for i := 0; i+1 < len(flat); i += 2 {
+		count, _ := flat[i+1].(float64)
+		counts = append(counts, hotelsDomain.FacetCount{
+			Value: fmt.Sprint(flat[i]),
+			Count: int(count),
+		})
+	}
+	return counts
+}
+
+// Helper function to safely get string fields from the document
+func getStringField(doc map[string]interface{}, field string) string {
+	if val, ok := doc[field].(string); ok {
+		return val
+	}
+	if val, ok := doc[field].([]interface{}); ok && len(val) > 0 {
+		if strVal, ok := val[0].(string); ok {
+			return strVal
+		}
+	}
+	return ""
+}
+
+// Helper function to safely get multi-valued string fields from the document
+func getStringsField(doc map[string]interface{}, field string) []string {
+	values := make([]string, 0)
+	if val, ok := doc[field].(string); ok {
+		return append(values, val)
+	}
+	if val, ok := doc[field].([]interface{}); ok {
+		for _, item := range val {
+			if strVal, ok := item.(string); ok {
+				values = append(values, strVal)
+			}
+		}
+	}
+	return values
+}
+
+// Helper function to safely get float64 fields from the document
+func getFloatField(doc map[string]interface{}, field string) float64 {
+	if val, ok := doc[field].(float64); ok {
+		return val
+	}
+	if val, ok := doc[field].([]interface{}); ok && len(val) > 0 {
+		if floatVal, ok := val[0].(float64); ok {
+			return floatVal
+		}
+	}
+	return 0.0
+}
+
+// getLocationField parses a LatLonPointSpatialField stored as "lat,lon"
+func getLocationField(doc map[string]interface{}, field string) (*float64, *float64) {
+	parts := strings.Split(getStringField(doc, field), ",")
+	if len(parts) != 2 {
+		return nil, nil
+	}
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lon, lonErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if latErr != nil || lonErr != nil {
+		return nil, nil
+	}
+	return &lat, &lon
+}
+
+func getIntField(doc map[string]interface{}, field string) int {
+	if val, ok := doc[field].(float64); ok {
+		return int(val)
+	}
+	if val, ok := doc[field].([]interface{}); ok && len(val) > 0 {
+		if floatVal, ok := val[0].(float64); ok {
+			return int(floatVal)
+		}
+	}
+	return 0
+}
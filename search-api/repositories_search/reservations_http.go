@@ -0,0 +1,71 @@
+package repositories_search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const defaultAvailabilityTimeout = 2 * time.Second
+
+type ReservationsConfig struct {
+	Host    string
+	Port    string
+	Timeout time.Duration // per request (default 2s)
+}
+
+// Reservations asks reservations-api which hotels are fully booked for a date range
+type Reservations struct {
+	unavailableURL string
+	timeout        time.Duration
+	client         *http.Client
+}
+
+func NewReservations(config ReservationsConfig) Reservations {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultAvailabilityTimeout
+	}
+	return Reservations{
+		unavailableURL: fmt.Sprintf("http://%s:%s/reservations/unavailable", config.Host, config.Port),
+		timeout:        config.Timeout,
+		client:         &http.Client{},
+	}
+}
+
+// Unavailable queries GET /reservations/unavailable: the ids of the hotels with no room left for
+// the guests between checkIn and checkOut
+func (repository Reservations) Unavailable(ctx context.Context, checkIn time.Time, checkOut time.Time, guests int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, repository.timeout)
+	defer cancel()
+
+	params := url.Values{}
+	params.Set("check_in", checkIn.Format(time.DateOnly))
+	params.Set("check_out", checkOut.Format(time.DateOnly))
+	params.Set("guests", strconv.Itoa(guests))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, repository.unavailableURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building availability request: %w", err)
+	}
+	resp, err := repository.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching availability: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch availability: received status code %d", resp.StatusCode)
+	}
+
+	var body struct {
+		HotelIDs []string `json:"hotel_ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error unmarshaling availability: %w", err)
+	}
+	return body.HotelIDs, nil
+}
@@ -5,11 +5,11 @@ import (
 )
 
 type Mock struct {
-	data map[int64]dao_search.Search
+	data map[string]dao_search.Search
 }
 
 func NewMock() Mock {
 	return Mock{
-		data: make(map[int64]dao_search.Search),
+		data: make(map[string]dao_search.Search),
 	}
 }
@@ -0,0 +1,242 @@
+package repositories_search
+
+import (
+	"net/url"
+	hotelsDomain "search/domain_search"
+	"strconv"
+	"strings"
+)
+
+// Fields searched by the free-text query, with their boosts
+const (
+	searchQueryFields  = "name^3 city^2 amenities_text"
+	searchPhraseFields = "name^5 city^2"
+)
+
+// searchFacet is a facet returned with every search (response key -> Solr field)
+type searchFacet struct {
+	Name  string
+	Field string
+}
+
+var searchFacets = []searchFacet{
+	{Name: "city", Field: "city_exact"},
+	{Name: "stars", Field: "stars"},
+	{Name: "amenities", Field: "amenities"},
+}
+
+var searchSorts = map[string]string{
+	hotelsDomain.SortPriceAsc:  "price_per_night asc",
+	hotelsDomain.SortPriceDesc: "price_per_night desc",
+	hotelsDomain.SortStarsAsc:  "stars asc",
+	hotelsDomain.SortStarsDesc: "stars desc",
+	hotelsDomain.SortRating:    "rating_avg desc,rating_count desc",
+}
+
+// Boolean operators are lowercased so a user typing them gets plain terms
+var queryOperators = map[string]bool{
+	"AND": true,
+	"OR":  true,
+	"NOT": true,
+	"TO":  true,
+}
+
+// BuildSearchParams translates a search into /select parameters. Every user supplied value is
+// escaped, so it can never change the query structure or add Solr parameters of its own.
+func BuildSearchParams(query hotelsDomain.SearchQuery) url.Values {
+	params := url.Values{}
+
+	// Free text goes through edismax across name, city and amenities; no text means everything
+	params.Set("defType", "edismax")
+	params.Set("qf", searchQueryFields)
+	params.Set("pf", searchPhraseFields)
+	params.Set("q.op", "AND")
+	params.Set("lowercaseOperators", "false")
+	params.Set("uf", "-*") // users cannot target fields with "field:value"
+	params.Set("q.alt", "*:*")
+	if q := UserQuery(query.Query); q != "" {
+		params.Set("q", q)
+	}
+	params.Set("rows", strconv.Itoa(query.Limit))
+	if query.Cursor != "" {
+		// Deep paging: Solr requires start=0 and a sort ending on the unique key
+		params.Set("cursorMark", query.Cursor)
+		params.Set("start", "0")
+	} else {
+		params.Set("start", strconv.Itoa(query.Offset))
+	}
+
+	// Filters
+	if city := strings.TrimSpace(query.City); city != "" {
+		// city is tokenized for free text; the filter matches the whole value, like the city facet
+		params.Add("fq", "city_exact:"+Phrase(city))
+	}
+	if query.MinStars > 0 || query.MaxStars > 0 {
+		params.Add("fq", "stars:"+rangeFilter(float64(query.MinStars), float64(query.MaxStars)))
+	}
+	if query.MinPrice > 0 || query.MaxPrice > 0 {
+		params.Add("fq", "price_per_night:"+rangeFilter(query.MinPrice, query.MaxPrice))
+	}
+	for _, amenity := range query.Amenities {
+		if amenity = strings.TrimSpace(amenity); amenity != "" {
+			params.Add("fq", "amenities:"+Phrase(amenity))
+		}
+	}
+
+	// Availability: a room type big enough for the guests (hotels without rooms loaded have no
+	// max_guests and take any group) and not fully booked. The ids go in their own parameter
+	// through the terms parser, which has no boolean clause limit.
+	if query.Guests > 0 {
+		params.Add("fq", "max_guests:["+strconv.Itoa(query.Guests)+" TO *] OR (*:* -max_guests:[* TO *])")
+	}
+	if len(query.ExcludeIDs) > 0 {
+		params.Add("fq", "-{!terms f=id v=$excluded_ids}")
+		params.Set("excluded_ids", strings.Join(query.ExcludeIDs, ","))
+	}
+
+	// Geo: only hotels within the radius, with their distance (km) in the "distance" pseudo field
+	if query.Near != nil {
+		params.Set("sfield", "location")
+		params.Set("pt", formatCoordinate(query.Near.Lat)+","+formatCoordinate(query.Near.Lon))
+		params.Set("d", strconv.FormatFloat(query.RadiusKm, 'f', -1, 64))
+		params.Add("fq", "{!geofilt}")
+		params.Set("fl", "*,distance:geodist()")
+	}
+
+	// Sorting (relevance is Solr's default order); id breaks ties so pages are stable
+	if sortsByDistance(query) {
+		params.Set("sort", "geodist() asc,id asc")
+	} else if sort, ok := searchSorts[query.Sort]; ok {
+		params.Set("sort", sort+",id asc")
+	} else if query.Cursor != "" {
+		params.Set("sort", "score desc,id asc")
+	}
+
+	// Facets
+	params.Set("facet", "true")
+	params.Set("facet.mincount", "1")
+	for _, facet := range searchFacets {
+		params.Add("facet.field", facet.Field)
+	}
+
+	return params
+}
+
+// BuildSuggestParams matches the words typed so far against the edge n-gram field (name_prefix
+// or city_prefix); the last word may be incomplete. Only ids and names are returned.
+func BuildSuggestParams(prefix string, field string, limit int) url.Values {
+	params := url.Values{}
+	params.Set("defType", "edismax")
+	params.Set("qf", field)
+	params.Set("q.op", "AND")
+	params.Set("lowercaseOperators", "false")
+	params.Set("uf", "-*")
+	params.Set("q", strings.Join(terms(prefix), " "))
+	params.Set("fl", "id,name,city")
+	params.Set("sort", "score desc,id asc")
+	params.Set("rows", strconv.Itoa(limit))
+	return params
+}
+
+// BuildCitySuggestParams counts hotels per matching city instead of returning documents
+func BuildCitySuggestParams(prefix string, limit int) url.Values {
+	params := BuildSuggestParams(prefix, "city_prefix", 0)
+	params.Del("fl")
+	params.Del("sort")
+	params.Set("facet", "true")
+	params.Set("facet.field", "city_exact")
+	params.Set("facet.mincount", "1")
+	params.Set("facet.limit", strconv.Itoa(limit))
+	return params
+}
+
+// sortsByDistance: geo searches default to nearest first unless there is text to rank by
+func sortsByDistance(query hotelsDomain.SearchQuery) bool {
+	if query.Near == nil {
+		return false
+	}
+	return query.Sort == hotelsDomain.SortDistance || (query.Sort == "" && UserQuery(query.Query) == "")
+}
+
+// formatCoordinate writes degrees with enough precision (~1cm) and no exponent
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 7, 64)
+}
+
+// UserQuery turns free text into a safe edismax query: "quoted text" is kept as a phrase,
+// every other word is escaped and searched as a term.
+func UserQuery(input string) string {
+	parts := make([]string, 0)
+
+	rest := input
+	for {
+		start := strings.Index(rest, `"`)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start+1:], `"`)
+		if end < 0 {
+			// Unbalanced quote: treat it as a regular character
+			break
+		}
+		parts = append(parts, terms(rest[:start])...)
+		if phrase := strings.TrimSpace(rest[start+1 : start+1+end]); phrase != "" {
+			parts = append(parts, Phrase(phrase))
+		}
+		rest = rest[start+1+end+1:]
+	}
+	parts = append(parts, terms(rest)...)
+
+	return strings.Join(parts, " ")
+}
+
+func terms(text string) []string {
+	words := strings.Fields(text)
+	result := make([]string, 0, len(words))
+	for _, word := range words {
+		if queryOperators[word] {
+			word = strings.ToLower(word)
+		}
+		if escaped := EscapeQueryChars(word); escaped != "" {
+			result = append(result, escaped)
+		}
+	}
+	return result
+}
+
+// Phrase quotes a value so it is matched as an exact phrase
+func Phrase(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// EscapeQueryChars backslash-escapes every character with a meaning in the Lucene query syntax
+// (same set as SolrJ's ClientUtils.escapeQueryChars)
+func EscapeQueryChars(value string) string {
+	var builder strings.Builder
+	for _, char := range value {
+		switch char {
+		case '\\', '+', '-', '!', '(', ')', ':', '^', '[', ']', '"', '{', '}', '~', '*', '?', '|', '&', ';', '/':
+			builder.WriteRune('\\')
+		default:
+			if char == ' ' || char == '\t' || char == '\n' || char == '\r' {
+				builder.WriteRune('\\')
+			}
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// rangeFilter formats a numeric range, 0 meaning open ended
+func rangeFilter(min float64, max float64) string {
+	return "[" + rangeBound(min) + " TO " + rangeBound(max) + "]"
+}
+
+func rangeBound(value float64) string {
+	if value <= 0 {
+		return "*"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,187 @@
+package repositories_search_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"search/domain_search"
+	repositories "search/repositories_search"
+)
+
+func TestEscapeQueryChars(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain word", input: "cordoba", expected: "cordoba"},
+		{name: "field syntax", input: "name:*", expected: `name\:\*`},
+		{name: "parameter injection", input: "x&rows=100000", expected: `x\&rows=100000`},
+		{name: "grouping", input: "(a)", expected: `\(a\)`},
+		{name: "local params", input: "{!xmlparser v=x}", expected: `\{\!xmlparser\ v=x\}`},
+		{name: "range", input: "[* TO *]", expected: `\[\*\ TO\ \*\]`},
+		{name: "boolean symbols", input: "a&&b||!c", expected: `a\&\&b\|\|\!c`},
+		{name: "backslash", input: `a\b`, expected: `a\\b`},
+		{name: "fuzzy and boost", input: "hotel~2^10", expected: `hotel\~2\^10`},
+		{name: "required and prohibited", input: "+a -b", expected: `\+a\ \-b`},
+		{name: "quote", input: `"a`, expected: `\"a`},
+		{name: "regex and wildcard", input: "/.*/ ?", expected: `\/.\*\/\ \?`},
+		{name: "accents are kept", input: "Córdoba", expected: "Córdoba"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, repositories.EscapeQueryChars(tt.input))
+		})
+	}
+}
+
+func TestUserQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "empty", input: "   ", expected: ""},
+		{name: "single word", input: "sol", expected: "sol"},
+		{name: "multi word", input: "  hotel   sol ", expected: "hotel sol"},
+		{name: "phrase", input: `"mar del plata"`, expected: `"mar del plata"`},
+		{name: "phrase and terms", input: `spa "mar del plata" wifi`, expected: `spa "mar del plata" wifi`},
+		{name: "unbalanced quote", input: `"mar del`, expected: `\"mar del`},
+		{name: "empty phrase", input: `"" sol`, expected: "sol"},
+		{name: "phrase with backslash", input: `"a\b"`, expected: `"a\\b"`},
+		{name: "operators become terms", input: "sol AND NOT luna OR TO", expected: "sol and not luna or to"},
+		{name: "field injection", input: "owner_id:u1", expected: `owner_id\:u1`},
+		{name: "parameter injection", input: "a&fq=*:*&rows=999", expected: `a\&fq=\*\:\*\&rows=999`},
+		{name: "local params injection", input: "{!lucene}*:*", expected: `\{\!lucene\}\*\:\*`},
+		{name: "unbalanced parenthesis", input: "sol)", expected: `sol\)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, repositories.UserQuery(tt.input))
+		})
+	}
+}
+
+func TestBuildSearchParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   domain_search.SearchQuery
+		q       string
+		filters []string
+		sort    string
+	}{
+		{
+			name:  "match all",
+			query: domain_search.SearchQuery{Limit: 10},
+		},
+		{
+			name:  "free text",
+			query: domain_search.SearchQuery{Query: "hotel sol", Limit: 10},
+			q:     "hotel sol",
+		},
+		{
+			name:    "city with quotes",
+			query:   domain_search.SearchQuery{City: `Cordoba" OR owner_id:"u1`, Limit: 10},
+			filters: []string{`city_exact:"Cordoba\" OR owner_id:\"u1"`},
+		},
+		{
+			name:    "ranges",
+			query:   domain_search.SearchQuery{MinStars: 3, MaxPrice: 75000.5, Limit: 10},
+			filters: []string{"stars:[3 TO *]", "price_per_night:[* TO 75000.5]"},
+		},
+		{
+			name:    "amenities",
+			query:   domain_search.SearchQuery{Amenities: []string{"wifi", " ", "spa) OR (*:*"}, Limit: 10},
+			filters: []string{`amenities:"wifi"`, `amenities:"spa) OR (*:*"`},
+		},
+		{
+			name:  "known sort",
+			query: domain_search.SearchQuery{Sort: domain_search.SortPriceDesc, Limit: 10},
+			sort:  "price_per_night desc,id asc",
+		},
+		{
+			name:  "rating sort breaks ties by review count",
+			query: domain_search.SearchQuery{Sort: domain_search.SortRating, Limit: 10},
+			sort:  "rating_avg desc,rating_count desc,id asc",
+		},
+		{
+			name:  "cursor defaults to relevance then id",
+			query: domain_search.SearchQuery{Cursor: "*", Limit: 10},
+			sort:  "score desc,id asc",
+		},
+		{
+			name:  "cursor keeps the requested sort",
+			query: domain_search.SearchQuery{Cursor: "AoE/aDE=", Sort: domain_search.SortStarsAsc, Limit: 10},
+			sort:  "stars asc,id asc",
+		},
+		{
+			name:    "geo defaults to nearest first",
+			query:   domain_search.SearchQuery{Near: &domain_search.GeoPoint{Lat: -31.4201, Lon: -64.1888}, RadiusKm: 5, Limit: 10},
+			filters: []string{"{!geofilt}"},
+			sort:    "geodist() asc,id asc",
+		},
+		{
+			name:    "geo with text keeps relevance",
+			query:   domain_search.SearchQuery{Query: "spa", Near: &domain_search.GeoPoint{Lat: 1, Lon: 2}, RadiusKm: 5, Limit: 10},
+			q:       "spa",
+			filters: []string{"{!geofilt}"},
+		},
+		{
+			name:    "booked hotels and capacity",
+			query:   domain_search.SearchQuery{Guests: 3, ExcludeIDs: []string{"h1", "h2"}, Limit: 10},
+			filters: []string{"max_guests:[3 TO *] OR (*:* -max_guests:[* TO *])", "-{!terms f=id v=$excluded_ids}"},
+		},
+		{
+			name:  "unknown sort is ignored",
+			query: domain_search.SearchQuery{Sort: "id desc&rows=1", Limit: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := repositories.BuildSearchParams(tt.query)
+
+			assert.Equal(t, "edismax", params.Get("defType"))
+			assert.Equal(t, "-*", params.Get("uf"))
+			assert.Equal(t, tt.q, params.Get("q"))
+			assert.Equal(t, tt.filters, params["fq"])
+			assert.Equal(t, tt.sort, params.Get("sort"))
+			assert.Equal(t, "10", params.Get("rows"))
+			assert.Equal(t, tt.query.Cursor, params.Get("cursorMark"))
+			assert.Equal(t, strings.Join(tt.query.ExcludeIDs, ","), params.Get("excluded_ids"))
+			if tt.query.Near != nil {
+				assert.Equal(t, "location", params.Get("sfield"))
+				assert.Equal(t, "5", params.Get("d"))
+			}
+
+			// Whatever the input, encoding and decoding gives back exactly the same parameters
+			decoded, err := url.ParseQuery(params.Encode())
+			assert.NoError(t, err)
+			assert.Equal(t, params, decoded)
+		})
+	}
+}
+
+func TestBuildSuggestParams(t *testing.T) {
+	params := repositories.BuildSuggestParams("hotel so:l", "name_prefix", 5)
+
+	assert.Equal(t, "name_prefix", params.Get("qf"))
+	assert.Equal(t, `hotel so\:l`, params.Get("q"))
+	assert.Equal(t, "AND", params.Get("q.op"))
+	assert.Equal(t, "-*", params.Get("uf"))
+	assert.Equal(t, "5", params.Get("rows"))
+
+	cities := repositories.BuildCitySuggestParams("cor", 5)
+
+	assert.Equal(t, "city_prefix", cities.Get("qf"))
+	assert.Equal(t, "cor", cities.Get("q"))
+	assert.Equal(t, "0", cities.Get("rows"))
+	assert.Equal(t, "city_exact", cities.Get("facet.field"))
+	assert.Equal(t, "5", cities.Get("facet.limit"))
+	assert.Empty(t, cities.Get("sort"))
+}
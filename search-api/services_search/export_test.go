@@ -0,0 +1,10 @@
+package services_search
+
+import "time"
+
+// SetSuggestClock reemplaza el reloj del cache de sugerencias para probar el vencimiento
+func (service Service) SetSuggestClock(now func() time.Time) {
+	service.suggestions.mu.Lock()
+	defer service.suggestions.mu.Unlock()
+	service.suggestions.now = now
+}
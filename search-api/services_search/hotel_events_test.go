@@ -0,0 +1,110 @@
+package services_search_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"search/domain_search"
+	services "search/services_search"
+)
+
+// fakeIndex records what each event did to the index
+type fakeIndex struct {
+	services.Repository
+	indexed []string
+	updated []string
+	deleted []string
+}
+
+func (f *fakeIndex) Index(ctx context.Context, hotel domain_search.HotelDto) (string, error) {
+	f.indexed = append(f.indexed, hotel.ID)
+	return hotel.ID, nil
+}
+
+func (f *fakeIndex) Update(ctx context.Context, hotel domain_search.HotelDto) error {
+	f.updated = append(f.updated, hotel.ID)
+	return nil
+}
+
+func (f *fakeIndex) Delete(ctx context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func hotelEvent(eventType string, id string) domain_search.HotelEvent {
+	return domain_search.HotelEvent{Type: eventType, EntityID: id, Version: domain_search.EventVersion}
+}
+
+func TestHandleHotelEvent(t *testing.T) {
+	hotelsAPI := fakeHotelsAPI{hotels: map[string]domain_search.HotelDto{
+		"h1":       {ID: "h1", Name: "Sheraton"},
+		"archived": {ID: "archived", Name: "Hilton", Archived: true},
+	}}
+
+	t.Run("HandleHotelEvent - Created And Updated Hotels Are Indexed", func(t *testing.T) {
+		index := &fakeIndex{}
+		service := services.NewService(index, hotelsAPI, fakeAvailability{})
+
+		require.NoError(t, service.HandleHotelEvent(hotelEvent(domain_search.EventHotelCreated, "h1")))
+		require.NoError(t, service.HandleHotelEvent(hotelEvent(domain_search.EventHotelUpdated, "h1")))
+
+		assert.Equal(t, []string{"h1"}, index.indexed)
+		assert.Equal(t, []string{"h1"}, index.updated)
+		assert.Empty(t, index.deleted)
+	})
+
+	t.Run("HandleHotelEvent - Archived Or Missing Hotels Are Removed", func(t *testing.T) {
+		for _, event := range []domain_search.HotelEvent{
+			hotelEvent(domain_search.EventHotelUpdated, "archived"),
+			hotelEvent(domain_search.EventHotelCreated, "archived"),
+			hotelEvent(domain_search.EventHotelUpdated, "gone"),
+		} {
+			index := &fakeIndex{}
+			service := services.NewService(index, hotelsAPI, fakeAvailability{})
+
+			require.NoError(t, service.HandleHotelEvent(event))
+
+			assert.Equal(t, []string{event.EntityID}, index.deleted, event.Type)
+			assert.Empty(t, index.indexed)
+			assert.Empty(t, index.updated)
+		}
+	})
+
+	t.Run("HandleHotelEvent - Deleted", func(t *testing.T) {
+		index := &fakeIndex{}
+		service := services.NewService(index, hotelsAPI, fakeAvailability{})
+
+		require.NoError(t, service.HandleHotelEvent(hotelEvent(domain_search.EventHotelDeleted, "h1")))
+
+		assert.Equal(t, []string{"h1"}, index.deleted)
+	})
+
+	t.Run("HandleHotelEvent - API Errors Are Returned For Retry", func(t *testing.T) {
+		index := &fakeIndex{}
+		down := fakeHotelsAPI{err: errors.New("failed to fetch hotel (h1): received status code 503")}
+		service := services.NewService(index, down, fakeAvailability{})
+
+		err := service.HandleHotelEvent(hotelEvent(domain_search.EventHotelUpdated, "h1"))
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, services.ErrUnsupportedEvent)
+		assert.Empty(t, index.deleted)
+		assert.Empty(t, index.updated)
+	})
+
+	t.Run("HandleHotelEvent - Unsupported Events", func(t *testing.T) {
+		service := services.NewService(&fakeIndex{}, hotelsAPI, fakeAvailability{})
+
+		for _, event := range []domain_search.HotelEvent{
+			{Type: domain_search.EventHotelUpdated, EntityID: "h1", Version: 2},
+			{Type: domain_search.EventHotelUpdated, Version: domain_search.EventVersion},
+			hotelEvent("hotel.renamed", "h1"),
+		} {
+			assert.ErrorIs(t, service.HandleHotelEvent(event), services.ErrUnsupportedEvent)
+		}
+	})
+}
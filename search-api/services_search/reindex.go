@@ -0,0 +1,210 @@
+package services_search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	hotelsDomain "search/domain_search"
+	"sync"
+	"time"
+)
+
+// Tamaño de lote por defecto y máximo: hotels-api no devuelve páginas de más de 100 (MaxPageSize)
+const (
+	defaultReindexBatchSize = 100
+	maxReindexBatchSize     = 100
+)
+
+// ErrReindexRunning se devuelve cuando se pide un reindex mientras otro está en curso
+var ErrReindexRunning = errors.New("reindex already running")
+
+// ReindexRepository son las operaciones en bloque que necesita el reindex sobre Solr
+type ReindexRepository interface {
+	IndexBatch(ctx context.Context, hotels []hotelsDomain.HotelDto) error
+	DeleteBatch(ctx context.Context, ids []string) error
+	ListIDs(ctx context.Context) ([]string, error)
+}
+
+// HotelsCatalog pagina los hoteles de hotels-api y consulta uno puntual antes de borrarlo
+type HotelsCatalog interface {
+	ListHotels(ctx context.Context, offset int, limit int) ([]hotelsDomain.HotelDto, error)
+	GetHotelByID(ctx context.Context, id string) (hotelsDomain.HotelDto, error)
+}
+
+// ReindexStatus es el progreso (o resultado) del último reindex
+type ReindexStatus struct {
+	Running    bool       `json:"running"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Pages      int        `json:"pages"`
+	Fetched    int        `json:"fetched"`
+	Indexed    int        `json:"indexed"`
+	Failed     int        `json:"failed"`
+	Deleted    int        `json:"deleted"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Reindexer recorre todo el catálogo de hotels-api y lo vuelca en Solr,
+// borrando del índice los hoteles que ya no existen.
+type Reindexer struct {
+	repository ReindexRepository
+	hotelsAPI  HotelsCatalog
+	batchSize  int
+
+	mu     sync.Mutex
+	status ReindexStatus
+}
+
+// NewReindexer inicializa y devuelve un Reindexer. batchSize se limita a lo que pagina hotels-api.
+func NewReindexer(repository ReindexRepository, hotelsAPI HotelsCatalog, batchSize int) *Reindexer {
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatchSize
+	}
+	batchSize = min(batchSize, maxReindexBatchSize)
+	return &Reindexer{
+		repository: repository,
+		hotelsAPI:  hotelsAPI,
+		batchSize:  batchSize,
+	}
+}
+
+// Status devuelve el estado del reindex en curso o del último que corrió
+func (reindexer *Reindexer) Status() ReindexStatus {
+	reindexer.mu.Lock()
+	defer reindexer.mu.Unlock()
+	return reindexer.status
+}
+
+// Start lanza el reindex en segundo plano
+func (reindexer *Reindexer) Start() (ReindexStatus, error) {
+	if err := reindexer.begin(); err != nil {
+		return reindexer.Status(), err
+	}
+	go func() {
+		_ = reindexer.run(context.Background(), nil)
+	}()
+	return reindexer.Status(), nil
+}
+
+// Run ejecuta el reindex de forma sincrónica, avisando el progreso después de cada lote
+func (reindexer *Reindexer) Run(ctx context.Context, progress func(ReindexStatus)) (ReindexStatus, error) {
+	if err := reindexer.begin(); err != nil {
+		return reindexer.Status(), err
+	}
+	err := reindexer.run(ctx, progress)
+	return reindexer.Status(), err
+}
+
+func (reindexer *Reindexer) begin() error {
+	reindexer.mu.Lock()
+	defer reindexer.mu.Unlock()
+	if reindexer.status.Running {
+		return ErrReindexRunning
+	}
+	now := time.Now()
+	reindexer.status = ReindexStatus{Running: true, StartedAt: &now}
+	return nil
+}
+
+func (reindexer *Reindexer) run(ctx context.Context, progress func(ReindexStatus)) error {
+	err := reindexer.reindex(ctx, progress)
+
+	reindexer.update(func(status *ReindexStatus) {
+		now := time.Now()
+		status.Running = false
+		status.FinishedAt = &now
+		if err != nil {
+			status.Error = err.Error()
+		}
+	})
+	if progress != nil {
+		progress(reindexer.Status())
+	}
+	return err
+}
+
+func (reindexer *Reindexer) reindex(ctx context.Context, progress func(ReindexStatus)) error {
+	upstream := make(map[string]bool)
+
+	for offset := 0; ; offset += reindexer.batchSize {
+		hotels, err := reindexer.hotelsAPI.ListHotels(ctx, offset, reindexer.batchSize)
+		if err != nil {
+			// Sin el catálogo completo no podemos saber qué sobra, así que no se borra nada
+			return fmt.Errorf("error fetching hotels (offset %d): %w", offset, err)
+		}
+
+		batch := make([]hotelsDomain.HotelDto, 0, len(hotels))
+		for _, hotel := range hotels {
+			if hotel.ID == "" || upstream[hotel.ID] {
+				continue
+			}
+			upstream[hotel.ID] = true
+			batch = append(batch, hotel)
+		}
+
+		indexErr := reindexer.repository.IndexBatch(ctx, batch)
+		reindexer.update(func(status *ReindexStatus) {
+			status.Pages++
+			status.Fetched += len(batch)
+			if indexErr != nil {
+				status.Failed += len(batch)
+			} else {
+				status.Indexed += len(batch)
+			}
+		})
+		if indexErr != nil {
+			fmt.Printf("Error indexing hotels batch (offset %d): %v\n", offset, indexErr)
+		}
+		if progress != nil {
+			progress(reindexer.Status())
+		}
+
+		// Última página, o hotels-api ignoró la paginación y ya no hay nada nuevo
+		if len(hotels) < reindexer.batchSize || len(batch) == 0 {
+			break
+		}
+	}
+
+	indexed, err := reindexer.repository.ListIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing indexed hotels: %w", err)
+	}
+	orphans := make([]string, 0)
+	for _, id := range indexed {
+		if !upstream[id] && reindexer.gone(ctx, id) {
+			orphans = append(orphans, id)
+		}
+	}
+	for start := 0; start < len(orphans); start += reindexer.batchSize {
+		end := min(start+reindexer.batchSize, len(orphans))
+		if err := reindexer.repository.DeleteBatch(ctx, orphans[start:end]); err != nil {
+			return fmt.Errorf("error deleting orphan hotels: %w", err)
+		}
+		reindexer.update(func(status *ReindexStatus) {
+			status.Deleted += end - start
+		})
+	}
+
+	return nil
+}
+
+// gone confirma con hotels-api que un documento que no vino en el listado sobra. Un hotel creado
+// mientras corría el reindex (y ya indexado por su evento) no aparece en las páginas leídas antes,
+// así que solo se borra si hotels-api dice que no existe o que está archivado.
+func (reindexer *Reindexer) gone(ctx context.Context, id string) bool {
+	hotel, err := reindexer.hotelsAPI.GetHotelByID(ctx, id)
+	if errors.Is(err, hotelsDomain.ErrHotelNotFound) {
+		return true
+	}
+	if err != nil {
+		fmt.Printf("Error checking orphan hotel %s, keeping it: %v\n", id, err)
+		return false
+	}
+	return hotel.Archived
+}
+
+func (reindexer *Reindexer) update(apply func(status *ReindexStatus)) {
+	reindexer.mu.Lock()
+	defer reindexer.mu.Unlock()
+	apply(&reindexer.status)
+}
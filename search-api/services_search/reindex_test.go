@@ -0,0 +1,207 @@
+package services_search_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"search/domain_search"
+	services "search/services_search"
+)
+
+// fakeCatalog pages hotels like hotels-api GET /hotels, rejecting pages larger than 100.
+// GetHotelByID also finds the hotels in late (created after the listing) and returns
+// lookupErr for any other id when set.
+type fakeCatalog struct {
+	hotels    []domain_search.HotelDto
+	late      []domain_search.HotelDto
+	lookupErr error
+	limits    []int
+	failAt    int // offset that fails, -1 for none
+}
+
+func newFakeCatalog(count int) *fakeCatalog {
+	catalog := &fakeCatalog{failAt: -1}
+	for i := 0; i < count; i++ {
+		catalog.hotels = append(catalog.hotels, domain_search.HotelDto{ID: fmt.Sprintf("h%03d", i)})
+	}
+	return catalog
+}
+
+func (f *fakeCatalog) ListHotels(ctx context.Context, offset int, limit int) ([]domain_search.HotelDto, error) {
+	f.limits = append(f.limits, limit)
+	if limit > 100 {
+		return nil, errors.New("failed to fetch hotels page: received status code 400")
+	}
+	if offset == f.failAt {
+		return nil, errors.New("failed to fetch hotels page: received status code 500")
+	}
+	if offset >= len(f.hotels) {
+		return []domain_search.HotelDto{}, nil
+	}
+	return f.hotels[offset:min(offset+limit, len(f.hotels))], nil
+}
+
+func (f *fakeCatalog) GetHotelByID(ctx context.Context, id string) (domain_search.HotelDto, error) {
+	for _, hotels := range [][]domain_search.HotelDto{f.hotels, f.late} {
+		for _, hotel := range hotels {
+			if hotel.ID == id {
+				return hotel, nil
+			}
+		}
+	}
+	if f.lookupErr != nil {
+		return domain_search.HotelDto{}, f.lookupErr
+	}
+	return domain_search.HotelDto{}, fmt.Errorf("%w: %s", domain_search.ErrHotelNotFound, id)
+}
+
+// fakeSolr keeps the indexed ids in memory
+type fakeSolr struct {
+	mu      sync.Mutex
+	indexed map[string]bool
+	deleted []string
+}
+
+func newFakeSolr(ids ...string) *fakeSolr {
+	solr := &fakeSolr{indexed: make(map[string]bool)}
+	for _, id := range ids {
+		solr.indexed[id] = true
+	}
+	return solr
+}
+
+func (f *fakeSolr) IndexBatch(ctx context.Context, hotels []domain_search.HotelDto) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, hotel := range hotels {
+		f.indexed[hotel.ID] = true
+	}
+	return nil
+}
+
+func (f *fakeSolr) DeleteBatch(ctx context.Context, ids []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range ids {
+		delete(f.indexed, id)
+		f.deleted = append(f.deleted, id)
+	}
+	return nil
+}
+
+func (f *fakeSolr) ListIDs(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.indexed))
+	for id := range f.indexed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func TestReindexer(t *testing.T) {
+	t.Run("Run - Indexes Every Page And Deletes Stale Documents", func(t *testing.T) {
+		catalog := newFakeCatalog(250)
+		solr := newFakeSolr("h001", "stale-1", "stale-2")
+		reindexer := services.NewReindexer(solr, catalog, 100)
+
+		status, err := reindexer.Run(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{100, 100, 100}, catalog.limits)
+		assert.Equal(t, 3, status.Pages)
+		assert.Equal(t, 250, status.Fetched)
+		assert.Equal(t, 250, status.Indexed)
+		assert.Equal(t, 2, status.Deleted)
+		assert.False(t, status.Running)
+		assert.ElementsMatch(t, []string{"stale-1", "stale-2"}, solr.deleted)
+		assert.Len(t, solr.indexed, 250)
+		assert.True(t, solr.indexed["h249"])
+	})
+
+	t.Run("Run - Batch Size Is Capped At The hotels-api Page Size", func(t *testing.T) {
+		catalog := newFakeCatalog(150)
+		solr := newFakeSolr()
+		reindexer := services.NewReindexer(solr, catalog, 500)
+
+		status, err := reindexer.Run(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{100, 100}, catalog.limits)
+		assert.Equal(t, 150, status.Indexed)
+	})
+
+	t.Run("Run - Exact Multiple Of The Batch Size", func(t *testing.T) {
+		catalog := newFakeCatalog(20)
+		solr := newFakeSolr()
+		reindexer := services.NewReindexer(solr, catalog, 10)
+
+		status, err := reindexer.Run(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, status.Pages) // the third page comes back empty
+		assert.Equal(t, 20, status.Indexed)
+	})
+
+	t.Run("Run - Nothing Is Deleted When The Catalog Cannot Be Read", func(t *testing.T) {
+		catalog := newFakeCatalog(30)
+		catalog.failAt = 20
+		solr := newFakeSolr("stale-1")
+		reindexer := services.NewReindexer(solr, catalog, 10)
+
+		status, err := reindexer.Run(context.Background(), nil)
+
+		assert.Error(t, err)
+		assert.Contains(t, status.Error, "offset 20")
+		assert.Empty(t, solr.deleted)
+		assert.True(t, solr.indexed["stale-1"])
+	})
+
+	t.Run("Run - Hotels Created During The Reindex Are Kept", func(t *testing.T) {
+		catalog := newFakeCatalog(15)
+		catalog.late = []domain_search.HotelDto{{ID: "created-late"}, {ID: "archived-late", Archived: true}}
+		solr := newFakeSolr("created-late", "archived-late", "stale-1")
+		reindexer := services.NewReindexer(solr, catalog, 10)
+
+		status, err := reindexer.Run(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.Deleted)
+		assert.ElementsMatch(t, []string{"archived-late", "stale-1"}, solr.deleted)
+		assert.True(t, solr.indexed["created-late"])
+	})
+
+	t.Run("Run - Orphans Are Kept When hotels-api Cannot Confirm Them", func(t *testing.T) {
+		catalog := newFakeCatalog(5)
+		catalog.lookupErr = errors.New("failed to fetch hotel: received status code 503")
+		solr := newFakeSolr("stale-1")
+		reindexer := services.NewReindexer(solr, catalog, 10)
+
+		status, err := reindexer.Run(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.Deleted)
+		assert.True(t, solr.indexed["stale-1"])
+	})
+
+	t.Run("Run - Reports Progress After Every Batch", func(t *testing.T) {
+		catalog := newFakeCatalog(25)
+		reindexer := services.NewReindexer(newFakeSolr(), catalog, 10)
+
+		indexed := make([]int, 0)
+		_, err := reindexer.Run(context.Background(), func(status services.ReindexStatus) {
+			indexed = append(indexed, status.Indexed)
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{10, 20, 25, 25}, indexed)
+	})
+}
@@ -1,212 +1,173 @@
 package services_search
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	hotelsDomain "search/domain_search"
+	"sync"
+	"time"
 )
 
+// ErrUnsupportedEvent indica un evento que no sabemos procesar (versión o tipo desconocido)
+var ErrUnsupportedEvent = errors.New("unsupported event")
+
 // Repository define las funciones que el repositorio debe implementar
 type Repository interface {
-	Index(ctx context.Context, curso hotelsDomain.CourseDto) (string, error)
-	Update(ctx context.Context, curso hotelsDomain.CourseDto) error
-	Search(ctx context.Context, query string, limit int, offset int) ([]hotelsDomain.CourseDto, error)
+	Index(ctx context.Context, hotel hotelsDomain.HotelDto) (string, error)
+	Update(ctx context.Context, hotel hotelsDomain.HotelDto) error
+	Delete(ctx context.Context, id string) error
+	Search(ctx context.Context, query hotelsDomain.SearchQuery) (hotelsDomain.SearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) (hotelsDomain.SuggestionsDto, error)
 }
 
-// ExternalRepository define el método para obtener un curso de la API externa
+// ExternalRepository define los métodos para obtener un hotel y cotizar una estadía en la API externa
 type ExternalRepository interface {
-	GetCourseByID(ctx context.Context, id string) (hotelsDomain.CourseDto, error)
+	GetHotelByID(ctx context.Context, id string) (hotelsDomain.HotelDto, error)
+	Quote(ctx context.Context, hotelID string, checkIn time.Time, checkOut time.Time, guests int) (hotelsDomain.Quote, error)
+}
+
+// Tiempo máximo para cotizar una página de resultados y cuántas cotizaciones se piden a la vez
+const (
+	quoteTimeout = 2 * time.Second
+	quoteWorkers = 10
+)
+
+// AvailabilityRepository consulta en reservations-api qué hoteles no tienen lugar en un rango de fechas
+type AvailabilityRepository interface {
+	Unavailable(ctx context.Context, checkIn time.Time, checkOut time.Time, guests int) ([]string, error)
 }
 
 // Service estructura que maneja la lógica del servicio
 type Service struct {
-	repository Repository
-	hotelsAPI  ExternalRepository
+	repository      Repository
+	hotelsAPI       ExternalRepository
+	reservationsAPI AvailabilityRepository
+	suggestions     *suggestCache
 }
 
 // NewService inicializa y devuelve un Service
-func NewService(repository Repository, hotelsAPI ExternalRepository) Service {
+func NewService(repository Repository, hotelsAPI ExternalRepository, reservationsAPI AvailabilityRepository) Service {
 	return Service{
-		repository: repository,
-		hotelsAPI:  hotelsAPI,
-	}
-}
-
-// Search realiza una búsqueda de hotels
-func (service Service) Search(ctx context.Context, query string, offset int, limit int) ([]hotelsDomain.CourseDto, error) {
-	hotelsDAOList, err := service.repository.Search(ctx, query, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("error searching courses: %w", err)
-	}
-
-	// Convertir hotelsDAOList a hotelsDomainList
-	hotelsDomainList := make([]hotelsDomain.CourseDto, 0)
-	for _, curso := range hotelsDAOList {
-		hotelsDomainList = append(hotelsDomainList, hotelsDomain.CourseDto{
-			Course_id:    curso.Course_id,
-			Nombre:       curso.Nombre,
-			Profesor_id:  curso.Profesor_id,
-			Categoria:    curso.Categoria,
-			Descripcion:  curso.Descripcion,
-			Valoracion:   curso.Valoracion,
-			Duracion:     curso.Duracion,
-			Requisitos:   curso.Requisitos,
-			Url_image:    curso.Url_image,
-			Fecha_inicio: curso.Fecha_inicio,
-			Capacidad:    curso.Capacidad,
-		})
+		repository:      repository,
+		hotelsAPI:       hotelsAPI,
+		reservationsAPI: reservationsAPI,
+		suggestions:     newSuggestCache(),
 	}
-
-	return hotelsDomainList, nil
 }
 
-// HandleCourseNew maneja la creación o actualización de hotels
-func (service Service) HandleCourseNew(cursoNew hotelsDomain.CourseNew) {
-	switch cursoNew.Operation {
-	case "CREATE", "UPDATE":
-		curso, err := service.hotelsAPI.GetCourseByID(context.Background(), cursoNew.Curso_id)
+// Search realiza una búsqueda de hotels con filtros, facetas y ordenamiento. Con fechas, los hoteles
+// completos se excluyen en la misma consulta a Solr, así el total, las facetas y el cursor son los
+// de los hoteles disponibles. Si reservations-api no responde, los hoteles se muestran igual (sin
+// "available") en lugar de vaciar la búsqueda.
+func (service Service) Search(ctx context.Context, query hotelsDomain.SearchQuery) (hotelsDomain.SearchResult, error) {
+	availabilityKnown := false
+	if query.HasDates() {
+		unavailable, err := service.reservationsAPI.Unavailable(ctx, query.CheckIn, query.CheckOut, query.Guests)
 		if err != nil {
-			fmt.Printf("Error getting course (%s) from API: %v\n", cursoNew.Curso_id, err)
-			return
-		}
-
-		cursoDAO := hotelsDomain.CourseDto{
-			Course_id:    curso.Course_id,
-			Nombre:       curso.Nombre,
-			Profesor_id:  curso.Profesor_id,
-			Categoria:    curso.Categoria,
-			Descripcion:  curso.Descripcion,
-			Valoracion:   curso.Valoracion,
-			Duracion:     curso.Duracion,
-			Requisitos:   curso.Requisitos,
-			Fecha_inicio: curso.Fecha_inicio,
-			Url_image:    curso.Url_image,
-			Capacidad:    curso.Capacidad,
-		}
-
-		if cursoNew.Operation == "CREATE" {
-			if _, err := service.repository.Index(context.Background(), cursoDAO); err != nil {
-				fmt.Printf("Error indexing course (%s): %v\n", cursoNew.Curso_id, err)
-			} else {
-				fmt.Println("Course indexed successfully:", cursoNew.Curso_id)
-			}
+			fmt.Printf("Error checking availability: %v\n", err)
 		} else {
-			if err := service.repository.Update(context.Background(), cursoDAO); err != nil {
-				fmt.Printf("Error updating course (%s): %v\n", cursoNew.Curso_id, err)
-			} else {
-				fmt.Println("Course updated successfully:", cursoNew.Curso_id)
-			}
+			query.ExcludeIDs = unavailable
+			availabilityKnown = true
 		}
-
-	default:
-		fmt.Printf("Unknown operation: %s\n", cursoNew.Operation)
-	}
-}
-
-// SolrRepository define el repositorio para interactuar con Solr
-type SolrRepository struct {
-	solrURL string
-}
-
-// NewSolrRepository inicializa y devuelve un SolrRepository
-func NewSolrRepository(solrHost, solrPort, coreName string) *SolrRepository {
-	solrURL := fmt.Sprintf("http://%s:%s/solr/%s/update?commit=true", solrHost, solrPort, coreName)
-	return &SolrRepository{solrURL: solrURL}
-}
-
-// Index envía un documento de curso a Solr para indexación
-func (repo *SolrRepository) Index(ctx context.Context, curso hotelsDomain.CourseDto) (string, error) {
-	doc := map[string]interface{}{
-		"course_id":    curso.Course_id,
-		"nombre":       curso.Nombre,
-		"profesor_id":  curso.Profesor_id,
-		"categoria":    curso.Categoria,
-		"descripcion":  curso.Descripcion,
-		"valoracion":   curso.Valoracion,
-		"duracion":     curso.Duracion,
-		"requisitos":   curso.Requisitos,
-		"url_image":    curso.Url_image,
-		"fecha_inicio": curso.Fecha_inicio,
-		"capacidad":    curso.Capacidad,
 	}
 
-	indexRequest := map[string]interface{}{
-		"add": []interface{}{doc},
-	}
-
-	jsonData, err := json.Marshal(indexRequest)
+	result, err := service.repository.Search(ctx, query)
 	if err != nil {
-		return "", fmt.Errorf("error marshaling course data to JSON: %w", err)
+		return hotelsDomain.SearchResult{}, fmt.Errorf("error searching hotels: %w", err)
 	}
+	result.Offset = query.Offset
+	result.Limit = query.Limit
 
-	req, err := http.NewRequestWithContext(ctx, "POST", repo.solrURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("error creating HTTP request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error sending request to Solr: %w", err)
+	if query.HasDates() {
+		nights := query.Nights()
+		for i := range result.Hotels {
+			if availabilityKnown {
+				available := true
+				result.Hotels[i].Available = &available
+			}
+			result.Hotels[i].Nights = nights
+		}
+		service.priceStay(ctx, query, result.Hotels)
 	}
-	defer resp.Body.Close()
+	return result, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error indexing course in Solr, status code: %d", resp.StatusCode)
+// priceStay cotiza la estadía de cada hotel de la página en hotels-api, en paralelo. Un hotel cuya
+// cotización falla (o no llega a tiempo) queda sin total en lugar de mostrar un precio inventado.
+func (service Service) priceStay(ctx context.Context, query hotelsDomain.SearchQuery, hotels hotelsDomain.HotelsDto) {
+	ctx, cancel := context.WithTimeout(ctx, quoteTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, quoteWorkers)
+	for i := range hotels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			quote, err := service.hotelsAPI.Quote(ctx, hotels[i].ID, query.CheckIn, query.CheckOut, query.Guests)
+			if err != nil {
+				fmt.Printf("Error quoting hotel (%s): %v\n", hotels[i].ID, err)
+				return
+			}
+			hotels[i].TotalPrice = quote.Total
+			hotels[i].Currency = quote.Currency
+		}()
 	}
-
-	return curso.Course_id, nil
+	wg.Wait()
 }
 
-// Update modifica un documento de curso existente en la colección de Solr
-func (repo *SolrRepository) Update(ctx context.Context, curso hotelsDomain.CourseDto) error {
-	doc := map[string]interface{}{
-		"course_id":    curso.Course_id,
-		"nombre":       curso.Nombre,
-		"profesor_id":  curso.Profesor_id,
-		"categoria":    curso.Categoria,
-		"descripcion":  curso.Descripcion,
-		"valoracion":   curso.Valoracion,
-		"duracion":     curso.Duracion,
-		"requisitos":   curso.Requisitos,
-		"url_image":    curso.Url_image,
-		"fecha_inicio": curso.Fecha_inicio,
-		"capacidad":    curso.Capacidad,
+// HandleHotelEvent aplica en el índice un evento publicado por hotels-api.
+// Devuelve ErrUnsupportedEvent si la versión o el tipo no se reconocen.
+func (service Service) HandleHotelEvent(event hotelsDomain.HotelEvent) error {
+	if event.Version != hotelsDomain.EventVersion {
+		return fmt.Errorf("%w: version %d", ErrUnsupportedEvent, event.Version)
 	}
-
-	updateRequest := map[string]interface{}{
-		"add": []interface{}{doc},
+	if event.EntityID == "" {
+		return fmt.Errorf("%w: missing entity id", ErrUnsupportedEvent)
 	}
 
-	// Marshal the update request to JSON
-	body, err := json.Marshal(updateRequest)
-	if err != nil {
-		return fmt.Errorf("error marshaling course document: %w", err)
-	}
+	ctx := context.Background()
+	switch event.Type {
+	case hotelsDomain.EventHotelCreated, hotelsDomain.EventHotelUpdated:
+		hotel, err := service.hotelsAPI.GetHotelByID(ctx, event.EntityID)
+		// hotels-api still returns archived hotels by id; those (and deleted ones) must leave the
+		// index even if this event arrived after the hotel.deleted one
+		if errors.Is(err, hotelsDomain.ErrHotelNotFound) || (err == nil && hotel.Archived) {
+			return service.deleteHotel(ctx, event.EntityID)
+		}
+		if err != nil {
+			return fmt.Errorf("error getting hotel (%s) from API: %w", event.EntityID, err)
+		}
 
-	// Create a new HTTP request with the body
-	req, err := http.NewRequestWithContext(ctx, "POST", repo.solrURL, bytes.NewBuffer(body))
-	if err != nil {
-		return fmt.Errorf("error creating HTTP request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
+		if event.Type == hotelsDomain.EventHotelCreated {
+			if _, err := service.repository.Index(ctx, hotel); err != nil {
+				return fmt.Errorf("error indexing hotel (%s): %w", event.EntityID, err)
+			}
+			fmt.Println("Hotel indexed successfully:", event.EntityID)
+			return nil
+		}
+		if err := service.repository.Update(ctx, hotel); err != nil {
+			return fmt.Errorf("error updating hotel (%s): %w", event.EntityID, err)
+		}
+		fmt.Println("Hotel updated successfully:", event.EntityID)
+		return nil
 
-	// Execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("error updating course: %w", err)
-	}
-	defer resp.Body.Close()
+	case hotelsDomain.EventHotelDeleted:
+		return service.deleteHotel(ctx, event.EntityID)
 
-	// Check the response status
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to update course in Solr, status code: %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: type %q", ErrUnsupportedEvent, event.Type)
 	}
+}
 
+func (service Service) deleteHotel(ctx context.Context, id string) error {
+	if err := service.repository.Delete(ctx, id); err != nil {
+		return fmt.Errorf("error deleting hotel (%s): %w", id, err)
+	}
+	fmt.Println("Hotel deleted successfully:", id)
 	return nil
 }
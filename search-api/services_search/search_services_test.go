@@ -0,0 +1,131 @@
+package services_search_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"search/domain_search"
+	services "search/services_search"
+)
+
+// fakeSearchRepository answers every search with the same page and remembers the last query
+type fakeSearchRepository struct {
+	services.Repository
+	result domain_search.SearchResult
+	query  domain_search.SearchQuery
+}
+
+func (f *fakeSearchRepository) Search(ctx context.Context, query domain_search.SearchQuery) (domain_search.SearchResult, error) {
+	f.query = query
+	result := f.result
+	result.Hotels = append(domain_search.HotelsDto{}, f.result.Hotels...)
+	return result, nil
+}
+
+// fakeHotelsAPI quotes the hotels in quotes and fails for the rest. GetHotelByID answers with
+// hotels, err, or ErrHotelNotFound.
+type fakeHotelsAPI struct {
+	quotes map[string]domain_search.Quote
+	hotels map[string]domain_search.HotelDto
+	err    error
+}
+
+func (f fakeHotelsAPI) GetHotelByID(ctx context.Context, id string) (domain_search.HotelDto, error) {
+	if f.err != nil {
+		return domain_search.HotelDto{}, f.err
+	}
+	hotel, ok := f.hotels[id]
+	if !ok {
+		return domain_search.HotelDto{}, fmt.Errorf("%w: %s", domain_search.ErrHotelNotFound, id)
+	}
+	return hotel, nil
+}
+
+func (f fakeHotelsAPI) Quote(ctx context.Context, hotelID string, checkIn time.Time, checkOut time.Time, guests int) (domain_search.Quote, error) {
+	quote, ok := f.quotes[hotelID]
+	if !ok {
+		return domain_search.Quote{}, errors.New("failed to fetch quote: received status code 400")
+	}
+	return quote, nil
+}
+
+type fakeAvailability struct {
+	unavailable []string
+	err         error
+}
+
+func (f fakeAvailability) Unavailable(ctx context.Context, checkIn time.Time, checkOut time.Time, guests int) ([]string, error) {
+	return f.unavailable, f.err
+}
+
+func TestSearch(t *testing.T) {
+	checkIn := time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)
+	page := domain_search.SearchResult{
+		Total:  1,
+		Hotels: domain_search.HotelsDto{{ID: "h1", PricePerNight: 100}},
+	}
+	hotelsAPI := fakeHotelsAPI{quotes: map[string]domain_search.Quote{"h1": {Total: 230, Currency: "ARS"}}}
+	stay := domain_search.SearchQuery{Limit: 10, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 2}
+
+	t.Run("Search - Booked Hotels Are Excluded In The Query", func(t *testing.T) {
+		repo := &fakeSearchRepository{result: page}
+		service := services.NewService(repo, hotelsAPI, fakeAvailability{unavailable: []string{"h2", "h3"}})
+
+		result, err := service.Search(context.Background(), stay)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"h2", "h3"}, repo.query.ExcludeIDs)
+		assert.Equal(t, 1, result.Total)
+		require.Len(t, result.Hotels, 1)
+		require.NotNil(t, result.Hotels[0].Available)
+		assert.True(t, *result.Hotels[0].Available)
+		assert.Equal(t, 2, result.Hotels[0].Nights)
+	})
+
+	t.Run("Search - Total Price Comes From The Quote", func(t *testing.T) {
+		repo := &fakeSearchRepository{result: domain_search.SearchResult{
+			Total:  2,
+			Hotels: domain_search.HotelsDto{{ID: "h1", PricePerNight: 100}, {ID: "min-stay", PricePerNight: 100}},
+		}}
+		service := services.NewService(repo, hotelsAPI, fakeAvailability{})
+
+		result, err := service.Search(context.Background(), stay)
+
+		require.NoError(t, err)
+		require.Len(t, result.Hotels, 2)
+		assert.Equal(t, 230.0, result.Hotels[0].TotalPrice)
+		assert.Equal(t, "ARS", result.Hotels[0].Currency)
+		assert.Zero(t, result.Hotels[1].TotalPrice)
+		assert.Equal(t, 2, result.Hotels[1].Nights)
+	})
+
+	t.Run("Search - Reservations API Down Leaves Availability Unknown", func(t *testing.T) {
+		repo := &fakeSearchRepository{result: page}
+		service := services.NewService(repo, hotelsAPI, fakeAvailability{err: errors.New("timeout")})
+
+		result, err := service.Search(context.Background(), stay)
+
+		require.NoError(t, err)
+		assert.Empty(t, repo.query.ExcludeIDs)
+		require.Len(t, result.Hotels, 1)
+		assert.Nil(t, result.Hotels[0].Available)
+	})
+
+	t.Run("Search - No Dates No Availability", func(t *testing.T) {
+		repo := &fakeSearchRepository{result: page}
+		service := services.NewService(repo, hotelsAPI, fakeAvailability{unavailable: []string{"h1"}})
+
+		result, err := service.Search(context.Background(), domain_search.SearchQuery{Limit: 10})
+
+		require.NoError(t, err)
+		assert.Empty(t, repo.query.ExcludeIDs)
+		assert.Nil(t, result.Hotels[0].Available)
+		assert.Zero(t, result.Hotels[0].Nights)
+	})
+}
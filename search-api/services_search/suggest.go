@@ -0,0 +1,85 @@
+package services_search
+
+import (
+	"context"
+	"fmt"
+	hotelsDomain "search/domain_search"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	suggestTTL        = 30 * time.Second
+	suggestTimeout    = 300 * time.Millisecond
+	suggestCacheLimit = 1000
+)
+
+// suggestCache guarda las sugerencias por prefijo unos segundos: mientras el usuario tipea,
+// el frontend repite las mismas consultas muchas veces
+type suggestCache struct {
+	mu      sync.Mutex
+	entries map[string]suggestEntry
+	now     func() time.Time
+}
+
+type suggestEntry struct {
+	suggestions hotelsDomain.SuggestionsDto
+	expiresAt   time.Time
+}
+
+func newSuggestCache() *suggestCache {
+	return &suggestCache{entries: make(map[string]suggestEntry), now: time.Now}
+}
+
+func (cache *suggestCache) get(key string) (hotelsDomain.SuggestionsDto, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, ok := cache.entries[key]
+	if !ok || cache.now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.suggestions, true
+}
+
+func (cache *suggestCache) set(key string, suggestions hotelsDomain.SuggestionsDto) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	now := cache.now()
+	if len(cache.entries) >= suggestCacheLimit {
+		for k, entry := range cache.entries {
+			if now.After(entry.expiresAt) {
+				delete(cache.entries, k)
+			}
+		}
+		if len(cache.entries) >= suggestCacheLimit {
+			cache.entries = make(map[string]suggestEntry)
+		}
+	}
+	cache.entries[key] = suggestEntry{suggestions: suggestions, expiresAt: now.Add(suggestTTL)}
+}
+
+// Suggest devuelve ciudades y hoteles que empiezan con el prefijo. Si Solr no contesta a tiempo
+// devuelve una lista vacía: el autocompletado nunca debe trabar la búsqueda.
+func (service Service) Suggest(ctx context.Context, prefix string, limit int) hotelsDomain.SuggestionsDto {
+	prefix = strings.Join(strings.Fields(strings.ToLower(prefix)), " ")
+	if prefix == "" {
+		return hotelsDomain.SuggestionsDto{}
+	}
+
+	key := fmt.Sprintf("%d:%s", limit, prefix)
+	if suggestions, ok := service.suggestions.get(key); ok {
+		return suggestions
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, suggestTimeout)
+	defer cancel()
+	suggestions, err := service.repository.Suggest(ctx, prefix, limit)
+	if err != nil {
+		fmt.Printf("Error getting suggestions for %q: %v\n", prefix, err)
+		return hotelsDomain.SuggestionsDto{}
+	}
+
+	service.suggestions.set(key, suggestions)
+	return suggestions
+}
@@ -0,0 +1,127 @@
+package services_search_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"search/domain_search"
+	services "search/services_search"
+)
+
+// fakeSuggestRepository counts the Suggest calls. With block it waits for the context to be
+// cancelled, like a Solr that does not answer.
+type fakeSuggestRepository struct {
+	services.Repository
+	mu    sync.Mutex
+	calls int
+	err   error
+	block bool
+}
+
+func (f *fakeSuggestRepository) Suggest(ctx context.Context, prefix string, limit int) (domain_search.SuggestionsDto, error) {
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return domain_search.SuggestionsDto{{Text: "Córdoba", Type: "city", Count: 3}}, nil
+}
+
+func TestSuggest(t *testing.T) {
+	type call struct {
+		prefix string
+		limit  int
+	}
+	t.Run("Suggest - Cache", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			first     call
+			second    call
+			after     time.Duration
+			wantCalls int
+		}{
+			{"same prefix is cached", call{"cor", 5}, call{"cor", 5}, time.Second, 1},
+			{"prefix is normalized", call{"  Cor  ", 5}, call{"COR", 5}, 0, 1},
+			{"inner spaces are collapsed", call{"san  luis", 5}, call{"San Luis", 5}, 0, 1},
+			{"limit is part of the key", call{"cor", 5}, call{"cor", 10}, 0, 2},
+			{"other prefix", call{"cor", 5}, call{"cord", 5}, 0, 2},
+			{"still fresh before the TTL", call{"cor", 5}, call{"cor", 5}, 30 * time.Second, 1},
+			{"expired after the TTL", call{"cor", 5}, call{"cor", 5}, 30*time.Second + time.Millisecond, 2},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := &fakeSuggestRepository{}
+				service := services.NewService(repo, fakeHotelsAPI{}, fakeAvailability{})
+				now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+				service.SetSuggestClock(func() time.Time { return now })
+
+				first := service.Suggest(context.Background(), tt.first.prefix, tt.first.limit)
+				now = now.Add(tt.after)
+				second := service.Suggest(context.Background(), tt.second.prefix, tt.second.limit)
+
+				assert.Equal(t, tt.wantCalls, repo.calls)
+				assert.Len(t, first, 1)
+				assert.Equal(t, first, second)
+			})
+		}
+	})
+
+	t.Run("Suggest - Failures Return An Empty List And Are Not Cached", func(t *testing.T) {
+		tests := []struct {
+			name string
+			repo *fakeSuggestRepository
+		}{
+			{"solr error", &fakeSuggestRepository{err: errors.New("solr returned status 500")}},
+			{"solr does not answer in 300ms", &fakeSuggestRepository{block: true}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				service := services.NewService(tt.repo, fakeHotelsAPI{}, fakeAvailability{})
+
+				start := time.Now()
+				suggestions := service.Suggest(context.Background(), "cor", 5)
+				elapsed := time.Since(start)
+
+				require.NotNil(t, suggestions)
+				assert.Empty(t, suggestions)
+				assert.Less(t, elapsed, time.Second)
+
+				service.Suggest(context.Background(), "cor", 5)
+				assert.Equal(t, 2, tt.repo.calls)
+			})
+		}
+	})
+
+	t.Run("Suggest - Timeout Is 300ms", func(t *testing.T) {
+		repo := &fakeSuggestRepository{block: true}
+		service := services.NewService(repo, fakeHotelsAPI{}, fakeAvailability{})
+
+		start := time.Now()
+		service.Suggest(context.Background(), "cor", 5)
+		elapsed := time.Since(start)
+
+		assert.GreaterOrEqual(t, elapsed, 300*time.Millisecond)
+		assert.Less(t, elapsed, time.Second)
+	})
+
+	t.Run("Suggest - Empty Prefix Does Not Query Solr", func(t *testing.T) {
+		repo := &fakeSuggestRepository{}
+		service := services.NewService(repo, fakeHotelsAPI{}, fakeAvailability{})
+
+		for _, prefix := range []string{"", "   "} {
+			assert.Empty(t, service.Suggest(context.Background(), prefix, 5))
+		}
+		assert.Zero(t, repo.calls)
+	})
+}
@@ -157,7 +157,7 @@ func TestService(t *testing.T) {
 		memcachedRepo.On("CreateUser", mockUser).Return(int64(1), nil).Maybe()
 
 		// Configurar el mock para la generación del token
-		tokenizer.On("GenerateToken", email, int64(1)).Return("token", nil).Once()
+		tokenizer.On("GenerateToken", email, int64(1), false).Return("token", nil).Once()
 
 		// Ejecutar el método bajo prueba
 		response, err := usersService.Login(email, password)
@@ -238,7 +238,7 @@ func TestService(t *testing.T) {
 		mainRepo.On("GetUserByEmail", email).Return(mockUser, nil).Once()
 
 		// Configurar el mock para la generación del token con un error
-		tokenizer.On("GenerateToken", email, int64(1)).Return("", errors.New("token error")).Once()
+		tokenizer.On("GenerateToken", email, int64(1), false).Return("", errors.New("token error")).Once()
 
 		// Ejecutar el método bajo prueba
 		response, err := usersService.Login(email, password)
@@ -17,7 +17,7 @@ type Repository interface {
 }
 
 type Tokenizer interface {
-	GenerateToken(username string, userID int64) (string, error)
+	GenerateToken(username string, userID int64, admin bool) (string, error)
 }
 
 type Service struct {
@@ -127,7 +127,7 @@ func (service Service) Login(email string, password string) (domain.LoginRespons
 		return domain.LoginResponse{}, fmt.Errorf("invalid credentials")
 	}
 
-	token, err := service.tokenizer.GenerateToken(user.Email, user.User_id)
+	token, err := service.tokenizer.GenerateToken(user.Email, user.User_id, user.Admin)
 	if err != nil {
 		return domain.LoginResponse{}, fmt.Errorf("error generating token: %w", err)
 	}
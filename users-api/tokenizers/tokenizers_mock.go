@@ -10,7 +10,7 @@ func NewMock() *Mock {
 	return &Mock{}
 }
 
-func (m *Mock) GenerateToken(Email string, User_id int64) (string, error) {
-	args := m.Called(Email, User_id)
+func (m *Mock) GenerateToken(Email string, User_id int64, Admin bool) (string, error) {
+	args := m.Called(Email, User_id, Admin)
 	return args.String(0), args.Error(1)
 }